@@ -6,9 +6,13 @@ import (
 	"fmt"
 	"gw-currency-wallet/config"
 	"gw-currency-wallet/internal/handler"
+	"gw-currency-wallet/internal/mailer"
 	gw_grpc "gw-currency-wallet/internal/pb/exchange"
 	"gw-currency-wallet/internal/repository"
+	"gw-currency-wallet/internal/requestid"
 	"gw-currency-wallet/internal/service"
+	"gw-currency-wallet/internal/tracing"
+	"gw-currency-wallet/pkg/metrics"
 	"net/http"
 	"os"
 	"os/signal"
@@ -21,6 +25,8 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 )
 
+const metricsNamespace = "gw_wallet"
+
 func init() {
 	zap.ReplaceGlobals(zap.Must(zap.NewProduction()))
 }
@@ -29,6 +35,12 @@ func main() {
 	cfg := config.LoadConfig()
 
 	ctx := context.Background()
+
+	shutdownTracing, err := tracing.Setup(ctx, &cfg.Tracing)
+	if err != nil {
+		zap.L().Fatal(err.Error())
+	}
+
 	dsn := fmt.Sprintf(
 		"postgres://%s:%s@%s:%d/%s",
 		cfg.Database.User,
@@ -38,21 +50,43 @@ func main() {
 		cfg.Database.Name,
 	)
 
-	pool, err := pgxpool.New(ctx, dsn)
+	poolConfig, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		zap.L().Fatal(err.Error())
+	}
+	repository.TrackRequestID(poolConfig)
+	poolConfig.ConnConfig.Tracer = tracing.QueryTracer{}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		zap.L().Fatal("error to open connect to database")
 	}
 
+	m := metrics.New(metricsNamespace)
+	if err = m.RegisterPgxPool("main", pool); err != nil {
+		zap.L().Fatal(err.Error())
+	}
+
 	grpcConn, err := grpc.NewClient(
 		fmt.Sprintf("%s:%s", cfg.ExchangeService.Host, cfg.ExchangeService.Port),
 		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(tracing.UnaryClientInterceptor, requestid.UnaryClientInterceptor, m.UnaryClientInterceptor),
 	)
 
 	exchangeClient := gw_grpc.NewExchangeServiceClient(grpcConn)
 
+	ml, err := mailer.NewMailer(&cfg.Mailer)
+	if err != nil {
+		zap.L().Fatal(err.Error())
+	}
+
 	r := repository.NewRepository(pool)
-	s := service.NewService(ctx, r, &cfg.Auth, exchangeClient)
-	h := handler.NewHandler(s)
+	if r.RateLimit, err = repository.NewRateLimit(cfg.RateLimit.Backend, pool); err != nil {
+		zap.L().Fatal(err.Error())
+	}
+
+	s := service.NewService(ctx, r, &cfg.Auth, &cfg.RateLimit, exchangeClient, ml, m)
+	h := handler.NewHandler(s, &cfg.Server, m)
 
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
@@ -88,6 +122,9 @@ func main() {
 			zap.L().Error("failed to close gRPC connection", zap.Error(err))
 		}
 	}
+	if err = shutdownTracing(ctx); err != nil {
+		zap.L().Error("failed to flush traces", zap.Error(err))
+	}
 
 	zap.L().Info("server gracefully stopped")
 }
@@ -3,8 +3,11 @@ package config
 import (
 	"flag"
 	"fmt"
+	"net"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"go.uber.org/zap"
@@ -15,10 +18,16 @@ type Config struct {
 	Database        DatabaseConfig
 	Auth            AuthConfig
 	ExchangeService ExchangeService
+	Mailer          MailerConfig
+	RateLimit       RateLimitConfig
+	Tracing         TracingConfig
 }
 
 type ServerConfig struct {
 	Port string
+	// TrustedProxies IP-адреса и подсети прокси, чьему X-Forwarded-For можно верить при определении
+	// IP клиента; пустой список — заголовок игнорируется, IP клиента берётся из соединения
+	TrustedProxies []string
 }
 
 type DatabaseConfig struct {
@@ -32,6 +41,72 @@ type DatabaseConfig struct {
 
 type AuthConfig struct {
 	SecretKey string
+	// SigningKeys пути к PEM-файлам ключей RSA или Ed25519 по kid
+	SigningKeys map[string]string
+	// ActiveKeyID kid ключа, которым подписываются новые токены
+	ActiveKeyID string
+	// LoginMaxAttempts и LoginMaxAttemptsPerIP — число неудачных попыток входа
+	// до блокировки по аккаунту и по IP-адресу, LoginLockoutDuration — длительность блокировки
+	LoginMaxAttempts      int
+	LoginMaxAttemptsPerIP int
+	LoginLockoutDuration  time.Duration
+	// PasswordResetURL адрес страницы сброса пароля, к которому добавляется токен
+	PasswordResetURL string
+	// EmailVerificationURL адрес страницы подтверждения email, к которому добавляется токен
+	EmailVerificationURL string
+	PasswordHash         PasswordHashConfig
+	PasswordPolicy       PasswordPolicyConfig
+}
+
+type PasswordHashConfig struct {
+	// Algorithm "argon2id" (по умолчанию) или "bcrypt" для новых хешей; хеши обоих алгоритмов проверяются всегда
+	Algorithm string
+	// Argon2Memory память в КиБ, Argon2Iterations число проходов, Argon2Parallelism число потоков
+	Argon2Memory      int
+	Argon2Iterations  int
+	Argon2Parallelism int
+	BcryptCost        int
+}
+
+type PasswordPolicyConfig struct {
+	MinLength int
+	// BreachedListPath файл с утёкшими паролями по одному в строке, дополняет встроенный список
+	BreachedListPath string
+}
+
+type MailerConfig struct {
+	// Driver "smtp" или "file"; file пишет письма в FilePath, а без него — в лог
+	Driver       string
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	From         string
+	FilePath     string
+	// SMTPTimeout предельное время отправки одного письма, включая подключение
+	SMTPTimeout time.Duration
+}
+
+type RateLimitConfig struct {
+	// Backend "memory" (по умолчанию) хранит счётчики в памяти процесса, "postgres" — в базе,
+	// тогда лимиты общие для всех экземпляров
+	Backend string
+	// Limits лимиты по группам маршрутов; группы без лимита получают лимит группы "default"
+	Limits map[string]RateLimit
+}
+
+// RateLimit не больше Requests запросов за Period. Запас восстанавливается равномерно,
+// поэтому короткий всплеск до Requests запросов допустим.
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+}
+
+type TracingConfig struct {
+	// Exporter "otlp" отправляет спаны по OTLP/gRPC (адрес из OTEL_EXPORTER_OTLP_ENDPOINT),
+	// "stdout" пишет их в stdout, "file" — в FilePath; пустое значение отключает экспорт
+	Exporter string
+	FilePath string
 }
 
 type ExchangeService struct {
@@ -58,6 +133,7 @@ func LoadConfig() *Config {
 	cfg := &Config{}
 
 	cfg.Server.Port = os.Getenv("SERVER_PORT")
+	cfg.Server.TrustedProxies = parseTrustedProxies(os.Getenv("TRUSTED_PROXIES"))
 
 	cfg.Database.Host = os.Getenv("DATABASE_HOST")
 	dbPort := os.Getenv("DATABASE_PORT")
@@ -73,9 +149,132 @@ func LoadConfig() *Config {
 	cfg.Database.Name = os.Getenv("DATABASE_NAME")
 
 	cfg.Auth.SecretKey = os.Getenv("JWT_KEY")
+	cfg.Auth.SigningKeys = parseSigningKeys(os.Getenv("JWT_SIGNING_KEYS"))
+	cfg.Auth.ActiveKeyID = os.Getenv("JWT_ACTIVE_KEY_ID")
+	cfg.Auth.LoginMaxAttempts = parseInt("LOGIN_MAX_ATTEMPTS")
+	cfg.Auth.LoginMaxAttemptsPerIP = parseInt("LOGIN_MAX_ATTEMPTS_PER_IP")
+	cfg.Auth.LoginLockoutDuration = parseDuration("LOGIN_LOCKOUT_DURATION")
+	cfg.Auth.PasswordResetURL = os.Getenv("PASSWORD_RESET_URL")
+	cfg.Auth.EmailVerificationURL = os.Getenv("EMAIL_VERIFICATION_URL")
+	cfg.Auth.PasswordHash.Algorithm = os.Getenv("PASSWORD_HASH_ALGORITHM")
+	cfg.Auth.PasswordHash.Argon2Memory = parseInt("ARGON2_MEMORY")
+	cfg.Auth.PasswordHash.Argon2Iterations = parseInt("ARGON2_ITERATIONS")
+	cfg.Auth.PasswordHash.Argon2Parallelism = parseInt("ARGON2_PARALLELISM")
+	cfg.Auth.PasswordHash.BcryptCost = parseInt("BCRYPT_COST")
+	cfg.Auth.PasswordPolicy.MinLength = parseInt("PASSWORD_MIN_LENGTH")
+	cfg.Auth.PasswordPolicy.BreachedListPath = os.Getenv("PASSWORD_BREACHED_LIST")
 
 	cfg.ExchangeService.Host = os.Getenv("EXCHANGE_SERVICE_HOST")
 	cfg.ExchangeService.Port = os.Getenv("EXCHANGE_SERVICE_PORT")
 
+	cfg.Mailer.Driver = os.Getenv("MAILER_DRIVER")
+	cfg.Mailer.SMTPHost = os.Getenv("SMTP_HOST")
+	cfg.Mailer.SMTPPort = parseInt("SMTP_PORT")
+	cfg.Mailer.SMTPUsername = os.Getenv("SMTP_USERNAME")
+	cfg.Mailer.SMTPPassword = os.Getenv("SMTP_PASSWORD")
+	cfg.Mailer.SMTPTimeout = parseDuration("SMTP_TIMEOUT")
+	cfg.Mailer.From = os.Getenv("MAIL_FROM")
+	cfg.Mailer.FilePath = os.Getenv("MAIL_FILE")
+
+	cfg.RateLimit.Backend = os.Getenv("RATE_LIMIT_BACKEND")
+	cfg.RateLimit.Limits = parseRateLimits(os.Getenv("RATE_LIMITS"))
+
+	cfg.Tracing.Exporter = os.Getenv("TRACING_EXPORTER")
+	cfg.Tracing.FilePath = os.Getenv("TRACING_FILE")
+
 	return cfg
 }
+
+// parseSigningKeys разбирает список вида "kid1=/path/to/a.pem,kid2=/path/to/b.pem"
+func parseSigningKeys(value string) map[string]string {
+	keys := make(map[string]string)
+	if value == "" {
+		return keys
+	}
+
+	for _, item := range strings.Split(value, ",") {
+		kid, path, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok || kid == "" || path == "" {
+			zap.L().Fatal(fmt.Sprintf("invalid JWT_SIGNING_KEYS entry: %s", item))
+		}
+		keys[kid] = path
+	}
+
+	return keys
+}
+
+// parseTrustedProxies разбирает список IP-адресов и подсетей вида "10.0.0.1,192.168.0.0/16"
+func parseTrustedProxies(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	var proxies []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if _, _, err := net.ParseCIDR(item); err != nil && net.ParseIP(item) == nil {
+			zap.L().Fatal(fmt.Sprintf("invalid TRUSTED_PROXIES entry: %s", item))
+		}
+		proxies = append(proxies, item)
+	}
+
+	return proxies
+}
+
+// parseRateLimits разбирает список вида "auth=10/1m,exchange=30/1m". Лимит 0 отключает ограничение группы.
+func parseRateLimits(value string) map[string]RateLimit {
+	limits := make(map[string]RateLimit)
+	if value == "" {
+		return limits
+	}
+
+	for _, item := range strings.Split(value, ",") {
+		group, rule, ok := strings.Cut(strings.TrimSpace(item), "=")
+		requests, period, okRule := strings.Cut(rule, "/")
+		if !ok || !okRule || group == "" {
+			zap.L().Fatal(fmt.Sprintf("invalid RATE_LIMITS entry: %s", item))
+		}
+
+		n, err := strconv.Atoi(requests)
+		if err != nil || n < 0 {
+			zap.L().Fatal(fmt.Sprintf("invalid RATE_LIMITS entry: %s", item))
+		}
+
+		d, err := time.ParseDuration(period)
+		if err != nil || d <= 0 {
+			zap.L().Fatal(fmt.Sprintf("invalid RATE_LIMITS entry: %s", item))
+		}
+
+		limits[group] = RateLimit{Requests: n, Period: d}
+	}
+
+	return limits
+}
+
+func parseInt(name string) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		zap.L().Fatal(fmt.Sprintf("invalid %s value: %s", name, value))
+	}
+
+	return n
+}
+
+func parseDuration(name string) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		zap.L().Fatal(fmt.Sprintf("invalid %s value: %s", name, value))
+	}
+
+	return d
+}
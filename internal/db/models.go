@@ -4,14 +4,200 @@
 
 package db
 
+import (
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+)
+
 type AppAccount struct {
-	Email    string
-	Username string
-	Password string
+	Email      string
+	Username   string
+	Password   string
+	VerifiedAt pgtype.Timestamptz
+	Role       string
+	FrozenAt   pgtype.Timestamptz
+}
+
+type AppAccountTotp struct {
+	Email        string
+	Secret       string
+	EnabledAt    pgtype.Timestamptz
+	LastUsedStep int64
+	CreatedAt    pgtype.Timestamptz
+}
+
+type AppAdminAuditLog struct {
+	ID        int64
+	Actor     string
+	Action    string
+	Subject   string
+	Reason    string
+	Details   []byte
+	CreatedAt pgtype.Timestamptz
+}
+
+type AppApiKey struct {
+	ID         pgtype.UUID
+	Email      string
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	AllowedIps []string
+	ExpiresAt  pgtype.Timestamptz
+	LastUsedAt pgtype.Timestamptz
+	RevokedAt  pgtype.Timestamptz
+	CreatedAt  pgtype.Timestamptz
+}
+
+type AppCurrency struct {
+	Code       string
+	Name       string
+	MinorUnits int16
+	Symbol     string
+	Enabled    bool
+}
+
+type AppEmailVerificationToken struct {
+	TokenHash string
+	Email     string
+	ExpiresAt pgtype.Timestamptz
+	UsedAt    pgtype.Timestamptz
+	CreatedAt pgtype.Timestamptz
+}
+
+type AppExchangeFee struct {
+	FromCurrency  string
+	ToCurrency    string
+	SpreadPercent decimal.Decimal
+	FixedFee      decimal.Decimal
+	MinFee        decimal.Decimal
+}
+
+type AppExchangeQuote struct {
+	ID            pgtype.UUID
+	Email         string
+	FromCurrency  string
+	ToCurrency    string
+	Amount        decimal.Decimal
+	Rate          decimal.Decimal
+	ReceiveAmount decimal.Decimal
+	ExpiresAt     pgtype.Timestamptz
+	UsedAt        pgtype.Timestamptz
+	CreatedAt     pgtype.Timestamptz
+	Fee           decimal.Decimal
+}
+
+type AppHold struct {
+	ID             pgtype.UUID
+	Email          string
+	Currency       string
+	Amount         decimal.Decimal
+	CapturedAmount decimal.Decimal
+	Status         string
+	ExpiresAt      pgtype.Timestamptz
+	CreatedAt      pgtype.Timestamptz
+	UpdatedAt      pgtype.Timestamptz
+}
+
+type AppIdempotencyKey struct {
+	Email       string
+	Key         string
+	Operation   string
+	RequestHash string
+	Response    []byte
+	CreatedAt   pgtype.Timestamptz
+}
+
+type AppLedgerEntry struct {
+	ID            int64
+	TransactionID int64
+	Account       string
+	Currency      string
+	Direction     string
+	Amount        decimal.Decimal
+}
+
+type AppLockoutEvent struct {
+	ID          int64
+	Scope       string
+	Subject     string
+	Failures    int32
+	LockedUntil pgtype.Timestamptz
+	CreatedAt   pgtype.Timestamptz
+	UnlockedAt  pgtype.Timestamptz
+	UnlockedBy  pgtype.Text
+}
+
+type AppLoginAttempt struct {
+	Scope        string
+	Subject      string
+	Failures     int32
+	LastFailedAt pgtype.Timestamptz
+	LockedUntil  pgtype.Timestamptz
+}
+
+type AppLoginChallenge struct {
+	TokenHash string
+	Email     string
+	Attempts  int32
+	ExpiresAt pgtype.Timestamptz
+	UsedAt    pgtype.Timestamptz
+	CreatedAt pgtype.Timestamptz
+}
+
+type AppPasswordResetToken struct {
+	TokenHash string
+	Email     string
+	ExpiresAt pgtype.Timestamptz
+	UsedAt    pgtype.Timestamptz
+	CreatedAt pgtype.Timestamptz
+}
+
+type AppRateLimitBucket struct {
+	Key       string
+	Tokens    float64
+	Allowed   bool
+	UpdatedAt pgtype.Timestamptz
+}
+
+type AppRecoveryCode struct {
+	Email     string
+	CodeHash  string
+	UsedAt    pgtype.Timestamptz
+	CreatedAt pgtype.Timestamptz
+}
+
+type AppRefreshToken struct {
+	TokenHash string
+	SessionID pgtype.UUID
+	ExpiresAt pgtype.Timestamptz
+	UsedAt    pgtype.Timestamptz
+	CreatedAt pgtype.Timestamptz
+}
+
+type AppSession struct {
+	ID        pgtype.UUID
+	Email     string
+	CreatedAt pgtype.Timestamptz
+	RevokedAt pgtype.Timestamptz
+}
+
+type AppTransaction struct {
+	ID        int64
+	Type      string
+	CreatedAt pgtype.Timestamptz
 }
 
 type AppWallet struct {
 	Email    string
 	Currency string
-	Balance  float32
+	Balance  decimal.Decimal
+}
+
+type AppWalletReconciliation struct {
+	Email         string
+	Currency      string
+	Balance       decimal.Decimal
+	LedgerBalance decimal.Decimal
 }
@@ -3,20 +3,15 @@ INSERT INTO app.account (email, username, password)
 VALUES ($1, $2, $3)
 RETURNING *;
 
--- name: IsAccountExistsByUsername :one
-SELECT EXISTS (
-    SELECT 1 FROM app.account WHERE username = $1
-);
-
--- name: IsAccountExistsByEmail :one
-SELECT EXISTS (
-    SELECT 1 FROM app.account WHERE email = $1
-);
-
 -- name: GetAccountByUsername :one
 SELECT *
 FROM app.account
-WHERE username = $1;
+WHERE lower(username) = lower(@username::text);
+
+-- name: GetAccountByEmail :one
+SELECT *
+FROM app.account
+WHERE lower(email) = lower(@email::text);
 
 -- name: GetWalletsByEmail :many
 SELECT *
@@ -42,4 +37,375 @@ SELECT EXISTS (
 
 -- name: CreateWallet :exec
 INSERT INTO app.wallet (email, currency, balance)
-VALUES ($1, $2, 0);
\ No newline at end of file
+VALUES ($1, $2, 0);
+
+-- name: CreateTransaction :one
+INSERT INTO app.transaction (type)
+VALUES ($1)
+RETURNING *;
+
+-- name: CreateLedgerEntry :exec
+INSERT INTO app.ledger_entry (transaction_id, account, currency, direction, amount)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListAccountTransactions :many
+SELECT t.id, t.type, t.created_at
+FROM app.transaction t
+WHERE EXISTS (
+    SELECT 1
+    FROM app.ledger_entry e
+    WHERE e.transaction_id = t.id
+      AND e.account = @account
+      AND (sqlc.narg(currency)::varchar IS NULL OR e.currency = sqlc.narg(currency))
+)
+  AND (sqlc.narg(type)::varchar IS NULL OR t.type = sqlc.narg(type))
+  AND (sqlc.narg(from_time)::timestamptz IS NULL OR t.created_at >= sqlc.narg(from_time))
+  AND (sqlc.narg(to_time)::timestamptz IS NULL OR t.created_at < sqlc.narg(to_time))
+  AND (
+    sqlc.narg(cursor_time)::timestamptz IS NULL
+    OR (t.created_at, t.id) < (sqlc.narg(cursor_time), sqlc.narg(cursor_id)::bigint)
+  )
+ORDER BY t.created_at DESC, t.id DESC
+LIMIT @page_size;
+
+-- name: GetAccountEntriesByTransactions :many
+SELECT *
+FROM app.ledger_entry
+WHERE account = @account AND transaction_id = ANY(@transaction_ids::bigint[])
+ORDER BY id;
+
+-- name: ClaimIdempotencyKey :execrows
+INSERT INTO app.idempotency_key (email, key, operation, request_hash)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (email, key) DO NOTHING;
+
+-- name: GetIdempotencyKey :one
+SELECT *
+FROM app.idempotency_key
+WHERE email = $1 AND key = $2;
+
+-- name: SaveIdempotencyResponse :exec
+UPDATE app.idempotency_key
+SET response = $3
+WHERE email = $1 AND key = $2;
+
+-- name: CreateExchangeQuote :one
+INSERT INTO app.exchange_quote (email, from_currency, to_currency, amount, rate, receive_amount, fee, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetExchangeFee :one
+SELECT *
+FROM app.exchange_fee
+WHERE from_currency IN (@from_currency::varchar, '*')
+  AND to_currency IN (@to_currency::varchar, '*')
+ORDER BY from_currency = '*', to_currency = '*'
+LIMIT 1;
+
+-- name: GetExchangeQuoteForUpdate :one
+SELECT *
+FROM app.exchange_quote
+WHERE id = $1 AND email = $2
+FOR UPDATE;
+
+-- name: MarkExchangeQuoteUsed :exec
+UPDATE app.exchange_quote
+SET used_at = now()
+WHERE id = $1;
+
+-- name: GetCurrency :one
+SELECT *
+FROM app.currency
+WHERE code = $1;
+
+-- name: ListCurrencies :many
+SELECT *
+FROM app.currency
+ORDER BY code;
+
+-- name: CreateHold :one
+INSERT INTO app.hold (email, currency, amount, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetHoldForUpdate :one
+SELECT *
+FROM app.hold
+WHERE id = $1 AND email = $2
+FOR UPDATE;
+
+-- name: GetHeldAmount :one
+SELECT COALESCE(SUM(amount), 0)::numeric AS held
+FROM app.hold
+WHERE email = $1 AND currency = $2 AND status = 'active' AND expires_at > now();
+
+-- name: GetHeldAmountsByEmail :many
+SELECT currency, SUM(amount)::numeric AS held
+FROM app.hold
+WHERE email = $1 AND status = 'active' AND expires_at > now()
+GROUP BY currency;
+
+-- name: UpdateHoldStatus :one
+UPDATE app.hold
+SET status = $2, captured_amount = $3, updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: CreateSession :one
+INSERT INTO app.session (email)
+VALUES ($1)
+RETURNING *;
+
+-- name: GetSession :one
+SELECT *
+FROM app.session
+WHERE id = $1;
+
+-- name: RevokeSession :exec
+UPDATE app.session
+SET revoked_at = now()
+WHERE id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeAccountSessions :exec
+UPDATE app.session
+SET revoked_at = now()
+WHERE email = $1 AND revoked_at IS NULL;
+
+-- name: CreateRefreshToken :exec
+INSERT INTO app.refresh_token (token_hash, session_id, expires_at)
+VALUES ($1, $2, $3);
+
+-- name: GetRefreshTokenForUpdate :one
+SELECT *
+FROM app.refresh_token
+WHERE token_hash = $1
+FOR UPDATE;
+
+-- name: MarkRefreshTokenUsed :exec
+UPDATE app.refresh_token
+SET used_at = now()
+WHERE token_hash = $1;
+
+-- name: GetLoginAttempt :one
+SELECT *
+FROM app.login_attempt
+WHERE scope = $1 AND subject = $2;
+
+-- name: RecordLoginFailure :one
+INSERT INTO app.login_attempt (scope, subject, failures, last_failed_at)
+VALUES (@scope, @subject, 1, now())
+ON CONFLICT (scope, subject) DO UPDATE
+SET failures = CASE
+        WHEN app.login_attempt.last_failed_at < @reset_before THEN 1
+        ELSE app.login_attempt.failures + 1
+    END,
+    last_failed_at = now()
+RETURNING *;
+
+-- name: SetLoginLockedUntil :exec
+UPDATE app.login_attempt
+SET locked_until = $3, failures = $4
+WHERE scope = $1 AND subject = $2;
+
+-- name: DeleteLoginAttempt :exec
+DELETE FROM app.login_attempt
+WHERE scope = $1 AND subject = $2;
+
+-- name: CreateLockoutEvent :exec
+INSERT INTO app.lockout_event (scope, subject, failures, locked_until)
+VALUES ($1, $2, $3, $4);
+
+-- name: ListLockoutEvents :many
+SELECT *
+FROM app.lockout_event
+WHERE scope = $1 AND subject = $2
+ORDER BY id DESC
+LIMIT $3;
+
+-- name: MarkLockoutEventsUnlocked :exec
+UPDATE app.lockout_event
+SET unlocked_at = now(), unlocked_by = $3
+WHERE scope = $1 AND subject = $2 AND unlocked_at IS NULL;
+
+-- name: GetAccountTOTP :one
+SELECT *
+FROM app.account_totp
+WHERE email = $1;
+
+-- name: GetAccountTOTPForUpdate :one
+SELECT *
+FROM app.account_totp
+WHERE email = $1
+FOR UPDATE;
+
+-- name: UpsertAccountTOTP :exec
+INSERT INTO app.account_totp (email, secret)
+VALUES ($1, $2)
+ON CONFLICT (email) DO UPDATE
+SET secret = EXCLUDED.secret, created_at = now()
+WHERE app.account_totp.enabled_at IS NULL;
+
+-- name: EnableAccountTOTP :exec
+UPDATE app.account_totp
+SET enabled_at = now(), last_used_step = $2
+WHERE email = $1;
+
+-- name: UpdateTOTPLastUsedStep :exec
+UPDATE app.account_totp
+SET last_used_step = $2
+WHERE email = $1;
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM app.recovery_code
+WHERE email = $1;
+
+-- name: CreateRecoveryCode :exec
+INSERT INTO app.recovery_code (email, code_hash)
+VALUES ($1, $2);
+
+-- name: UseRecoveryCode :execrows
+UPDATE app.recovery_code
+SET used_at = now()
+WHERE email = $1 AND code_hash = $2 AND used_at IS NULL;
+
+-- name: CreateLoginChallenge :exec
+INSERT INTO app.login_challenge (token_hash, email, expires_at)
+VALUES ($1, $2, $3);
+
+-- name: GetLoginChallengeForUpdate :one
+SELECT *
+FROM app.login_challenge
+WHERE token_hash = $1
+FOR UPDATE;
+
+-- name: IncrementLoginChallengeAttempts :exec
+UPDATE app.login_challenge
+SET attempts = attempts + 1
+WHERE token_hash = $1;
+
+-- name: MarkLoginChallengeUsed :exec
+UPDATE app.login_challenge
+SET used_at = now()
+WHERE token_hash = $1;
+
+-- name: CreatePasswordResetToken :exec
+INSERT INTO app.password_reset_token (token_hash, email, expires_at)
+VALUES ($1, $2, $3);
+
+-- name: GetPasswordResetTokenForUpdate :one
+SELECT *
+FROM app.password_reset_token
+WHERE token_hash = $1
+FOR UPDATE;
+
+-- name: UsePasswordResetTokens :exec
+UPDATE app.password_reset_token
+SET used_at = now()
+WHERE email = $1 AND used_at IS NULL;
+
+-- name: UpdateAccountPassword :exec
+UPDATE app.account
+SET password = $2
+WHERE email = $1;
+
+-- name: RehashAccountPassword :execrows
+UPDATE app.account
+SET password = @new_password
+WHERE email = @email AND password = @current_password;
+
+-- name: CreateEmailVerificationToken :exec
+INSERT INTO app.email_verification_token (token_hash, email, expires_at)
+VALUES ($1, $2, $3);
+
+-- name: GetEmailVerificationTokenForUpdate :one
+SELECT *
+FROM app.email_verification_token
+WHERE token_hash = $1
+FOR UPDATE;
+
+-- name: GetLastEmailVerificationSentAt :one
+SELECT max(created_at)::timestamptz AS last_sent_at
+FROM app.email_verification_token
+WHERE email = $1;
+
+-- name: UseEmailVerificationTokens :exec
+UPDATE app.email_verification_token
+SET used_at = now()
+WHERE email = $1 AND used_at IS NULL;
+
+-- name: VerifyAccountEmail :exec
+UPDATE app.account
+SET verified_at = now()
+WHERE email = $1 AND verified_at IS NULL;
+
+-- name: SearchAccounts :many
+SELECT *
+FROM app.account
+WHERE lower(email) LIKE '%' || lower(@query::text) || '%'
+   OR lower(username) LIKE '%' || lower(@query::text) || '%'
+ORDER BY email
+LIMIT @page_size;
+
+-- name: SetAccountFrozen :execrows
+UPDATE app.account
+SET frozen_at = CASE WHEN @frozen::bool THEN coalesce(frozen_at, now()) END
+WHERE email = @email;
+
+-- name: SetAccountRole :execrows
+UPDATE app.account
+SET role = $2
+WHERE email = $1;
+
+-- name: CreateAdminAuditEntry :exec
+INSERT INTO app.admin_audit_log (actor, action, subject, reason, details)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListAdminAuditEntries :many
+SELECT *
+FROM app.admin_audit_log
+WHERE sqlc.narg(subject)::varchar IS NULL OR subject = sqlc.narg(subject)
+ORDER BY id DESC
+LIMIT @page_size;
+
+-- name: CreateAPIKey :one
+INSERT INTO app.api_key (email, name, prefix, key_hash, scopes, allowed_ips, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetAPIKeyByPrefix :one
+SELECT *
+FROM app.api_key
+WHERE prefix = $1;
+
+-- name: ListAPIKeys :many
+SELECT *
+FROM app.api_key
+WHERE email = $1
+ORDER BY created_at DESC;
+
+-- name: RevokeAPIKey :execrows
+UPDATE app.api_key
+SET revoked_at = now()
+WHERE id = $1 AND email = $2 AND revoked_at IS NULL;
+
+-- name: TouchAPIKey :exec
+UPDATE app.api_key
+SET last_used_at = now()
+WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute');
+
+-- name: TakeRateLimitToken :one
+INSERT INTO app.rate_limit_bucket (key, tokens, allowed, updated_at)
+VALUES (@key, @capacity::float8 - 1, TRUE, now())
+ON CONFLICT (key) DO UPDATE
+SET tokens = CASE
+        WHEN LEAST(@capacity::float8, app.rate_limit_bucket.tokens + EXTRACT(EPOCH FROM now() - app.rate_limit_bucket.updated_at)::float8 * @refill_rate::float8) >= 1
+        THEN LEAST(@capacity::float8, app.rate_limit_bucket.tokens + EXTRACT(EPOCH FROM now() - app.rate_limit_bucket.updated_at)::float8 * @refill_rate::float8) - 1
+        ELSE LEAST(@capacity::float8, app.rate_limit_bucket.tokens + EXTRACT(EPOCH FROM now() - app.rate_limit_bucket.updated_at)::float8 * @refill_rate::float8)
+    END,
+    allowed = LEAST(@capacity::float8, app.rate_limit_bucket.tokens + EXTRACT(EPOCH FROM now() - app.rate_limit_bucket.updated_at)::float8 * @refill_rate::float8) >= 1,
+    updated_at = now()
+RETURNING tokens, allowed;
+
+-- name: DeleteIdleRateLimitBuckets :execrows
+DELETE FROM app.rate_limit_bucket
+WHERE updated_at < $1;
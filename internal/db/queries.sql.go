@@ -7,12 +7,84 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
 )
 
+const claimIdempotencyKey = `-- name: ClaimIdempotencyKey :execrows
+INSERT INTO app.idempotency_key (email, key, operation, request_hash)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (email, key) DO NOTHING
+`
+
+type ClaimIdempotencyKeyParams struct {
+	Email       string
+	Key         string
+	Operation   string
+	RequestHash string
+}
+
+func (q *Queries) ClaimIdempotencyKey(ctx context.Context, arg ClaimIdempotencyKeyParams) (int64, error) {
+	result, err := q.db.Exec(ctx, claimIdempotencyKey,
+		arg.Email,
+		arg.Key,
+		arg.Operation,
+		arg.RequestHash,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const createAPIKey = `-- name: CreateAPIKey :one
+INSERT INTO app.api_key (email, name, prefix, key_hash, scopes, allowed_ips, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, email, name, prefix, key_hash, scopes, allowed_ips, expires_at, last_used_at, revoked_at, created_at
+`
+
+type CreateAPIKeyParams struct {
+	Email      string
+	Name       string
+	Prefix     string
+	KeyHash    string
+	Scopes     []string
+	AllowedIps []string
+	ExpiresAt  pgtype.Timestamptz
+}
+
+func (q *Queries) CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (AppApiKey, error) {
+	row := q.db.QueryRow(ctx, createAPIKey,
+		arg.Email,
+		arg.Name,
+		arg.Prefix,
+		arg.KeyHash,
+		arg.Scopes,
+		arg.AllowedIps,
+		arg.ExpiresAt,
+	)
+	var i AppApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.Prefix,
+		&i.KeyHash,
+		&i.Scopes,
+		&i.AllowedIps,
+		&i.ExpiresAt,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const createAccount = `-- name: CreateAccount :one
 INSERT INTO app.account (email, username, password)
 VALUES ($1, $2, $3)
-RETURNING email, username, password
+RETURNING email, username, password, verified_at, role, frozen_at
 `
 
 type CreateAccountParams struct {
@@ -24,7 +96,274 @@ type CreateAccountParams struct {
 func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (AppAccount, error) {
 	row := q.db.QueryRow(ctx, createAccount, arg.Email, arg.Username, arg.Password)
 	var i AppAccount
-	err := row.Scan(&i.Email, &i.Username, &i.Password)
+	err := row.Scan(
+		&i.Email,
+		&i.Username,
+		&i.Password,
+		&i.VerifiedAt,
+		&i.Role,
+		&i.FrozenAt,
+	)
+	return i, err
+}
+
+const createAdminAuditEntry = `-- name: CreateAdminAuditEntry :exec
+INSERT INTO app.admin_audit_log (actor, action, subject, reason, details)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateAdminAuditEntryParams struct {
+	Actor   string
+	Action  string
+	Subject string
+	Reason  string
+	Details []byte
+}
+
+func (q *Queries) CreateAdminAuditEntry(ctx context.Context, arg CreateAdminAuditEntryParams) error {
+	_, err := q.db.Exec(ctx, createAdminAuditEntry,
+		arg.Actor,
+		arg.Action,
+		arg.Subject,
+		arg.Reason,
+		arg.Details,
+	)
+	return err
+}
+
+const createEmailVerificationToken = `-- name: CreateEmailVerificationToken :exec
+INSERT INTO app.email_verification_token (token_hash, email, expires_at)
+VALUES ($1, $2, $3)
+`
+
+type CreateEmailVerificationTokenParams struct {
+	TokenHash string
+	Email     string
+	ExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) CreateEmailVerificationToken(ctx context.Context, arg CreateEmailVerificationTokenParams) error {
+	_, err := q.db.Exec(ctx, createEmailVerificationToken, arg.TokenHash, arg.Email, arg.ExpiresAt)
+	return err
+}
+
+const createExchangeQuote = `-- name: CreateExchangeQuote :one
+INSERT INTO app.exchange_quote (email, from_currency, to_currency, amount, rate, receive_amount, fee, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, email, from_currency, to_currency, amount, rate, receive_amount, expires_at, used_at, created_at, fee
+`
+
+type CreateExchangeQuoteParams struct {
+	Email         string
+	FromCurrency  string
+	ToCurrency    string
+	Amount        decimal.Decimal
+	Rate          decimal.Decimal
+	ReceiveAmount decimal.Decimal
+	Fee           decimal.Decimal
+	ExpiresAt     pgtype.Timestamptz
+}
+
+func (q *Queries) CreateExchangeQuote(ctx context.Context, arg CreateExchangeQuoteParams) (AppExchangeQuote, error) {
+	row := q.db.QueryRow(ctx, createExchangeQuote,
+		arg.Email,
+		arg.FromCurrency,
+		arg.ToCurrency,
+		arg.Amount,
+		arg.Rate,
+		arg.ReceiveAmount,
+		arg.Fee,
+		arg.ExpiresAt,
+	)
+	var i AppExchangeQuote
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.FromCurrency,
+		&i.ToCurrency,
+		&i.Amount,
+		&i.Rate,
+		&i.ReceiveAmount,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+		&i.Fee,
+	)
+	return i, err
+}
+
+const createHold = `-- name: CreateHold :one
+INSERT INTO app.hold (email, currency, amount, expires_at)
+VALUES ($1, $2, $3, $4)
+RETURNING id, email, currency, amount, captured_amount, status, expires_at, created_at, updated_at
+`
+
+type CreateHoldParams struct {
+	Email     string
+	Currency  string
+	Amount    decimal.Decimal
+	ExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) CreateHold(ctx context.Context, arg CreateHoldParams) (AppHold, error) {
+	row := q.db.QueryRow(ctx, createHold,
+		arg.Email,
+		arg.Currency,
+		arg.Amount,
+		arg.ExpiresAt,
+	)
+	var i AppHold
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Currency,
+		&i.Amount,
+		&i.CapturedAmount,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createLedgerEntry = `-- name: CreateLedgerEntry :exec
+INSERT INTO app.ledger_entry (transaction_id, account, currency, direction, amount)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateLedgerEntryParams struct {
+	TransactionID int64
+	Account       string
+	Currency      string
+	Direction     string
+	Amount        decimal.Decimal
+}
+
+func (q *Queries) CreateLedgerEntry(ctx context.Context, arg CreateLedgerEntryParams) error {
+	_, err := q.db.Exec(ctx, createLedgerEntry,
+		arg.TransactionID,
+		arg.Account,
+		arg.Currency,
+		arg.Direction,
+		arg.Amount,
+	)
+	return err
+}
+
+const createLockoutEvent = `-- name: CreateLockoutEvent :exec
+INSERT INTO app.lockout_event (scope, subject, failures, locked_until)
+VALUES ($1, $2, $3, $4)
+`
+
+type CreateLockoutEventParams struct {
+	Scope       string
+	Subject     string
+	Failures    int32
+	LockedUntil pgtype.Timestamptz
+}
+
+func (q *Queries) CreateLockoutEvent(ctx context.Context, arg CreateLockoutEventParams) error {
+	_, err := q.db.Exec(ctx, createLockoutEvent,
+		arg.Scope,
+		arg.Subject,
+		arg.Failures,
+		arg.LockedUntil,
+	)
+	return err
+}
+
+const createLoginChallenge = `-- name: CreateLoginChallenge :exec
+INSERT INTO app.login_challenge (token_hash, email, expires_at)
+VALUES ($1, $2, $3)
+`
+
+type CreateLoginChallengeParams struct {
+	TokenHash string
+	Email     string
+	ExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) CreateLoginChallenge(ctx context.Context, arg CreateLoginChallengeParams) error {
+	_, err := q.db.Exec(ctx, createLoginChallenge, arg.TokenHash, arg.Email, arg.ExpiresAt)
+	return err
+}
+
+const createPasswordResetToken = `-- name: CreatePasswordResetToken :exec
+INSERT INTO app.password_reset_token (token_hash, email, expires_at)
+VALUES ($1, $2, $3)
+`
+
+type CreatePasswordResetTokenParams struct {
+	TokenHash string
+	Email     string
+	ExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) CreatePasswordResetToken(ctx context.Context, arg CreatePasswordResetTokenParams) error {
+	_, err := q.db.Exec(ctx, createPasswordResetToken, arg.TokenHash, arg.Email, arg.ExpiresAt)
+	return err
+}
+
+const createRecoveryCode = `-- name: CreateRecoveryCode :exec
+INSERT INTO app.recovery_code (email, code_hash)
+VALUES ($1, $2)
+`
+
+type CreateRecoveryCodeParams struct {
+	Email    string
+	CodeHash string
+}
+
+func (q *Queries) CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) error {
+	_, err := q.db.Exec(ctx, createRecoveryCode, arg.Email, arg.CodeHash)
+	return err
+}
+
+const createRefreshToken = `-- name: CreateRefreshToken :exec
+INSERT INTO app.refresh_token (token_hash, session_id, expires_at)
+VALUES ($1, $2, $3)
+`
+
+type CreateRefreshTokenParams struct {
+	TokenHash string
+	SessionID pgtype.UUID
+	ExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) error {
+	_, err := q.db.Exec(ctx, createRefreshToken, arg.TokenHash, arg.SessionID, arg.ExpiresAt)
+	return err
+}
+
+const createSession = `-- name: CreateSession :one
+INSERT INTO app.session (email)
+VALUES ($1)
+RETURNING id, email, created_at, revoked_at
+`
+
+func (q *Queries) CreateSession(ctx context.Context, email string) (AppSession, error) {
+	row := q.db.QueryRow(ctx, createSession, email)
+	var i AppSession
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.CreatedAt,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
+const createTransaction = `-- name: CreateTransaction :one
+INSERT INTO app.transaction (type)
+VALUES ($1)
+RETURNING id, type, created_at
+`
+
+func (q *Queries) CreateTransaction(ctx context.Context, type_ string) (AppTransaction, error) {
+	row := q.db.QueryRow(ctx, createTransaction, type_)
+	var i AppTransaction
+	err := row.Scan(&i.ID, &i.Type, &i.CreatedAt)
 	return i, err
 }
 
@@ -43,54 +382,340 @@ func (q *Queries) CreateWallet(ctx context.Context, arg CreateWalletParams) erro
 	return err
 }
 
+const deleteIdleRateLimitBuckets = `-- name: DeleteIdleRateLimitBuckets :execrows
+DELETE FROM app.rate_limit_bucket
+WHERE updated_at < $1
+`
+
+func (q *Queries) DeleteIdleRateLimitBuckets(ctx context.Context, updatedAt pgtype.Timestamptz) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteIdleRateLimitBuckets, updatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteLoginAttempt = `-- name: DeleteLoginAttempt :exec
+DELETE FROM app.login_attempt
+WHERE scope = $1 AND subject = $2
+`
+
+type DeleteLoginAttemptParams struct {
+	Scope   string
+	Subject string
+}
+
+func (q *Queries) DeleteLoginAttempt(ctx context.Context, arg DeleteLoginAttemptParams) error {
+	_, err := q.db.Exec(ctx, deleteLoginAttempt, arg.Scope, arg.Subject)
+	return err
+}
+
+const deleteRecoveryCodes = `-- name: DeleteRecoveryCodes :exec
+DELETE FROM app.recovery_code
+WHERE email = $1
+`
+
+func (q *Queries) DeleteRecoveryCodes(ctx context.Context, email string) error {
+	_, err := q.db.Exec(ctx, deleteRecoveryCodes, email)
+	return err
+}
+
+const enableAccountTOTP = `-- name: EnableAccountTOTP :exec
+UPDATE app.account_totp
+SET enabled_at = now(), last_used_step = $2
+WHERE email = $1
+`
+
+type EnableAccountTOTPParams struct {
+	Email        string
+	LastUsedStep int64
+}
+
+func (q *Queries) EnableAccountTOTP(ctx context.Context, arg EnableAccountTOTPParams) error {
+	_, err := q.db.Exec(ctx, enableAccountTOTP, arg.Email, arg.LastUsedStep)
+	return err
+}
+
+const getAPIKeyByPrefix = `-- name: GetAPIKeyByPrefix :one
+SELECT id, email, name, prefix, key_hash, scopes, allowed_ips, expires_at, last_used_at, revoked_at, created_at
+FROM app.api_key
+WHERE prefix = $1
+`
+
+func (q *Queries) GetAPIKeyByPrefix(ctx context.Context, prefix string) (AppApiKey, error) {
+	row := q.db.QueryRow(ctx, getAPIKeyByPrefix, prefix)
+	var i AppApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Name,
+		&i.Prefix,
+		&i.KeyHash,
+		&i.Scopes,
+		&i.AllowedIps,
+		&i.ExpiresAt,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getAccountByEmail = `-- name: GetAccountByEmail :one
+SELECT email, username, password, verified_at, role, frozen_at
+FROM app.account
+WHERE lower(email) = lower($1::text)
+`
+
+func (q *Queries) GetAccountByEmail(ctx context.Context, email string) (AppAccount, error) {
+	row := q.db.QueryRow(ctx, getAccountByEmail, email)
+	var i AppAccount
+	err := row.Scan(
+		&i.Email,
+		&i.Username,
+		&i.Password,
+		&i.VerifiedAt,
+		&i.Role,
+		&i.FrozenAt,
+	)
+	return i, err
+}
+
 const getAccountByUsername = `-- name: GetAccountByUsername :one
-SELECT email, username, password
+SELECT email, username, password, verified_at, role, frozen_at
 FROM app.account
-WHERE username = $1
+WHERE lower(username) = lower($1::text)
 `
 
 func (q *Queries) GetAccountByUsername(ctx context.Context, username string) (AppAccount, error) {
 	row := q.db.QueryRow(ctx, getAccountByUsername, username)
 	var i AppAccount
-	err := row.Scan(&i.Email, &i.Username, &i.Password)
+	err := row.Scan(
+		&i.Email,
+		&i.Username,
+		&i.Password,
+		&i.VerifiedAt,
+		&i.Role,
+		&i.FrozenAt,
+	)
 	return i, err
 }
 
-const getWalletForUpdate = `-- name: GetWalletForUpdate :one
-SELECT email, currency, balance
-FROM app.wallet
-WHERE email = $1 and currency = $2
+const getAccountEntriesByTransactions = `-- name: GetAccountEntriesByTransactions :many
+SELECT id, transaction_id, account, currency, direction, amount
+FROM app.ledger_entry
+WHERE account = $1 AND transaction_id = ANY($2::bigint[])
+ORDER BY id
+`
+
+type GetAccountEntriesByTransactionsParams struct {
+	Account        string
+	TransactionIds []int64
+}
+
+func (q *Queries) GetAccountEntriesByTransactions(ctx context.Context, arg GetAccountEntriesByTransactionsParams) ([]AppLedgerEntry, error) {
+	rows, err := q.db.Query(ctx, getAccountEntriesByTransactions, arg.Account, arg.TransactionIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AppLedgerEntry
+	for rows.Next() {
+		var i AppLedgerEntry
+		if err := rows.Scan(
+			&i.ID,
+			&i.TransactionID,
+			&i.Account,
+			&i.Currency,
+			&i.Direction,
+			&i.Amount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAccountTOTP = `-- name: GetAccountTOTP :one
+SELECT email, secret, enabled_at, last_used_step, created_at
+FROM app.account_totp
+WHERE email = $1
+`
+
+func (q *Queries) GetAccountTOTP(ctx context.Context, email string) (AppAccountTotp, error) {
+	row := q.db.QueryRow(ctx, getAccountTOTP, email)
+	var i AppAccountTotp
+	err := row.Scan(
+		&i.Email,
+		&i.Secret,
+		&i.EnabledAt,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getAccountTOTPForUpdate = `-- name: GetAccountTOTPForUpdate :one
+SELECT email, secret, enabled_at, last_used_step, created_at
+FROM app.account_totp
+WHERE email = $1
 FOR UPDATE
 `
 
-type GetWalletForUpdateParams struct {
+func (q *Queries) GetAccountTOTPForUpdate(ctx context.Context, email string) (AppAccountTotp, error) {
+	row := q.db.QueryRow(ctx, getAccountTOTPForUpdate, email)
+	var i AppAccountTotp
+	err := row.Scan(
+		&i.Email,
+		&i.Secret,
+		&i.EnabledAt,
+		&i.LastUsedStep,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getCurrency = `-- name: GetCurrency :one
+SELECT code, name, minor_units, symbol, enabled
+FROM app.currency
+WHERE code = $1
+`
+
+func (q *Queries) GetCurrency(ctx context.Context, code string) (AppCurrency, error) {
+	row := q.db.QueryRow(ctx, getCurrency, code)
+	var i AppCurrency
+	err := row.Scan(
+		&i.Code,
+		&i.Name,
+		&i.MinorUnits,
+		&i.Symbol,
+		&i.Enabled,
+	)
+	return i, err
+}
+
+const getEmailVerificationTokenForUpdate = `-- name: GetEmailVerificationTokenForUpdate :one
+SELECT token_hash, email, expires_at, used_at, created_at
+FROM app.email_verification_token
+WHERE token_hash = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetEmailVerificationTokenForUpdate(ctx context.Context, tokenHash string) (AppEmailVerificationToken, error) {
+	row := q.db.QueryRow(ctx, getEmailVerificationTokenForUpdate, tokenHash)
+	var i AppEmailVerificationToken
+	err := row.Scan(
+		&i.TokenHash,
+		&i.Email,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getExchangeFee = `-- name: GetExchangeFee :one
+SELECT from_currency, to_currency, spread_percent, fixed_fee, min_fee
+FROM app.exchange_fee
+WHERE from_currency IN ($1::varchar, '*')
+  AND to_currency IN ($2::varchar, '*')
+ORDER BY from_currency = '*', to_currency = '*'
+LIMIT 1
+`
+
+type GetExchangeFeeParams struct {
+	FromCurrency string
+	ToCurrency   string
+}
+
+func (q *Queries) GetExchangeFee(ctx context.Context, arg GetExchangeFeeParams) (AppExchangeFee, error) {
+	row := q.db.QueryRow(ctx, getExchangeFee, arg.FromCurrency, arg.ToCurrency)
+	var i AppExchangeFee
+	err := row.Scan(
+		&i.FromCurrency,
+		&i.ToCurrency,
+		&i.SpreadPercent,
+		&i.FixedFee,
+		&i.MinFee,
+	)
+	return i, err
+}
+
+const getExchangeQuoteForUpdate = `-- name: GetExchangeQuoteForUpdate :one
+SELECT id, email, from_currency, to_currency, amount, rate, receive_amount, expires_at, used_at, created_at, fee
+FROM app.exchange_quote
+WHERE id = $1 AND email = $2
+FOR UPDATE
+`
+
+type GetExchangeQuoteForUpdateParams struct {
+	ID    pgtype.UUID
+	Email string
+}
+
+func (q *Queries) GetExchangeQuoteForUpdate(ctx context.Context, arg GetExchangeQuoteForUpdateParams) (AppExchangeQuote, error) {
+	row := q.db.QueryRow(ctx, getExchangeQuoteForUpdate, arg.ID, arg.Email)
+	var i AppExchangeQuote
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.FromCurrency,
+		&i.ToCurrency,
+		&i.Amount,
+		&i.Rate,
+		&i.ReceiveAmount,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+		&i.Fee,
+	)
+	return i, err
+}
+
+const getHeldAmount = `-- name: GetHeldAmount :one
+SELECT COALESCE(SUM(amount), 0)::numeric AS held
+FROM app.hold
+WHERE email = $1 AND currency = $2 AND status = 'active' AND expires_at > now()
+`
+
+type GetHeldAmountParams struct {
 	Email    string
 	Currency string
 }
 
-func (q *Queries) GetWalletForUpdate(ctx context.Context, arg GetWalletForUpdateParams) (AppWallet, error) {
-	row := q.db.QueryRow(ctx, getWalletForUpdate, arg.Email, arg.Currency)
-	var i AppWallet
-	err := row.Scan(&i.Email, &i.Currency, &i.Balance)
-	return i, err
+func (q *Queries) GetHeldAmount(ctx context.Context, arg GetHeldAmountParams) (decimal.Decimal, error) {
+	row := q.db.QueryRow(ctx, getHeldAmount, arg.Email, arg.Currency)
+	var held decimal.Decimal
+	err := row.Scan(&held)
+	return held, err
 }
 
-const getWalletsByEmail = `-- name: GetWalletsByEmail :many
-SELECT email, currency, balance
-FROM app.wallet
-WHERE email = $1
+const getHeldAmountsByEmail = `-- name: GetHeldAmountsByEmail :many
+SELECT currency, SUM(amount)::numeric AS held
+FROM app.hold
+WHERE email = $1 AND status = 'active' AND expires_at > now()
+GROUP BY currency
 `
 
-func (q *Queries) GetWalletsByEmail(ctx context.Context, email string) ([]AppWallet, error) {
-	rows, err := q.db.Query(ctx, getWalletsByEmail, email)
+type GetHeldAmountsByEmailRow struct {
+	Currency string
+	Held     decimal.Decimal
+}
+
+func (q *Queries) GetHeldAmountsByEmail(ctx context.Context, email string) ([]GetHeldAmountsByEmailRow, error) {
+	rows, err := q.db.Query(ctx, getHeldAmountsByEmail, email)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []AppWallet
+	var items []GetHeldAmountsByEmailRow
 	for rows.Next() {
-		var i AppWallet
-		if err := rows.Scan(&i.Email, &i.Currency, &i.Balance); err != nil {
+		var i GetHeldAmountsByEmailRow
+		if err := rows.Scan(&i.Currency, &i.Held); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -101,66 +726,918 @@ func (q *Queries) GetWalletsByEmail(ctx context.Context, email string) ([]AppWal
 	return items, nil
 }
 
-const isAccountExistsByEmail = `-- name: IsAccountExistsByEmail :one
-SELECT EXISTS (
-    SELECT 1 FROM app.account WHERE email = $1
-)
+const getHoldForUpdate = `-- name: GetHoldForUpdate :one
+SELECT id, email, currency, amount, captured_amount, status, expires_at, created_at, updated_at
+FROM app.hold
+WHERE id = $1 AND email = $2
+FOR UPDATE
 `
 
-func (q *Queries) IsAccountExistsByEmail(ctx context.Context, email string) (bool, error) {
-	row := q.db.QueryRow(ctx, isAccountExistsByEmail, email)
-	var exists bool
-	err := row.Scan(&exists)
-	return exists, err
+type GetHoldForUpdateParams struct {
+	ID    pgtype.UUID
+	Email string
 }
 
-const isAccountExistsByUsername = `-- name: IsAccountExistsByUsername :one
-SELECT EXISTS (
-    SELECT 1 FROM app.account WHERE username = $1
-)
-`
-
-func (q *Queries) IsAccountExistsByUsername(ctx context.Context, username string) (bool, error) {
-	row := q.db.QueryRow(ctx, isAccountExistsByUsername, username)
-	var exists bool
-	err := row.Scan(&exists)
-	return exists, err
+func (q *Queries) GetHoldForUpdate(ctx context.Context, arg GetHoldForUpdateParams) (AppHold, error) {
+	row := q.db.QueryRow(ctx, getHoldForUpdate, arg.ID, arg.Email)
+	var i AppHold
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Currency,
+		&i.Amount,
+		&i.CapturedAmount,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
 }
 
-const isExistCurrency = `-- name: IsExistCurrency :one
-SELECT EXISTS (
-    SELECT 1 FROM app.wallet WHERE email = $1 and currency = $2
-)
+const getIdempotencyKey = `-- name: GetIdempotencyKey :one
+SELECT email, key, operation, request_hash, response, created_at
+FROM app.idempotency_key
+WHERE email = $1 AND key = $2
 `
 
-type IsExistCurrencyParams struct {
-	Email    string
-	Currency string
+type GetIdempotencyKeyParams struct {
+	Email string
+	Key   string
 }
 
-func (q *Queries) IsExistCurrency(ctx context.Context, arg IsExistCurrencyParams) (bool, error) {
-	row := q.db.QueryRow(ctx, isExistCurrency, arg.Email, arg.Currency)
-	var exists bool
-	err := row.Scan(&exists)
-	return exists, err
+func (q *Queries) GetIdempotencyKey(ctx context.Context, arg GetIdempotencyKeyParams) (AppIdempotencyKey, error) {
+	row := q.db.QueryRow(ctx, getIdempotencyKey, arg.Email, arg.Key)
+	var i AppIdempotencyKey
+	err := row.Scan(
+		&i.Email,
+		&i.Key,
+		&i.Operation,
+		&i.RequestHash,
+		&i.Response,
+		&i.CreatedAt,
+	)
+	return i, err
 }
 
-const updateWallet = `-- name: UpdateWallet :one
-UPDATE app.wallet
-SET balance = $3
-WHERE email = $1 and currency = $2
-RETURNING email, currency, balance
+const getLastEmailVerificationSentAt = `-- name: GetLastEmailVerificationSentAt :one
+SELECT max(created_at)::timestamptz AS last_sent_at
+FROM app.email_verification_token
+WHERE email = $1
 `
 
-type UpdateWalletParams struct {
-	Email    string
-	Currency string
-	Balance  float32
+func (q *Queries) GetLastEmailVerificationSentAt(ctx context.Context, email string) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, getLastEmailVerificationSentAt, email)
+	var last_sent_at pgtype.Timestamptz
+	err := row.Scan(&last_sent_at)
+	return last_sent_at, err
 }
 
-func (q *Queries) UpdateWallet(ctx context.Context, arg UpdateWalletParams) (AppWallet, error) {
+const getLoginAttempt = `-- name: GetLoginAttempt :one
+SELECT scope, subject, failures, last_failed_at, locked_until
+FROM app.login_attempt
+WHERE scope = $1 AND subject = $2
+`
+
+type GetLoginAttemptParams struct {
+	Scope   string
+	Subject string
+}
+
+func (q *Queries) GetLoginAttempt(ctx context.Context, arg GetLoginAttemptParams) (AppLoginAttempt, error) {
+	row := q.db.QueryRow(ctx, getLoginAttempt, arg.Scope, arg.Subject)
+	var i AppLoginAttempt
+	err := row.Scan(
+		&i.Scope,
+		&i.Subject,
+		&i.Failures,
+		&i.LastFailedAt,
+		&i.LockedUntil,
+	)
+	return i, err
+}
+
+const getLoginChallengeForUpdate = `-- name: GetLoginChallengeForUpdate :one
+SELECT token_hash, email, attempts, expires_at, used_at, created_at
+FROM app.login_challenge
+WHERE token_hash = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetLoginChallengeForUpdate(ctx context.Context, tokenHash string) (AppLoginChallenge, error) {
+	row := q.db.QueryRow(ctx, getLoginChallengeForUpdate, tokenHash)
+	var i AppLoginChallenge
+	err := row.Scan(
+		&i.TokenHash,
+		&i.Email,
+		&i.Attempts,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPasswordResetTokenForUpdate = `-- name: GetPasswordResetTokenForUpdate :one
+SELECT token_hash, email, expires_at, used_at, created_at
+FROM app.password_reset_token
+WHERE token_hash = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetPasswordResetTokenForUpdate(ctx context.Context, tokenHash string) (AppPasswordResetToken, error) {
+	row := q.db.QueryRow(ctx, getPasswordResetTokenForUpdate, tokenHash)
+	var i AppPasswordResetToken
+	err := row.Scan(
+		&i.TokenHash,
+		&i.Email,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getRefreshTokenForUpdate = `-- name: GetRefreshTokenForUpdate :one
+SELECT token_hash, session_id, expires_at, used_at, created_at
+FROM app.refresh_token
+WHERE token_hash = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetRefreshTokenForUpdate(ctx context.Context, tokenHash string) (AppRefreshToken, error) {
+	row := q.db.QueryRow(ctx, getRefreshTokenForUpdate, tokenHash)
+	var i AppRefreshToken
+	err := row.Scan(
+		&i.TokenHash,
+		&i.SessionID,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getSession = `-- name: GetSession :one
+SELECT id, email, created_at, revoked_at
+FROM app.session
+WHERE id = $1
+`
+
+func (q *Queries) GetSession(ctx context.Context, id pgtype.UUID) (AppSession, error) {
+	row := q.db.QueryRow(ctx, getSession, id)
+	var i AppSession
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.CreatedAt,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
+const getWalletForUpdate = `-- name: GetWalletForUpdate :one
+SELECT email, currency, balance
+FROM app.wallet
+WHERE email = $1 and currency = $2
+FOR UPDATE
+`
+
+type GetWalletForUpdateParams struct {
+	Email    string
+	Currency string
+}
+
+func (q *Queries) GetWalletForUpdate(ctx context.Context, arg GetWalletForUpdateParams) (AppWallet, error) {
+	row := q.db.QueryRow(ctx, getWalletForUpdate, arg.Email, arg.Currency)
+	var i AppWallet
+	err := row.Scan(&i.Email, &i.Currency, &i.Balance)
+	return i, err
+}
+
+const getWalletsByEmail = `-- name: GetWalletsByEmail :many
+SELECT email, currency, balance
+FROM app.wallet
+WHERE email = $1
+`
+
+func (q *Queries) GetWalletsByEmail(ctx context.Context, email string) ([]AppWallet, error) {
+	rows, err := q.db.Query(ctx, getWalletsByEmail, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AppWallet
+	for rows.Next() {
+		var i AppWallet
+		if err := rows.Scan(&i.Email, &i.Currency, &i.Balance); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const incrementLoginChallengeAttempts = `-- name: IncrementLoginChallengeAttempts :exec
+UPDATE app.login_challenge
+SET attempts = attempts + 1
+WHERE token_hash = $1
+`
+
+func (q *Queries) IncrementLoginChallengeAttempts(ctx context.Context, tokenHash string) error {
+	_, err := q.db.Exec(ctx, incrementLoginChallengeAttempts, tokenHash)
+	return err
+}
+
+const isExistCurrency = `-- name: IsExistCurrency :one
+SELECT EXISTS (
+    SELECT 1 FROM app.wallet WHERE email = $1 and currency = $2
+)
+`
+
+type IsExistCurrencyParams struct {
+	Email    string
+	Currency string
+}
+
+func (q *Queries) IsExistCurrency(ctx context.Context, arg IsExistCurrencyParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isExistCurrency, arg.Email, arg.Currency)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const listAPIKeys = `-- name: ListAPIKeys :many
+SELECT id, email, name, prefix, key_hash, scopes, allowed_ips, expires_at, last_used_at, revoked_at, created_at
+FROM app.api_key
+WHERE email = $1
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListAPIKeys(ctx context.Context, email string) ([]AppApiKey, error) {
+	rows, err := q.db.Query(ctx, listAPIKeys, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AppApiKey
+	for rows.Next() {
+		var i AppApiKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Name,
+			&i.Prefix,
+			&i.KeyHash,
+			&i.Scopes,
+			&i.AllowedIps,
+			&i.ExpiresAt,
+			&i.LastUsedAt,
+			&i.RevokedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAccountTransactions = `-- name: ListAccountTransactions :many
+SELECT t.id, t.type, t.created_at
+FROM app.transaction t
+WHERE EXISTS (
+    SELECT 1
+    FROM app.ledger_entry e
+    WHERE e.transaction_id = t.id
+      AND e.account = $1
+      AND ($2::varchar IS NULL OR e.currency = $2)
+)
+  AND ($3::varchar IS NULL OR t.type = $3)
+  AND ($4::timestamptz IS NULL OR t.created_at >= $4)
+  AND ($5::timestamptz IS NULL OR t.created_at < $5)
+  AND (
+    $6::timestamptz IS NULL
+    OR (t.created_at, t.id) < ($6, $7::bigint)
+  )
+ORDER BY t.created_at DESC, t.id DESC
+LIMIT $8
+`
+
+type ListAccountTransactionsParams struct {
+	Account    string
+	Currency   pgtype.Text
+	Type       pgtype.Text
+	FromTime   pgtype.Timestamptz
+	ToTime     pgtype.Timestamptz
+	CursorTime pgtype.Timestamptz
+	CursorID   pgtype.Int8
+	PageSize   int32
+}
+
+func (q *Queries) ListAccountTransactions(ctx context.Context, arg ListAccountTransactionsParams) ([]AppTransaction, error) {
+	rows, err := q.db.Query(ctx, listAccountTransactions,
+		arg.Account,
+		arg.Currency,
+		arg.Type,
+		arg.FromTime,
+		arg.ToTime,
+		arg.CursorTime,
+		arg.CursorID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AppTransaction
+	for rows.Next() {
+		var i AppTransaction
+		if err := rows.Scan(&i.ID, &i.Type, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAdminAuditEntries = `-- name: ListAdminAuditEntries :many
+SELECT id, actor, action, subject, reason, details, created_at
+FROM app.admin_audit_log
+WHERE $1::varchar IS NULL OR subject = $1
+ORDER BY id DESC
+LIMIT $2
+`
+
+type ListAdminAuditEntriesParams struct {
+	Subject  pgtype.Text
+	PageSize int32
+}
+
+func (q *Queries) ListAdminAuditEntries(ctx context.Context, arg ListAdminAuditEntriesParams) ([]AppAdminAuditLog, error) {
+	rows, err := q.db.Query(ctx, listAdminAuditEntries, arg.Subject, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AppAdminAuditLog
+	for rows.Next() {
+		var i AppAdminAuditLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.Actor,
+			&i.Action,
+			&i.Subject,
+			&i.Reason,
+			&i.Details,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCurrencies = `-- name: ListCurrencies :many
+SELECT code, name, minor_units, symbol, enabled
+FROM app.currency
+ORDER BY code
+`
+
+func (q *Queries) ListCurrencies(ctx context.Context) ([]AppCurrency, error) {
+	rows, err := q.db.Query(ctx, listCurrencies)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AppCurrency
+	for rows.Next() {
+		var i AppCurrency
+		if err := rows.Scan(
+			&i.Code,
+			&i.Name,
+			&i.MinorUnits,
+			&i.Symbol,
+			&i.Enabled,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLockoutEvents = `-- name: ListLockoutEvents :many
+SELECT id, scope, subject, failures, locked_until, created_at, unlocked_at, unlocked_by
+FROM app.lockout_event
+WHERE scope = $1 AND subject = $2
+ORDER BY id DESC
+LIMIT $3
+`
+
+type ListLockoutEventsParams struct {
+	Scope   string
+	Subject string
+	Limit   int32
+}
+
+func (q *Queries) ListLockoutEvents(ctx context.Context, arg ListLockoutEventsParams) ([]AppLockoutEvent, error) {
+	rows, err := q.db.Query(ctx, listLockoutEvents, arg.Scope, arg.Subject, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AppLockoutEvent
+	for rows.Next() {
+		var i AppLockoutEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.Scope,
+			&i.Subject,
+			&i.Failures,
+			&i.LockedUntil,
+			&i.CreatedAt,
+			&i.UnlockedAt,
+			&i.UnlockedBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markExchangeQuoteUsed = `-- name: MarkExchangeQuoteUsed :exec
+UPDATE app.exchange_quote
+SET used_at = now()
+WHERE id = $1
+`
+
+func (q *Queries) MarkExchangeQuoteUsed(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, markExchangeQuoteUsed, id)
+	return err
+}
+
+const markLockoutEventsUnlocked = `-- name: MarkLockoutEventsUnlocked :exec
+UPDATE app.lockout_event
+SET unlocked_at = now(), unlocked_by = $3
+WHERE scope = $1 AND subject = $2 AND unlocked_at IS NULL
+`
+
+type MarkLockoutEventsUnlockedParams struct {
+	Scope      string
+	Subject    string
+	UnlockedBy pgtype.Text
+}
+
+func (q *Queries) MarkLockoutEventsUnlocked(ctx context.Context, arg MarkLockoutEventsUnlockedParams) error {
+	_, err := q.db.Exec(ctx, markLockoutEventsUnlocked, arg.Scope, arg.Subject, arg.UnlockedBy)
+	return err
+}
+
+const markLoginChallengeUsed = `-- name: MarkLoginChallengeUsed :exec
+UPDATE app.login_challenge
+SET used_at = now()
+WHERE token_hash = $1
+`
+
+func (q *Queries) MarkLoginChallengeUsed(ctx context.Context, tokenHash string) error {
+	_, err := q.db.Exec(ctx, markLoginChallengeUsed, tokenHash)
+	return err
+}
+
+const markRefreshTokenUsed = `-- name: MarkRefreshTokenUsed :exec
+UPDATE app.refresh_token
+SET used_at = now()
+WHERE token_hash = $1
+`
+
+func (q *Queries) MarkRefreshTokenUsed(ctx context.Context, tokenHash string) error {
+	_, err := q.db.Exec(ctx, markRefreshTokenUsed, tokenHash)
+	return err
+}
+
+const recordLoginFailure = `-- name: RecordLoginFailure :one
+INSERT INTO app.login_attempt (scope, subject, failures, last_failed_at)
+VALUES ($1, $2, 1, now())
+ON CONFLICT (scope, subject) DO UPDATE
+SET failures = CASE
+        WHEN app.login_attempt.last_failed_at < $3 THEN 1
+        ELSE app.login_attempt.failures + 1
+    END,
+    last_failed_at = now()
+RETURNING scope, subject, failures, last_failed_at, locked_until
+`
+
+type RecordLoginFailureParams struct {
+	Scope       string
+	Subject     string
+	ResetBefore pgtype.Timestamptz
+}
+
+func (q *Queries) RecordLoginFailure(ctx context.Context, arg RecordLoginFailureParams) (AppLoginAttempt, error) {
+	row := q.db.QueryRow(ctx, recordLoginFailure, arg.Scope, arg.Subject, arg.ResetBefore)
+	var i AppLoginAttempt
+	err := row.Scan(
+		&i.Scope,
+		&i.Subject,
+		&i.Failures,
+		&i.LastFailedAt,
+		&i.LockedUntil,
+	)
+	return i, err
+}
+
+const rehashAccountPassword = `-- name: RehashAccountPassword :execrows
+UPDATE app.account
+SET password = $1
+WHERE email = $2 AND password = $3
+`
+
+type RehashAccountPasswordParams struct {
+	NewPassword     string
+	Email           string
+	CurrentPassword string
+}
+
+func (q *Queries) RehashAccountPassword(ctx context.Context, arg RehashAccountPasswordParams) (int64, error) {
+	result, err := q.db.Exec(ctx, rehashAccountPassword, arg.NewPassword, arg.Email, arg.CurrentPassword)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const revokeAPIKey = `-- name: RevokeAPIKey :execrows
+UPDATE app.api_key
+SET revoked_at = now()
+WHERE id = $1 AND email = $2 AND revoked_at IS NULL
+`
+
+type RevokeAPIKeyParams struct {
+	ID    pgtype.UUID
+	Email string
+}
+
+func (q *Queries) RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeAPIKey, arg.ID, arg.Email)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const revokeAccountSessions = `-- name: RevokeAccountSessions :exec
+UPDATE app.session
+SET revoked_at = now()
+WHERE email = $1 AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeAccountSessions(ctx context.Context, email string) error {
+	_, err := q.db.Exec(ctx, revokeAccountSessions, email)
+	return err
+}
+
+const revokeSession = `-- name: RevokeSession :exec
+UPDATE app.session
+SET revoked_at = now()
+WHERE id = $1 AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeSession(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, revokeSession, id)
+	return err
+}
+
+const saveIdempotencyResponse = `-- name: SaveIdempotencyResponse :exec
+UPDATE app.idempotency_key
+SET response = $3
+WHERE email = $1 AND key = $2
+`
+
+type SaveIdempotencyResponseParams struct {
+	Email    string
+	Key      string
+	Response []byte
+}
+
+func (q *Queries) SaveIdempotencyResponse(ctx context.Context, arg SaveIdempotencyResponseParams) error {
+	_, err := q.db.Exec(ctx, saveIdempotencyResponse, arg.Email, arg.Key, arg.Response)
+	return err
+}
+
+const searchAccounts = `-- name: SearchAccounts :many
+SELECT email, username, password, verified_at, role, frozen_at
+FROM app.account
+WHERE lower(email) LIKE '%' || lower($1::text) || '%'
+   OR lower(username) LIKE '%' || lower($1::text) || '%'
+ORDER BY email
+LIMIT $2
+`
+
+type SearchAccountsParams struct {
+	Query    string
+	PageSize int32
+}
+
+func (q *Queries) SearchAccounts(ctx context.Context, arg SearchAccountsParams) ([]AppAccount, error) {
+	rows, err := q.db.Query(ctx, searchAccounts, arg.Query, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AppAccount
+	for rows.Next() {
+		var i AppAccount
+		if err := rows.Scan(
+			&i.Email,
+			&i.Username,
+			&i.Password,
+			&i.VerifiedAt,
+			&i.Role,
+			&i.FrozenAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setAccountFrozen = `-- name: SetAccountFrozen :execrows
+UPDATE app.account
+SET frozen_at = CASE WHEN $1::bool THEN coalesce(frozen_at, now()) END
+WHERE email = $2
+`
+
+type SetAccountFrozenParams struct {
+	Frozen bool
+	Email  string
+}
+
+func (q *Queries) SetAccountFrozen(ctx context.Context, arg SetAccountFrozenParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setAccountFrozen, arg.Frozen, arg.Email)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setAccountRole = `-- name: SetAccountRole :execrows
+UPDATE app.account
+SET role = $2
+WHERE email = $1
+`
+
+type SetAccountRoleParams struct {
+	Email string
+	Role  string
+}
+
+func (q *Queries) SetAccountRole(ctx context.Context, arg SetAccountRoleParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setAccountRole, arg.Email, arg.Role)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setLoginLockedUntil = `-- name: SetLoginLockedUntil :exec
+UPDATE app.login_attempt
+SET locked_until = $3, failures = $4
+WHERE scope = $1 AND subject = $2
+`
+
+type SetLoginLockedUntilParams struct {
+	Scope       string
+	Subject     string
+	LockedUntil pgtype.Timestamptz
+	Failures    int32
+}
+
+func (q *Queries) SetLoginLockedUntil(ctx context.Context, arg SetLoginLockedUntilParams) error {
+	_, err := q.db.Exec(ctx, setLoginLockedUntil,
+		arg.Scope,
+		arg.Subject,
+		arg.LockedUntil,
+		arg.Failures,
+	)
+	return err
+}
+
+const takeRateLimitToken = `-- name: TakeRateLimitToken :one
+INSERT INTO app.rate_limit_bucket (key, tokens, allowed, updated_at)
+VALUES ($1, $2::float8 - 1, TRUE, now())
+ON CONFLICT (key) DO UPDATE
+SET tokens = CASE
+        WHEN LEAST($2::float8, app.rate_limit_bucket.tokens + EXTRACT(EPOCH FROM now() - app.rate_limit_bucket.updated_at)::float8 * $3::float8) >= 1
+        THEN LEAST($2::float8, app.rate_limit_bucket.tokens + EXTRACT(EPOCH FROM now() - app.rate_limit_bucket.updated_at)::float8 * $3::float8) - 1
+        ELSE LEAST($2::float8, app.rate_limit_bucket.tokens + EXTRACT(EPOCH FROM now() - app.rate_limit_bucket.updated_at)::float8 * $3::float8)
+    END,
+    allowed = LEAST($2::float8, app.rate_limit_bucket.tokens + EXTRACT(EPOCH FROM now() - app.rate_limit_bucket.updated_at)::float8 * $3::float8) >= 1,
+    updated_at = now()
+RETURNING tokens, allowed
+`
+
+type TakeRateLimitTokenParams struct {
+	Key        string
+	Capacity   float64
+	RefillRate float64
+}
+
+type TakeRateLimitTokenRow struct {
+	Tokens  float64
+	Allowed bool
+}
+
+func (q *Queries) TakeRateLimitToken(ctx context.Context, arg TakeRateLimitTokenParams) (TakeRateLimitTokenRow, error) {
+	row := q.db.QueryRow(ctx, takeRateLimitToken, arg.Key, arg.Capacity, arg.RefillRate)
+	var i TakeRateLimitTokenRow
+	err := row.Scan(&i.Tokens, &i.Allowed)
+	return i, err
+}
+
+const touchAPIKey = `-- name: TouchAPIKey :exec
+UPDATE app.api_key
+SET last_used_at = now()
+WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < now() - interval '1 minute')
+`
+
+func (q *Queries) TouchAPIKey(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, touchAPIKey, id)
+	return err
+}
+
+const updateAccountPassword = `-- name: UpdateAccountPassword :exec
+UPDATE app.account
+SET password = $2
+WHERE email = $1
+`
+
+type UpdateAccountPasswordParams struct {
+	Email    string
+	Password string
+}
+
+func (q *Queries) UpdateAccountPassword(ctx context.Context, arg UpdateAccountPasswordParams) error {
+	_, err := q.db.Exec(ctx, updateAccountPassword, arg.Email, arg.Password)
+	return err
+}
+
+const updateHoldStatus = `-- name: UpdateHoldStatus :one
+UPDATE app.hold
+SET status = $2, captured_amount = $3, updated_at = now()
+WHERE id = $1
+RETURNING id, email, currency, amount, captured_amount, status, expires_at, created_at, updated_at
+`
+
+type UpdateHoldStatusParams struct {
+	ID             pgtype.UUID
+	Status         string
+	CapturedAmount decimal.Decimal
+}
+
+func (q *Queries) UpdateHoldStatus(ctx context.Context, arg UpdateHoldStatusParams) (AppHold, error) {
+	row := q.db.QueryRow(ctx, updateHoldStatus, arg.ID, arg.Status, arg.CapturedAmount)
+	var i AppHold
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.Currency,
+		&i.Amount,
+		&i.CapturedAmount,
+		&i.Status,
+		&i.ExpiresAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateTOTPLastUsedStep = `-- name: UpdateTOTPLastUsedStep :exec
+UPDATE app.account_totp
+SET last_used_step = $2
+WHERE email = $1
+`
+
+type UpdateTOTPLastUsedStepParams struct {
+	Email        string
+	LastUsedStep int64
+}
+
+func (q *Queries) UpdateTOTPLastUsedStep(ctx context.Context, arg UpdateTOTPLastUsedStepParams) error {
+	_, err := q.db.Exec(ctx, updateTOTPLastUsedStep, arg.Email, arg.LastUsedStep)
+	return err
+}
+
+const updateWallet = `-- name: UpdateWallet :one
+UPDATE app.wallet
+SET balance = $3
+WHERE email = $1 and currency = $2
+RETURNING email, currency, balance
+`
+
+type UpdateWalletParams struct {
+	Email    string
+	Currency string
+	Balance  decimal.Decimal
+}
+
+func (q *Queries) UpdateWallet(ctx context.Context, arg UpdateWalletParams) (AppWallet, error) {
 	row := q.db.QueryRow(ctx, updateWallet, arg.Email, arg.Currency, arg.Balance)
 	var i AppWallet
 	err := row.Scan(&i.Email, &i.Currency, &i.Balance)
 	return i, err
 }
+
+const upsertAccountTOTP = `-- name: UpsertAccountTOTP :exec
+INSERT INTO app.account_totp (email, secret)
+VALUES ($1, $2)
+ON CONFLICT (email) DO UPDATE
+SET secret = EXCLUDED.secret, created_at = now()
+WHERE app.account_totp.enabled_at IS NULL
+`
+
+type UpsertAccountTOTPParams struct {
+	Email  string
+	Secret string
+}
+
+func (q *Queries) UpsertAccountTOTP(ctx context.Context, arg UpsertAccountTOTPParams) error {
+	_, err := q.db.Exec(ctx, upsertAccountTOTP, arg.Email, arg.Secret)
+	return err
+}
+
+const useEmailVerificationTokens = `-- name: UseEmailVerificationTokens :exec
+UPDATE app.email_verification_token
+SET used_at = now()
+WHERE email = $1 AND used_at IS NULL
+`
+
+func (q *Queries) UseEmailVerificationTokens(ctx context.Context, email string) error {
+	_, err := q.db.Exec(ctx, useEmailVerificationTokens, email)
+	return err
+}
+
+const usePasswordResetTokens = `-- name: UsePasswordResetTokens :exec
+UPDATE app.password_reset_token
+SET used_at = now()
+WHERE email = $1 AND used_at IS NULL
+`
+
+func (q *Queries) UsePasswordResetTokens(ctx context.Context, email string) error {
+	_, err := q.db.Exec(ctx, usePasswordResetTokens, email)
+	return err
+}
+
+const useRecoveryCode = `-- name: UseRecoveryCode :execrows
+UPDATE app.recovery_code
+SET used_at = now()
+WHERE email = $1 AND code_hash = $2 AND used_at IS NULL
+`
+
+type UseRecoveryCodeParams struct {
+	Email    string
+	CodeHash string
+}
+
+func (q *Queries) UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, useRecoveryCode, arg.Email, arg.CodeHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const verifyAccountEmail = `-- name: VerifyAccountEmail :exec
+UPDATE app.account
+SET verified_at = now()
+WHERE email = $1 AND verified_at IS NULL
+`
+
+func (q *Queries) VerifyAccountEmail(ctx context.Context, email string) error {
+	_, err := q.db.Exec(ctx, verifyAccountEmail, email)
+	return err
+}
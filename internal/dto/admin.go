@@ -0,0 +1,96 @@
+package dto
+
+import (
+	"gw-currency-wallet/pkg"
+	"time"
+)
+
+type AdminAccount struct {
+	Email    string `json:"email"`
+	Username string `json:"username"`
+	Role     string `json:"role" example:"user"`
+	Verified bool   `json:"verified"`
+	Frozen   bool   `json:"frozen"`
+}
+
+type AdminAccountsResponse struct {
+	Accounts []AdminAccount `json:"accounts"`
+}
+
+type AdminWalletsResponse struct {
+	Account AdminAccount             `json:"account"`
+	Balance map[string]WalletBalance `json:"balance"`
+}
+
+type AdminReasonRequest struct {
+	Reason string `json:"reason" binding:"required" example:"Suspicious activity, ticket #123"`
+}
+
+type SetRoleRequest struct {
+	Role   string `json:"role" binding:"required" example:"support"`
+	Reason string `json:"reason" binding:"required"`
+}
+
+type AdjustBalanceRequest struct {
+	// Amount положительная сумма зачисляется, отрицательная списывается
+	Amount   pkg.Amount `json:"amount" swaggertype:"string" example:"-10.50"`
+	Currency string     `json:"currency" binding:"required"`
+	Reason   string     `json:"reason" binding:"required" example:"Refund for failed transfer, ticket #123"`
+}
+
+type AdjustBalanceResponse struct {
+	Message       string             `json:"message"`
+	TransactionID int64              `json:"transaction_id"`
+	NewBalance    pkg.AccountWallets `json:"new_balance" swaggertype:"object,string"`
+}
+
+type UnlockLoginRequest struct {
+	// Scope account или ip
+	Scope   string `json:"scope" binding:"required" example:"account"`
+	Subject string `json:"subject" binding:"required" example:"alice@example.com"`
+	Reason  string `json:"reason" binding:"required"`
+}
+
+type LockoutEvent struct {
+	ID          int64      `json:"id"`
+	Scope       string     `json:"scope"`
+	Subject     string     `json:"subject"`
+	Failures    int        `json:"failures"`
+	LockedUntil time.Time  `json:"locked_until"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UnlockedAt  *time.Time `json:"unlocked_at,omitempty"`
+	UnlockedBy  string     `json:"unlocked_by,omitempty"`
+}
+
+type LockoutsResponse struct {
+	Lockouts []LockoutEvent `json:"lockouts"`
+}
+
+type AuditEntry struct {
+	ID        int64          `json:"id"`
+	Actor     string         `json:"actor"`
+	Action    string         `json:"action" example:"freeze"`
+	Subject   string         `json:"subject"`
+	Reason    string         `json:"reason"`
+	Details   map[string]any `json:"details,omitempty"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+type AuditLogResponse struct {
+	Entries []AuditEntry `json:"entries"`
+}
+
+type AdminSearchRequest struct {
+	Query string `form:"q"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type LockoutsRequest struct {
+	Scope   string `form:"scope" binding:"required,oneof=account ip"`
+	Subject string `form:"subject" binding:"required"`
+}
+
+type AuditLogRequest struct {
+	Subject string `form:"subject"`
+	Limit   int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
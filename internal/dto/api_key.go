@@ -0,0 +1,34 @@
+package dto
+
+import "time"
+
+type CreateAPIKeyRequest struct {
+	Name   string   `json:"name" binding:"required" example:"accounting export"`
+	Scopes []string `json:"scopes" binding:"required" example:"balance:read,transactions:read"`
+	// AllowedIPs адреса и подсети CIDR, с которых разрешено использовать ключ; пусто — любой адрес
+	AllowedIPs []string `json:"allowed_ips" example:"203.0.113.10,10.0.0.0/8"`
+	// ExpiresAt срок действия ключа; если не указан, ключ бессрочный
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type APIKey struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix" example:"3f9a1c0b7d2e"`
+	Scopes     []string   `json:"scopes"`
+	AllowedIPs []string   `json:"allowed_ips"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type CreateAPIKeyResponse struct {
+	APIKey
+	// Key показывается только один раз
+	Key string `json:"key" example:"gwk_3f9a1c0b7d2e_secret"`
+}
+
+type APIKeysResponse struct {
+	Keys []APIKey `json:"keys"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+type Currency struct {
+	Code       string `json:"code" example:"USD"`
+	Name       string `json:"name" example:"US Dollar"`
+	MinorUnits int32  `json:"minor_units" example:"2"`
+	Symbol     string `json:"symbol" example:"$"`
+	Enabled    bool   `json:"enabled"`
+}
+
+type GetCurrenciesResponse struct {
+	Currencies []Currency `json:"currencies"`
+}
@@ -0,0 +1,6 @@
+package dto
+
+type VerifyEmailRequest struct {
+	// Token токен из письма
+	Token string `json:"token" binding:"required"`
+}
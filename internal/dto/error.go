@@ -1,5 +1,13 @@
 package dto
 
-type ErrorMessage struct {
-	Error string `json:"error"`
+// Problem описание ошибки по RFC 7807 (application/problem+json). Клиентам следует
+// ветвиться по Code: он стабилен, а Detail может меняться.
+type Problem struct {
+	Type     string         `json:"type" example:"about:blank"`
+	Title    string         `json:"title" example:"Bad Request"`
+	Status   int            `json:"status" example:"400"`
+	Detail   string         `json:"detail" example:"insufficient balance"`
+	Instance string         `json:"instance,omitempty" example:"/api/v1/wallet/withdraw"`
+	Code     string         `json:"code" example:"insufficient_balance"`
+	Details  map[string]any `json:"details,omitempty"`
 }
@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"gw-currency-wallet/pkg"
+	"time"
+)
+
+type CreateHoldRequest struct {
+	Currency string     `json:"currency" binding:"required"`
+	Amount   pkg.Amount `json:"amount" swaggertype:"string" example:"40"`
+	// TTLSeconds срок действия холда в секундах, по умолчанию сутки
+	TTLSeconds int64 `json:"ttl_seconds" example:"3600"`
+}
+
+type CaptureHoldRequest struct {
+	// Amount сумма списания; если не указана, списывается весь холд
+	Amount *pkg.Amount `json:"amount" swaggertype:"string" example:"25"`
+}
+
+type HoldResponse struct {
+	ID             string     `json:"id"`
+	Currency       string     `json:"currency"`
+	Amount         pkg.Amount `json:"amount" swaggertype:"string"`
+	CapturedAmount pkg.Amount `json:"captured_amount" swaggertype:"string"`
+	Status         string     `json:"status" enums:"active,captured,voided,expired"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
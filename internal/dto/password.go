@@ -0,0 +1,11 @@
+package dto
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email" example:"alice@example.com"`
+}
+
+type ResetPasswordRequest struct {
+	// Token токен из письма
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
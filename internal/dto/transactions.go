@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"gw-currency-wallet/pkg"
+	"time"
+)
+
+type GetTransactionsRequest struct {
+	Currency string    `form:"currency"`
+	Type     string    `form:"type" binding:"omitempty,oneof=deposit withdraw exchange transfer capture adjustment opening_balance"`
+	From     time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To       time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Cursor   string    `form:"cursor"`
+	Limit    int       `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type TransactionEntry struct {
+	Currency string     `json:"currency"`
+	Amount   pkg.Amount `json:"amount" swaggertype:"string" example:"-10.50"`
+}
+
+type Transaction struct {
+	ID        int64              `json:"id"`
+	Type      string             `json:"type"`
+	CreatedAt time.Time          `json:"created_at"`
+	Entries   []TransactionEntry `json:"entries"`
+}
+
+type GetTransactionsResponse struct {
+	Transactions []Transaction `json:"transactions"`
+	NextCursor   string        `json:"next_cursor,omitempty"`
+}
@@ -2,44 +2,89 @@ package dto
 
 import (
 	"gw-currency-wallet/pkg"
+	"time"
 )
 
+type WalletBalance struct {
+	Available pkg.Amount `json:"available" swaggertype:"string" example:"60"`
+	Held      pkg.Amount `json:"held" swaggertype:"string" example:"40"`
+}
+
 type GetWalletsResponse struct {
-	Balance pkg.AccountWallets `json:"balance"`
+	Balance map[string]WalletBalance `json:"balance"`
 }
 
 type DepositRequest struct {
-	Amount   float32 `json:"amount" binding:"required,gt=0"`
-	Currency string  `json:"currency" binding:"required"`
+	Amount   pkg.Amount `json:"amount" swaggertype:"string" example:"100.50"`
+	Currency string     `json:"currency" binding:"required"`
 }
 
 type DepositResponse struct {
 	Message    string             `json:"message"`
-	NewBalance pkg.AccountWallets `json:"new_balance"`
+	NewBalance pkg.AccountWallets `json:"new_balance" swaggertype:"object,string"`
 }
 
 type WithdrawRequest struct {
-	Amount   float32 `json:"amount" binding:"required,gt=0"`
-	Currency string  `json:"currency" binding:"required"`
+	Amount   pkg.Amount `json:"amount" swaggertype:"string" example:"50.25"`
+	Currency string     `json:"currency" binding:"required"`
 }
 
 type WithdrawResponse struct {
 	Message    string             `json:"message"`
-	NewBalance pkg.AccountWallets `json:"new_balance"`
+	NewBalance pkg.AccountWallets `json:"new_balance" swaggertype:"object,string"`
 }
 
 type GetRatesResponse struct {
-	Rates pkg.ExchangeRates `json:"rates"`
+	Rates pkg.ExchangeRates `json:"rates" swaggertype:"object,string"`
 }
 
 type ExchangeRequest struct {
-	FromCurrency string  `json:"from_currency"`
-	ToCurrency   string  `json:"to_currency"`
-	Amount       float32 `json:"amount"`
+	FromCurrency string     `json:"from_currency"`
+	ToCurrency   string     `json:"to_currency"`
+	Amount       pkg.Amount `json:"amount" swaggertype:"string" example:"10"`
+	QuoteID      string     `json:"quote_id"`
+	// MinReceiveAmount минимальная сумма к получению, MaxRate максимальная цена
+	// единицы to_currency в from_currency
+	MinReceiveAmount *pkg.Amount `json:"min_receive_amount" swaggertype:"string" example:"9.1"`
+	MaxRate          *pkg.Rate   `json:"max_rate" swaggertype:"string" example:"1.1"`
+}
+
+type ExchangeQuoteRequest struct {
+	FromCurrency string     `json:"from_currency" binding:"required"`
+	ToCurrency   string     `json:"to_currency" binding:"required"`
+	Amount       pkg.Amount `json:"amount" swaggertype:"string" example:"10"`
+}
+
+type ExchangeQuoteResponse struct {
+	QuoteID       string     `json:"quote_id"`
+	FromCurrency  string     `json:"from_currency"`
+	ToCurrency    string     `json:"to_currency"`
+	Amount        pkg.Amount `json:"amount" swaggertype:"string"`
+	Rate          pkg.Rate   `json:"rate" swaggertype:"string"`
+	GrossAmount   pkg.Amount `json:"gross_amount" swaggertype:"string"`
+	Fee           pkg.Amount `json:"fee" swaggertype:"string"`
+	ReceiveAmount pkg.Amount `json:"receive_amount" swaggertype:"string"`
+	ExpiresAt     time.Time  `json:"expires_at"`
 }
 
 type ExchangeResponse struct {
-	Message         string             `json:"message"`
-	ExchangedAmount float32            `json:"exchanged_amount"`
-	NewBalance      pkg.AccountWallets `json:"new_balance"`
+	Message string `json:"message"`
+	// ExchangedAmount совпадает с NetAmount и оставлен для совместимости
+	ExchangedAmount pkg.Amount         `json:"exchanged_amount" swaggertype:"string"`
+	Rate            pkg.Rate           `json:"rate" swaggertype:"string"`
+	GrossAmount     pkg.Amount         `json:"gross_amount" swaggertype:"string"`
+	Fee             pkg.Amount         `json:"fee" swaggertype:"string"`
+	NetAmount       pkg.Amount         `json:"net_amount" swaggertype:"string"`
+	NewBalance      pkg.AccountWallets `json:"new_balance" swaggertype:"object,string"`
+}
+
+type TransferRequest struct {
+	To       string     `json:"to" binding:"required" example:"alice"`
+	Currency string     `json:"currency" binding:"required"`
+	Amount   pkg.Amount `json:"amount" swaggertype:"string" example:"25"`
+}
+
+type TransferResponse struct {
+	Message    string             `json:"message"`
+	NewBalance pkg.AccountWallets `json:"new_balance" swaggertype:"object,string"`
 }
@@ -1,43 +1,35 @@
 package handler
 
 import (
-	"errors"
 	"gw-currency-wallet/internal/dto"
-	"gw-currency-wallet/internal/service"
+	"gw-currency-wallet/internal/models"
 
 	"github.com/gin-gonic/gin"
-	"go.uber.org/zap"
 )
 
 // Register godoc
 // @Summary Регистрация пользователя
-// @Description Регистрация нового пользователя.
+// @Description Регистрация нового пользователя. Пароль должен быть не короче минимальной длины (по умолчанию 8 символов),
+// @Description не входить в список утёкших паролей и не содержать имя пользователя.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param request body dto.RegisterRequest true "User registration data"
 // @Success 201 {object} dto.Message "User registered successfully"
-// @Failure 400 {object} dto.Message "Username or email already exists"
+// @Failure 400 {object} dto.Problem "Username or email already exists or password does not meet the policy"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
 // @Router /api/v1/register [post]
 func (h *Handler) Register(c *gin.Context) {
 	var in dto.RegisterRequest
 
 	if err := c.BindJSON(&in); err != nil {
-		sendBadRequest(c, err)
+		sendInvalidRequest(c, err)
 		return
 	}
 
 	_, err := h.s.Account.Register(c, in.Email, in.Username, in.Password)
 	if err != nil {
-		switch {
-		case errors.Is(err, service.ErrEmailAlreadyExists):
-			sendBadRequest(c, err)
-		case errors.Is(err, service.ErrUsernameAlreadyExists):
-			sendBadRequest(c, err)
-		default:
-			zap.L().Error(err.Error())
-			sendInternalError(c)
-		}
+		sendError(c, err)
 		return
 	}
 
@@ -46,35 +38,129 @@ func (h *Handler) Register(c *gin.Context) {
 
 // Login godoc
 // @Summary Авторизация пользователя
-// @Description Авторизация пользователя. При успешной авторизации возвращается JWT-токен.
+// @Description Авторизация пользователя по имени пользователя или email без учёта регистра. При успешной авторизации
+// @Description возвращается короткоживущий JWT-токен и refresh-токен для его обновления. Если включена двухфакторная
+// @Description аутентификация, возвращается 202 с токеном второго шага для /api/v1/login/2fa.
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param request body dto.LoginRequest true "User login data"
 // @Success 200 {object} dto.LoginResponse "JWT-token"
-// @Failure 401 {object} dto.Message "Invalid username or password"
+// @Success 202 {object} dto.TwoFactorChallengeResponse "Two-factor authentication required"
+// @Failure 400 {object} dto.Problem "Invalid username or password"
+// @Failure 429 {object} dto.Problem "Too many failed attempts or rate limit exceeded, see Retry-After"
 // @Router /api/v1/login [post]
 func (h *Handler) Login(c *gin.Context) {
 	var in dto.LoginRequest
 
 	if err := c.BindJSON(&in); err != nil {
-		sendBadRequest(c, err)
+		sendInvalidRequest(c, err)
 		return
 	}
 
-	token, err := h.s.Account.Login(c, in.Username, in.Password)
+	result, err := h.s.Account.Login(c, in.Username, in.Password, c.ClientIP())
 	if err != nil {
-		switch {
-		case errors.Is(err, service.ErrInvalidCredentials):
-			sendBadRequest(c, err)
-		default:
-			zap.L().Error(err.Error())
-			sendInternalError(c)
-		}
+		sendError(c, err)
 		return
 	}
 
-	sendOK(c, &dto.LoginResponse{
-		Token: token,
-	})
+	if result.Challenge != nil {
+		sendAccepted(c, &dto.TwoFactorChallengeResponse{
+			Message:        "Two-factor authentication required",
+			ChallengeToken: result.Challenge.Token,
+			ExpiresAt:      result.Challenge.ExpiresAt,
+		})
+		return
+	}
+
+	sendOK(c, toLoginResponse(result.Tokens))
+}
+
+// RefreshToken godoc
+// @Summary Обновление токена
+// @Description Обменивает refresh-токен на новую пару токенов. Refresh-токен одноразовый:
+// @Description повторное использование отзывает сессию.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.RefreshTokenRequest true "Refresh-токен"
+// @Success 200 {object} dto.LoginResponse "New token pair"
+// @Failure 401 {object} dto.Problem "Invalid, expired or reused refresh token"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Router /api/v1/token/refresh [post]
+func (h *Handler) RefreshToken(c *gin.Context) {
+	var in dto.RefreshTokenRequest
+
+	if err := c.BindJSON(&in); err != nil {
+		sendInvalidRequest(c, err)
+		return
+	}
+
+	tokens, err := h.s.Session.Refresh(c, in.RefreshToken)
+	if err != nil {
+		sendError(c, err)
+		return
+	}
+
+	sendOK(c, toLoginResponse(tokens))
+}
+
+// Logout godoc
+// @Summary Выход
+// @Description Завершает текущую сессию: её access- и refresh-токены перестают действовать.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} dto.Message "Logged out"
+// @Failure 401 {object} dto.Problem "Unauthorized"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/logout [post]
+// @Security BearerAuth
+func (h *Handler) Logout(c *gin.Context) {
+	sessionID, ok := getSessionFromContext(c)
+	if !ok {
+		sendInternalError(c)
+		return
+	}
+
+	if err := h.s.Session.Revoke(c, sessionID); err != nil {
+		sendError(c, err)
+		return
+	}
+
+	sendOK(c, &dto.Message{Message: "Logged out"})
+}
+
+// LogoutAll godoc
+// @Summary Выход на всех устройствах
+// @Description Завершает все сессии пользователя, включая текущую.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} dto.Message "Logged out everywhere"
+// @Failure 401 {object} dto.Problem "Unauthorized"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/logout/all [post]
+// @Security BearerAuth
+func (h *Handler) LogoutAll(c *gin.Context) {
+	email, ok := getAccountFromContext(c)
+	if !ok {
+		sendInternalError(c)
+		return
+	}
+
+	if err := h.s.Session.RevokeAll(c, email); err != nil {
+		sendError(c, err)
+		return
+	}
+
+	sendOK(c, &dto.Message{Message: "Logged out everywhere"})
+}
+
+func toLoginResponse(tokens *models.TokenPair) *dto.LoginResponse {
+	return &dto.LoginResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresAt:    tokens.AccessExpiresAt,
+	}
 }
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"context"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testAccount отклоняет вход и запоминает IP, по которому LoginGuard считал бы попытку
+type testAccount struct {
+	service.Account
+	frozen   bool
+	clientIP string
+}
+
+func (a *testAccount) Find(_ context.Context, email string) (*models.Account, error) {
+	return &models.Account{Email: email, Frozen: a.frozen}, nil
+}
+
+func (a *testAccount) Login(_ context.Context, _, _, clientIP string) (*models.LoginResult, error) {
+	a.clientIP = clientIP
+	return nil, service.ErrInvalidCredentials
+}
+
+func TestLogin_SpoofedForwardedFor_CountsConnectionIP(t *testing.T) {
+	account := &testAccount{}
+	router := newTestRouter(&service.Service{Account: account, RateLimit: &testRateLimit{}})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/login", strings.NewReader(`{"username":"user","password":"wrong-password"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Forwarded-For", "192.0.2.99")
+	w := serve(router, req, "203.0.113.5:41000")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "203.0.113.5", account.clientIP)
+}
@@ -0,0 +1,398 @@
+package handler
+
+import (
+	"gw-currency-wallet/internal/dto"
+	"gw-currency-wallet/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchAccounts godoc
+// @Summary Поиск аккаунтов
+// @Description Ищет аккаунты, email или имя пользователя которых содержит строку q, без учёта регистра.
+// @Description Требуется роль support или admin.
+// @Tags admin
+// @Produce json
+// @Param q query string false "Часть email или имени пользователя"
+// @Param limit query int false "Размер страницы (1-100, по умолчанию 20)"
+// @Success 200 {object} dto.AdminAccountsResponse "Accounts"
+// @Failure 401 {object} dto.Problem "Unauthorized"
+// @Failure 403 {object} dto.Problem "Permission denied"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/admin/accounts [get]
+// @Security BearerAuth
+func (h *Handler) SearchAccounts(c *gin.Context) {
+	var in dto.AdminSearchRequest
+
+	if err := c.BindQuery(&in); err != nil {
+		sendInvalidRequest(c, err)
+		return
+	}
+
+	accounts, err := h.s.Admin.SearchAccounts(c, in.Query, in.Limit)
+	if err != nil {
+		sendError(c, err)
+		return
+	}
+
+	out := make([]dto.AdminAccount, 0, len(accounts))
+	for _, account := range accounts {
+		out = append(out, toAdminAccount(&account))
+	}
+
+	sendOK(c, &dto.AdminAccountsResponse{
+		Accounts: out,
+	})
+}
+
+// GetAccountWallets godoc
+// @Summary Кошельки аккаунта
+// @Description Возвращает данные аккаунта и балансы всех его кошельков. Требуется роль support или admin.
+// @Tags admin
+// @Produce json
+// @Param email path string true "Email аккаунта"
+// @Success 200 {object} dto.AdminWalletsResponse "Account wallets"
+// @Failure 401 {object} dto.Problem "Unauthorized"
+// @Failure 403 {object} dto.Problem "Permission denied"
+// @Failure 404 {object} dto.Problem "Account not found"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/admin/accounts/{email}/wallets [get]
+// @Security BearerAuth
+func (h *Handler) GetAccountWallets(c *gin.Context) {
+	account, err := h.s.Admin.GetAccount(c, c.Param("email"))
+	if err != nil {
+		sendError(c, err)
+		return
+	}
+
+	balances, err := h.s.Admin.Balances(c, account.Email)
+	if err != nil {
+		sendError(c, err)
+		return
+	}
+
+	out := make(map[string]dto.WalletBalance, len(balances))
+	for currency, balance := range balances {
+		out[currency] = dto.WalletBalance{
+			Available: balance.Available,
+			Held:      balance.Held,
+		}
+	}
+
+	sendOK(c, &dto.AdminWalletsResponse{
+		Account: toAdminAccount(account),
+		Balance: out,
+	})
+}
+
+// FreezeAccount godoc
+// @Summary Заморозка аккаунта
+// @Description Запрещает аккаунту операции со средствами; вход и просмотр баланса остаются доступны.
+// @Description Причина обязательна и записывается в журнал действий. Требуется роль admin.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param email path string true "Email аккаунта"
+// @Param request body dto.AdminReasonRequest true "Причина"
+// @Success 200 {object} dto.Message "Account frozen"
+// @Failure 400 {object} dto.Problem "Reason is required"
+// @Failure 401 {object} dto.Problem "Unauthorized"
+// @Failure 403 {object} dto.Problem "Permission denied"
+// @Failure 404 {object} dto.Problem "Account not found"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/admin/accounts/{email}/freeze [post]
+// @Security BearerAuth
+func (h *Handler) FreezeAccount(c *gin.Context) {
+	h.setFrozen(c, true)
+}
+
+// UnfreezeAccount godoc
+// @Summary Разморозка аккаунта
+// @Description Снимает запрет на операции со средствами. Причина обязательна и записывается в журнал действий.
+// @Description Требуется роль admin.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param email path string true "Email аккаунта"
+// @Param request body dto.AdminReasonRequest true "Причина"
+// @Success 200 {object} dto.Message "Account unfrozen"
+// @Failure 400 {object} dto.Problem "Reason is required"
+// @Failure 401 {object} dto.Problem "Unauthorized"
+// @Failure 403 {object} dto.Problem "Permission denied"
+// @Failure 404 {object} dto.Problem "Account not found"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/admin/accounts/{email}/unfreeze [post]
+// @Security BearerAuth
+func (h *Handler) UnfreezeAccount(c *gin.Context) {
+	h.setFrozen(c, false)
+}
+
+// SetAccountRole godoc
+// @Summary Смена роли аккаунта
+// @Description Назначает роль user, support или admin и завершает все сессии аккаунта, чтобы новая роль
+// @Description действовала сразу. Свою роль изменить нельзя. Требуется роль admin.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param email path string true "Email аккаунта"
+// @Param request body dto.SetRoleRequest true "Роль и причина"
+// @Success 200 {object} dto.Message "Role updated"
+// @Failure 400 {object} dto.Problem "Invalid role, missing reason or own account"
+// @Failure 401 {object} dto.Problem "Unauthorized"
+// @Failure 403 {object} dto.Problem "Permission denied"
+// @Failure 404 {object} dto.Problem "Account not found"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/admin/accounts/{email}/role [put]
+// @Security BearerAuth
+func (h *Handler) SetAccountRole(c *gin.Context) {
+	var in dto.SetRoleRequest
+
+	if err := c.BindJSON(&in); err != nil {
+		sendInvalidRequest(c, err)
+		return
+	}
+
+	actor, ok := getAccountFromContext(c)
+	if !ok {
+		sendInternalError(c)
+		return
+	}
+
+	if err := h.s.Admin.SetRole(c, actor, c.Param("email"), in.Role, in.Reason); err != nil {
+		sendError(c, err)
+		return
+	}
+
+	sendOK(c, &dto.Message{Message: "Role updated"})
+}
+
+// AdjustBalance godoc
+// @Summary Ручная корректировка баланса
+// @Description Зачисляет положительную или списывает отрицательную сумму и записывает корректировку в журнал
+// @Description операций и в журнал действий вместе с обязательной причиной. Списание не может превышать
+// @Description доступный баланс. Работает и для замороженных аккаунтов. Требуется роль admin.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param email path string true "Email аккаунта"
+// @Param request body dto.AdjustBalanceRequest true "Сумма, валюта и причина"
+// @Param Idempotency-Key header string false "Ключ идемпотентности для безопасного повтора запроса"
+// @Success 200 {object} dto.AdjustBalanceResponse "Balance adjusted"
+// @Failure 400 {object} dto.Problem "Invalid amount or currency, insufficient funds or missing reason"
+// @Failure 401 {object} dto.Problem "Unauthorized"
+// @Failure 403 {object} dto.Problem "Permission denied"
+// @Failure 404 {object} dto.Problem "Account not found"
+// @Failure 409 {object} dto.Problem "Idempotency key reused with a different request"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/admin/accounts/{email}/adjustments [post]
+// @Security BearerAuth
+func (h *Handler) AdjustBalance(c *gin.Context) {
+	var in dto.AdjustBalanceRequest
+
+	if err := c.BindJSON(&in); err != nil {
+		sendInvalidRequest(c, err)
+		return
+	}
+
+	actor, ok := getAccountFromContext(c)
+	if !ok {
+		sendInternalError(c)
+		return
+	}
+
+	transactionID, wallets, err := h.s.Admin.AdjustBalance(c, actor, c.Param("email"), c.GetHeader(IdempotencyKeyHeader), in.Currency, in.Amount, in.Reason)
+	if err != nil {
+		sendError(c, err)
+		return
+	}
+
+	sendOK(c, &dto.AdjustBalanceResponse{
+		Message:       "Balance adjusted",
+		TransactionID: transactionID,
+		NewBalance:    wallets,
+	})
+}
+
+// GetLockouts godoc
+// @Summary История блокировок входа
+// @Description Возвращает последние блокировки входа по аккаунту (email) или IP-адресу. Требуется роль support или admin.
+// @Tags admin
+// @Produce json
+// @Param scope query string true "Область блокировки" Enums(account, ip)
+// @Param subject query string true "Email аккаунта или IP-адрес"
+// @Success 200 {object} dto.LockoutsResponse "Lockout events"
+// @Failure 400 {object} dto.Problem "Invalid scope"
+// @Failure 401 {object} dto.Problem "Unauthorized"
+// @Failure 403 {object} dto.Problem "Permission denied"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/admin/lockouts [get]
+// @Security BearerAuth
+func (h *Handler) GetLockouts(c *gin.Context) {
+	var in dto.LockoutsRequest
+
+	if err := c.BindQuery(&in); err != nil {
+		sendInvalidRequest(c, err)
+		return
+	}
+
+	events, err := h.s.Admin.LoginLockouts(c, in.Scope, in.Subject)
+	if err != nil {
+		sendError(c, err)
+		return
+	}
+
+	out := make([]dto.LockoutEvent, 0, len(events))
+	for _, event := range events {
+		out = append(out, dto.LockoutEvent{
+			ID:          event.ID,
+			Scope:       event.Scope,
+			Subject:     event.Subject,
+			Failures:    event.Failures,
+			LockedUntil: event.LockedUntil,
+			CreatedAt:   event.CreatedAt,
+			UnlockedAt:  event.UnlockedAt,
+			UnlockedBy:  event.UnlockedBy,
+		})
+	}
+
+	sendOK(c, &dto.LockoutsResponse{
+		Lockouts: out,
+	})
+}
+
+// UnlockLogin godoc
+// @Summary Снятие блокировки входа
+// @Description Сбрасывает счётчик неудачных попыток и снимает блокировку входа по аккаунту (email) или IP-адресу.
+// @Description Причина обязательна и записывается в журнал действий. Требуется роль support или admin.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body dto.UnlockLoginRequest true "Область, субъект и причина"
+// @Success 200 {object} dto.Message "Login unlocked"
+// @Failure 400 {object} dto.Problem "Invalid scope or missing reason"
+// @Failure 401 {object} dto.Problem "Unauthorized"
+// @Failure 403 {object} dto.Problem "Permission denied"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/admin/lockouts/unlock [post]
+// @Security BearerAuth
+func (h *Handler) UnlockLogin(c *gin.Context) {
+	var in dto.UnlockLoginRequest
+
+	if err := c.BindJSON(&in); err != nil {
+		sendInvalidRequest(c, err)
+		return
+	}
+
+	actor, ok := getAccountFromContext(c)
+	if !ok {
+		sendInternalError(c)
+		return
+	}
+
+	if err := h.s.Admin.UnlockLogin(c, actor, in.Scope, in.Subject, in.Reason); err != nil {
+		sendError(c, err)
+		return
+	}
+
+	sendOK(c, &dto.Message{Message: "Login unlocked"})
+}
+
+// GetAuditLog godoc
+// @Summary Журнал действий администраторов
+// @Description Возвращает записи журнала от новых к старым, при необходимости только по одному аккаунту или IP-адресу.
+// @Description Требуется роль support или admin.
+// @Tags admin
+// @Produce json
+// @Param subject query string false "Email аккаунта или IP-адрес"
+// @Param limit query int false "Размер страницы (1-100, по умолчанию 20)"
+// @Success 200 {object} dto.AuditLogResponse "Audit log"
+// @Failure 401 {object} dto.Problem "Unauthorized"
+// @Failure 403 {object} dto.Problem "Permission denied"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/admin/audit [get]
+// @Security BearerAuth
+func (h *Handler) GetAuditLog(c *gin.Context) {
+	var in dto.AuditLogRequest
+
+	if err := c.BindQuery(&in); err != nil {
+		sendInvalidRequest(c, err)
+		return
+	}
+
+	entries, err := h.s.Admin.AuditLog(c, in.Subject, in.Limit)
+	if err != nil {
+		sendError(c, err)
+		return
+	}
+
+	out := make([]dto.AuditEntry, 0, len(entries))
+	for _, entry := range entries {
+		out = append(out, dto.AuditEntry{
+			ID:        entry.ID,
+			Actor:     entry.Actor,
+			Action:    entry.Action,
+			Subject:   entry.Subject,
+			Reason:    entry.Reason,
+			Details:   entry.Details,
+			CreatedAt: entry.CreatedAt,
+		})
+	}
+
+	sendOK(c, &dto.AuditLogResponse{
+		Entries: out,
+	})
+}
+
+func (h *Handler) setFrozen(c *gin.Context, frozen bool) {
+	var in dto.AdminReasonRequest
+
+	if err := c.BindJSON(&in); err != nil {
+		sendInvalidRequest(c, err)
+		return
+	}
+
+	actor, ok := getAccountFromContext(c)
+	if !ok {
+		sendInternalError(c)
+		return
+	}
+
+	email := c.Param("email")
+
+	var err error
+	if frozen {
+		err = h.s.Admin.Freeze(c, actor, email, in.Reason)
+	} else {
+		err = h.s.Admin.Unfreeze(c, actor, email, in.Reason)
+	}
+	if err != nil {
+		sendError(c, err)
+		return
+	}
+
+	if frozen {
+		sendOK(c, &dto.Message{Message: "Account frozen"})
+		return
+	}
+
+	sendOK(c, &dto.Message{Message: "Account unfrozen"})
+}
+
+func toAdminAccount(account *models.Account) dto.AdminAccount {
+	return dto.AdminAccount{
+		Email:    account.Email,
+		Username: account.Username,
+		Role:     account.Role,
+		Verified: account.Verified,
+		Frozen:   account.Frozen,
+	}
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"gw-currency-wallet/internal/dto"
+	"gw-currency-wallet/internal/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateAPIKey godoc
+// @Summary Создание ключа API
+// @Description Создаёт ключ API для машинного клиента с указанными областями доступа, сроком действия
+// @Description и списком разрешённых адресов. Ключ возвращается только в этом ответе.
+// @Description Области: balance:read, transactions:read, wallet:deposit, wallet:withdraw, holds:manage,
+// @Description exchange:read, exchange:execute, transfer:execute.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateAPIKeyRequest true "Имя, области доступа, адреса и срок действия"
+// @Success 201 {object} dto.CreateAPIKeyResponse "API key created"
+// @Failure 400 {object} dto.Problem "Invalid name, scopes, IP allowlist or expiry"
+// @Failure 401 {object} dto.Problem "Unauthorized"
+// @Failure 403 {object} dto.Problem "API keys cannot manage API keys"
+// @Failure 409 {object} dto.Problem "Too many active API keys"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/api-keys [post]
+// @Security BearerAuth
+func (h *Handler) CreateAPIKey(c *gin.Context) {
+	var in dto.CreateAPIKeyRequest
+
+	if err := c.BindJSON(&in); err != nil {
+		sendInvalidRequest(c, err)
+		return
+	}
+
+	email, ok := getAccountFromContext(c)
+	if !ok {
+		sendInternalError(c)
+		return
+	}
+
+	key, err := h.s.APIKey.Create(c, email, in.Name, in.Scopes, in.AllowedIPs, in.ExpiresAt)
+	if err != nil {
+		sendError(c, err)
+		return
+	}
+
+	send(c, http.StatusCreated, &dto.CreateAPIKeyResponse{
+		APIKey: toAPIKeyResponse(&key.APIKey),
+		Key:    key.Key,
+	})
+}
+
+// GetAPIKeys godoc
+// @Summary Список ключей API
+// @Description Возвращает все ключи API пользователя, включая отозванные. Секреты ключей не возвращаются.
+// @Tags api-keys
+// @Produce json
+// @Success 200 {object} dto.APIKeysResponse "API keys"
+// @Failure 401 {object} dto.Problem "Unauthorized"
+// @Failure 403 {object} dto.Problem "API keys cannot manage API keys"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/api-keys [get]
+// @Security BearerAuth
+func (h *Handler) GetAPIKeys(c *gin.Context) {
+	email, ok := getAccountFromContext(c)
+	if !ok {
+		sendInternalError(c)
+		return
+	}
+
+	keys, err := h.s.APIKey.List(c, email)
+	if err != nil {
+		sendError(c, err)
+		return
+	}
+
+	out := dto.APIKeysResponse{Keys: make([]dto.APIKey, 0, len(keys))}
+	for i := range keys {
+		out.Keys = append(out.Keys, toAPIKeyResponse(&keys[i]))
+	}
+
+	sendOK(c, out)
+}
+
+// RevokeAPIKey godoc
+// @Summary Отзыв ключа API
+// @Description Отзывает ключ API пользователя. Отозванный ключ сразу перестаёт действовать.
+// @Tags api-keys
+// @Produce json
+// @Param id path string true "Идентификатор ключа"
+// @Success 200 {object} dto.Message "API key revoked"
+// @Failure 401 {object} dto.Problem "Unauthorized"
+// @Failure 403 {object} dto.Problem "API keys cannot manage API keys"
+// @Failure 404 {object} dto.Problem "API key not found"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/api-keys/{id} [delete]
+// @Security BearerAuth
+func (h *Handler) RevokeAPIKey(c *gin.Context) {
+	email, ok := getAccountFromContext(c)
+	if !ok {
+		sendInternalError(c)
+		return
+	}
+
+	if err := h.s.APIKey.Revoke(c, email, c.Param("id")); err != nil {
+		sendError(c, err)
+		return
+	}
+
+	sendOK(c, &dto.Message{Message: "API key revoked"})
+}
+
+func toAPIKeyResponse(key *models.APIKey) dto.APIKey {
+	return dto.APIKey{
+		ID:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		AllowedIPs: key.AllowedIPs,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"gw-currency-wallet/internal/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCurrencies godoc
+// @Summary Справочник валют
+// @Description Возвращает валюты по ISO 4217: код, название, число знаков после запятой, символ и признак доступности.
+// @Tags currency
+// @Produce json
+// @Success 200 {object} dto.GetCurrenciesResponse "Currency registry"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/currencies [get]
+func (h *Handler) GetCurrencies(c *gin.Context) {
+	currencies, err := h.s.Currency.List(c)
+	if err != nil {
+		sendError(c, err)
+		return
+	}
+
+	out := make([]dto.Currency, 0, len(currencies))
+	for _, currency := range currencies {
+		out = append(out, dto.Currency{
+			Code:       currency.Code,
+			Name:       currency.Name,
+			MinorUnits: currency.MinorUnits,
+			Symbol:     currency.Symbol,
+			Enabled:    currency.Enabled,
+		})
+	}
+
+	sendOK(c, &dto.GetCurrenciesResponse{
+		Currencies: out,
+	})
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"gw-currency-wallet/internal/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VerifyEmailLink godoc
+// @Summary Подтверждение email по ссылке
+// @Description Подтверждает email по токену из ссылки в письме. Токен действует 24 часа.
+// @Tags auth
+// @Produce json
+// @Param token query string true "Токен из письма"
+// @Success 200 {object} dto.Message "Email verified"
+// @Failure 400 {object} dto.Problem "Invalid or expired token"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/verify-email [get]
+func (h *Handler) VerifyEmailLink(c *gin.Context) {
+	h.verifyEmail(c, c.Query("token"))
+}
+
+// VerifyEmail godoc
+// @Summary Подтверждение email
+// @Description Подтверждает email по токену из письма. Токен действует 24 часа.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.VerifyEmailRequest true "Токен из письма"
+// @Success 200 {object} dto.Message "Email verified"
+// @Failure 400 {object} dto.Problem "Invalid or expired token"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/verify-email [post]
+func (h *Handler) VerifyEmail(c *gin.Context) {
+	var in dto.VerifyEmailRequest
+
+	if err := c.BindJSON(&in); err != nil {
+		sendInvalidRequest(c, err)
+		return
+	}
+
+	h.verifyEmail(c, in.Token)
+}
+
+// ResendVerificationEmail godoc
+// @Summary Повторная отправка письма подтверждения
+// @Description Отправляет новое письмо для подтверждения email не чаще раза в минуту.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} dto.Message "Verification email sent"
+// @Failure 401 {object} dto.Problem "Unauthorized"
+// @Failure 409 {object} dto.Problem "Email is already verified"
+// @Failure 429 {object} dto.Problem "Verification email was sent recently or rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/verify-email/resend [post]
+// @Security BearerAuth
+func (h *Handler) ResendVerificationEmail(c *gin.Context) {
+	email, ok := getAccountFromContext(c)
+	if !ok {
+		sendInternalError(c)
+		return
+	}
+
+	if err := h.s.EmailVerification.Resend(c, email); err != nil {
+		sendError(c, err)
+		return
+	}
+
+	sendOK(c, &dto.Message{Message: "Verification email sent"})
+}
+
+func (h *Handler) verifyEmail(c *gin.Context, token string) {
+	if err := h.s.EmailVerification.Verify(c, token); err != nil {
+		sendError(c, err)
+		return
+	}
+
+	sendOK(c, &dto.Message{Message: "Email verified"})
+}
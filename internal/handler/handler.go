@@ -1,13 +1,22 @@
 package handler
 
-import "gw-currency-wallet/internal/service"
+import (
+	"gw-currency-wallet/config"
+	"gw-currency-wallet/internal/service"
+	"gw-currency-wallet/pkg/metrics"
+)
 
 type Handler struct {
-	s *service.Service
+	s              *service.Service
+	m              *metrics.Metrics
+	trustedProxies []string
 }
 
-func NewHandler(srv *service.Service) *Handler {
+// NewHandler создаёт обработчики. Без m маршрут /metrics не регистрируется.
+func NewHandler(srv *service.Service, cfg *config.ServerConfig, m *metrics.Metrics) *Handler {
 	return &Handler{
-		s: srv,
+		s:              srv,
+		m:              m,
+		trustedProxies: cfg.TrustedProxies,
 	}
 }
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"context"
+	"gw-currency-wallet/config"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/service"
+	"gw-currency-wallet/pkg"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testEmail = "user@example.com"
+
+// testAPIKey ключ API, разрешённый только с allowedIP; запоминает IP последнего запроса
+type testAPIKey struct {
+	service.APIKey
+	allowedIP string
+	scopes    []models.Scope
+	clientIP  string
+}
+
+func (k *testAPIKey) Authenticate(_ context.Context, _, clientIP string) (*models.APIKey, error) {
+	k.clientIP = clientIP
+	if clientIP != k.allowedIP {
+		return nil, service.ErrAPIKeyIPNotAllowed
+	}
+
+	return &models.APIKey{
+		Email:  testEmail,
+		Scopes: k.scopes,
+	}, nil
+}
+
+// testRateLimit пропускает все запросы и запоминает, по какому клиенту они посчитаны
+type testRateLimit struct {
+	subjects []string
+}
+
+func (l *testRateLimit) Take(_ context.Context, _ models.RateLimitGroup, subject string) (*models.RateLimitResult, error) {
+	l.subjects = append(l.subjects, subject)
+	return &models.RateLimitResult{Allowed: true, Limit: 10, Remaining: 9, Period: time.Minute}, nil
+}
+
+type testWallet struct {
+	service.Wallet
+	ratesErr error
+}
+
+func (w *testWallet) GetBalances(context.Context, string) (map[pkg.Currency]models.WalletBalance, error) {
+	return map[pkg.Currency]models.WalletBalance{}, nil
+}
+
+func (w *testWallet) GetRates(context.Context) (pkg.ExchangeRates, error) {
+	return nil, w.ratesErr
+}
+
+type testCurrency struct {
+	service.Currency
+}
+
+func (testCurrency) List(context.Context) ([]models.Currency, error) {
+	return nil, nil
+}
+
+func newTestRouter(s *service.Service, trustedProxies ...string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return NewHandler(s, &config.ServerConfig{TrustedProxies: trustedProxies}, nil).Router()
+}
+
+// serve выполняет запрос с адреса remoteAddr
+func serve(router *gin.Engine, req *http.Request, remoteAddr string) *httptest.ResponseRecorder {
+	req.RemoteAddr = remoteAddr
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"gw-currency-wallet/internal/dto"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/service"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CreateHold godoc
+// @Summary Резервирование средств
+// @Description Создаёт холд: сумма уменьшает доступный баланс, но не общий. Холд действует ttl_seconds секунд (по умолчанию сутки, не больше 30 дней).
+// @Tags holds
+// @Accept json
+// @Produce json
+// @Param input body dto.CreateHoldRequest true "Данные холда"
+// @Param Idempotency-Key header string false "Ключ идемпотентности для безопасного повтора запроса"
+// @Success 200 {object} dto.HoldResponse "Hold created"
+// @Failure 400 {object} dto.Problem "Invalid amount, currency or insufficient funds"
+// @Failure 403 {object} dto.Problem "Email is not verified or account is frozen"
+// @Failure 409 {object} dto.Problem "Idempotency key reused with a different request"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/wallet/holds [post]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *Handler) CreateHold(c *gin.Context) {
+	var in dto.CreateHoldRequest
+
+	if err := c.BindJSON(&in); err != nil {
+		sendInvalidRequest(c, err)
+		return
+	}
+
+	email, ok := getAccountFromContext(c)
+	if !ok {
+		sendInternalError(c)
+		return
+	}
+
+	// проверяем до умножения: большое значение переполнит time.Duration и может
+	// оказаться в допустимом диапазоне
+	if in.TTLSeconds < 0 || in.TTLSeconds > int64(service.MaxHoldTTL/time.Second) {
+		sendError(c, service.ErrInvalidHoldTTL)
+		return
+	}
+
+	ttl := time.Duration(in.TTLSeconds) * time.Second
+	hold, err := h.s.Wallet.Authorize(c, email, c.GetHeader(IdempotencyKeyHeader), in.Currency, in.Amount, ttl)
+	if err != nil {
+		sendError(c, err)
+		return
+	}
+
+	sendOK(c, toHoldResponse(hold))
+}
+
+// CaptureHold godoc
+// @Summary Списание по холду
+// @Description Списывает зарезервированные средства полностью или частично. При частичном списании остаток холда освобождается.
+// @Tags holds
+// @Accept json
+// @Produce json
+// @Param id path string true "Идентификатор холда"
+// @Param input body dto.CaptureHoldRequest false "Сумма списания"
+// @Param Idempotency-Key header string false "Ключ идемпотентности для безопасного повтора запроса"
+// @Success 200 {object} dto.HoldResponse "Hold captured"
+// @Failure 400 {object} dto.Problem "Invalid amount"
+// @Failure 403 {object} dto.Problem "Email is not verified or account is frozen"
+// @Failure 404 {object} dto.Problem "Hold not found"
+// @Failure 409 {object} dto.Problem "Hold already captured or voided"
+// @Failure 410 {object} dto.Problem "Hold expired"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/wallet/holds/{id}/capture [post]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *Handler) CaptureHold(c *gin.Context) {
+	var in dto.CaptureHoldRequest
+
+	if c.Request.ContentLength != 0 {
+		if err := c.BindJSON(&in); err != nil {
+			sendInvalidRequest(c, err)
+			return
+		}
+	}
+
+	email, ok := getAccountFromContext(c)
+	if !ok {
+		sendInternalError(c)
+		return
+	}
+
+	hold, err := h.s.Wallet.Capture(c, email, c.GetHeader(IdempotencyKeyHeader), c.Param("id"), in.Amount)
+	if err != nil {
+		sendError(c, err)
+		return
+	}
+
+	sendOK(c, toHoldResponse(hold))
+}
+
+// VoidHold godoc
+// @Summary Отмена холда
+// @Description Отменяет холд и освобождает зарезервированную сумму.
+// @Tags holds
+// @Produce json
+// @Param id path string true "Идентификатор холда"
+// @Success 200 {object} dto.HoldResponse "Hold voided"
+// @Failure 404 {object} dto.Problem "Hold not found"
+// @Failure 409 {object} dto.Problem "Hold already captured or voided"
+// @Failure 410 {object} dto.Problem "Hold expired"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/wallet/holds/{id}/void [post]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *Handler) VoidHold(c *gin.Context) {
+	email, ok := getAccountFromContext(c)
+	if !ok {
+		sendInternalError(c)
+		return
+	}
+
+	hold, err := h.s.Wallet.Void(c, email, c.Param("id"))
+	if err != nil {
+		sendError(c, err)
+		return
+	}
+
+	sendOK(c, toHoldResponse(hold))
+}
+
+func toHoldResponse(hold *models.Hold) *dto.HoldResponse {
+	return &dto.HoldResponse{
+		ID:             hold.ID,
+		Currency:       hold.Currency,
+		Amount:         hold.Amount,
+		CapturedAmount: hold.CapturedAmount,
+		Status:         hold.Status,
+		ExpiresAt:      hold.ExpiresAt,
+		CreatedAt:      hold.CreatedAt,
+	}
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"gw-currency-wallet/internal/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetJWKS godoc
+// @Summary Открытые ключи подписи токенов
+// @Description Возвращает JWKS (RFC 7517) с открытыми ключами, которыми можно проверить подпись токенов доступа.
+// @Description Токен указывает ключ в заголовке kid. После ротации прежние ключи остаются в списке, пока их не уберут из конфигурации.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} dto.JWKSResponse "JSON Web Key Set"
+// @Router /.well-known/jwks.json [get]
+func (h *Handler) GetJWKS(c *gin.Context) {
+	keys := h.s.Auth.JWKS()
+
+	out := make([]dto.JWK, 0, len(keys))
+	for _, key := range keys {
+		out = append(out, dto.JWK{
+			Kty: key.KeyType,
+			Kid: key.KeyID,
+			Use: key.Use,
+			Alg: key.Algorithm,
+			N:   key.N,
+			E:   key.E,
+			Crv: key.Curve,
+			X:   key.X,
+		})
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	sendOK(c, &dto.JWKSResponse{
+		Keys: out,
+	})
+}
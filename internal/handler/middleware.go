@@ -1,43 +1,230 @@
 package handler
 
 import (
-	"errors"
+	"fmt"
+	"gw-currency-wallet/internal/logger"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/requestid"
 	"gw-currency-wallet/internal/service"
+	"math"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
 type contextKey string
 
-const AccountEmailKey contextKey = "accountEmail"
-
-var (
-	ErrInvalidAuthorizationHeader = errors.New("missing or invalid Authorization header")
+const (
+	AccountEmailKey contextKey = "accountEmail"
+	SessionIDKey    contextKey = "sessionID"
+	RoleKey         contextKey = "role"
+	APIKeyScopesKey contextKey = "apiKeyScopes"
 )
 
+const APIKeyHeader = "X-API-Key"
+
+// requestIDMiddleware принимает ID запроса из заголовка X-Request-ID или генерирует новый,
+// кладёт его в контекст запроса и возвращает в ответе. ID попадает в логи, application_name
+// соединения с Postgres, метаданные вызовов gRPC и атрибуты спана запроса.
+func (h *Handler) requestIDMiddleware(c *gin.Context) {
+	id := c.GetHeader(requestid.Header)
+	if !requestid.IsValid(id) {
+		id = requestid.New()
+	}
+
+	trace.SpanFromContext(c.Request.Context()).SetAttributes(attribute.String("request.id", id))
+	c.Request = c.Request.WithContext(requestid.With(c.Request.Context(), id))
+	c.Header(requestid.Header, id)
+	c.Next()
+}
+
+// authMiddleware принимает токен доступа в заголовке Authorization либо ключ API
+// в заголовке X-API-Key. Для ключа в контекст кладутся его области доступа,
+// роль не задаётся: административные права ключам не выдаются.
 func (h *Handler) authMiddleware(c *gin.Context) {
+	if key := c.GetHeader(APIKeyHeader); key != "" {
+		h.apiKeyAuth(c, key)
+		return
+	}
+
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
-		zap.L().Error(ErrInvalidAuthorizationHeader.Error())
-		sendUnauthorized(c, ErrInvalidAuthorizationHeader)
+		logger.L(c).Error(service.ErrInvalidAuthorizationHeader.Error())
+		sendError(c, service.ErrInvalidAuthorizationHeader)
 		return
 	}
 
 	token := strings.TrimPrefix(authHeader, "Bearer ")
 
 	claims, err := h.s.Auth.GetClaims(token)
+	if err != nil || claims.SessionID == "" {
+		logger.L(c).Error(service.ErrTokenInvalid.Error())
+		sendError(c, service.ErrTokenInvalid)
+		return
+	}
+
+	active, err := h.s.Session.IsActive(c, claims.SessionID)
 	if err != nil {
-		zap.L().Error(service.ErrTokenInvalid.Error())
-		sendUnauthorized(c, service.ErrTokenInvalid)
+		sendError(c, err)
+		return
+	}
+
+	if !active {
+		logger.L(c).Warn(service.ErrSessionRevoked.Error())
+		sendError(c, service.ErrSessionRevoked)
 		return
 	}
 
+	role := claims.Role
+	if role == "" {
+		role = models.RoleUser
+	}
+
 	c.Set(AccountEmailKey, claims.Email)
+	c.Set(SessionIDKey, claims.SessionID)
+	c.Set(RoleKey, role)
 	c.Next()
 }
 
+func (h *Handler) apiKeyAuth(c *gin.Context, key string) {
+	apiKey, err := h.s.APIKey.Authenticate(c, key, c.ClientIP())
+	if err != nil {
+		sendError(c, err)
+		return
+	}
+
+	c.Set(AccountEmailKey, apiKey.Email)
+	c.Set(APIKeyScopesKey, apiKey.Scopes)
+	c.Next()
+}
+
+// requireScope требует область доступа scope у запроса с ключом API.
+// Запросы с токеном доступа пропускаются. Должен стоять после authMiddleware.
+func (h *Handler) requireScope(scope models.Scope) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scopes, ok := getAPIKeyScopesFromContext(c)
+		if ok && !slices.Contains(scopes, scope) {
+			logger.L(c).Warn(service.ErrAPIKeyScopeDenied.Error(), zap.String("scope", scope))
+			sendError(c, service.ErrAPIKeyScopeDenied)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// sessionOnlyMiddleware не пропускает запросы с ключом API: управление сессиями,
+// ключами и административные действия доступны только по токену доступа.
+// Должен стоять после authMiddleware.
+func (h *Handler) sessionOnlyMiddleware(c *gin.Context) {
+	if _, ok := getAPIKeyScopesFromContext(c); ok {
+		logger.L(c).Warn(service.ErrAPIKeyNotAccepted.Error(), zap.String("path", c.FullPath()))
+		sendError(c, service.ErrAPIKeyNotAccepted)
+		return
+	}
+
+	c.Next()
+}
+
+// requirePermission пропускает только роли с правом permission.
+// Должен стоять после authMiddleware.
+func (h *Handler) requirePermission(permission models.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, _ := getRoleFromContext(c)
+		if !models.HasPermission(role, permission) {
+			logger.L(c).Warn(service.ErrPermissionDenied.Error(), zap.String("role", role), zap.String("permission", permission))
+			sendError(c, service.ErrPermissionDenied)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// notFrozenMiddleware запрещает операции со средствами замороженным аккаунтам.
+// Должен стоять после authMiddleware.
+func (h *Handler) notFrozenMiddleware(c *gin.Context) {
+	email, ok := getAccountFromContext(c)
+	if !ok {
+		sendInternalError(c)
+		return
+	}
+
+	account, err := h.s.Account.Find(c, email)
+	if err != nil {
+		sendError(c, err)
+		return
+	}
+
+	if account == nil || account.Frozen {
+		logger.L(c).Warn(service.ErrAccountFrozen.Error(), zap.String("email", email))
+		sendError(c, service.ErrAccountFrozen)
+		return
+	}
+
+	c.Next()
+}
+
+// verifiedMiddleware пропускает только аккаунты с подтверждённым email.
+// Должен стоять после authMiddleware.
+func (h *Handler) verifiedMiddleware(c *gin.Context) {
+	email, ok := getAccountFromContext(c)
+	if !ok {
+		sendInternalError(c)
+		return
+	}
+
+	verified, err := h.s.EmailVerification.IsVerified(c, email)
+	if err != nil {
+		sendError(c, err)
+		return
+	}
+
+	if !verified {
+		logger.L(c).Warn(service.ErrEmailNotVerified.Error(), zap.String("email", email))
+		sendError(c, service.ErrEmailNotVerified)
+		return
+	}
+
+	c.Next()
+}
+
+// rateLimitMiddleware ограничивает частоту запросов группы маршрутов group. Запросы считаются
+// по email клиента, а до аутентификации — по IP-адресу соединения или, за доверенным прокси,
+// по X-Forwarded-For, поэтому на защищённых маршрутах должен стоять после authMiddleware.
+func (h *Handler) rateLimitMiddleware(group models.RateLimitGroup) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		subject := "ip:" + c.ClientIP()
+		if email, ok := getAccountFromContext(c); ok {
+			subject = "account:" + email
+		}
+
+		result, err := h.s.RateLimit.Take(c, group, subject)
+		if result != nil {
+			setRateLimitHeaders(c, result)
+		}
+		if err != nil {
+			sendError(c, err)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// setRateLimitHeaders выставляет заголовки RateLimit-* (draft-ietf-httpapi-ratelimit-headers)
+func setRateLimitHeaders(c *gin.Context, result *models.RateLimitResult) {
+	c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(int(math.Ceil(result.Reset.Seconds()))))
+	c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", result.Limit, int(result.Period.Seconds())))
+}
+
 func getAccountFromContext(ctx *gin.Context) (string, bool) {
 	accountID, ok := ctx.Get(AccountEmailKey)
 	if !ok {
@@ -46,3 +233,30 @@ func getAccountFromContext(ctx *gin.Context) (string, bool) {
 	idStr, ok := accountID.(string)
 	return idStr, ok
 }
+
+func getSessionFromContext(ctx *gin.Context) (string, bool) {
+	sessionID, ok := ctx.Get(SessionIDKey)
+	if !ok {
+		return "", false
+	}
+	idStr, ok := sessionID.(string)
+	return idStr, ok
+}
+
+func getRoleFromContext(ctx *gin.Context) (models.Role, bool) {
+	role, ok := ctx.Get(RoleKey)
+	if !ok {
+		return "", false
+	}
+	roleStr, ok := role.(string)
+	return roleStr, ok
+}
+
+func getAPIKeyScopesFromContext(ctx *gin.Context) ([]models.Scope, bool) {
+	scopes, ok := ctx.Get(APIKeyScopesKey)
+	if !ok {
+		return nil, false
+	}
+	scopeList, ok := scopes.([]models.Scope)
+	return scopeList, ok
+}
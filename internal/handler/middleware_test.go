@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyAuth_SpoofedForwardedFor_DoesNotSatisfyAllowlist(t *testing.T) {
+	apiKey := &testAPIKey{allowedIP: "10.0.0.1"}
+	router := newTestRouter(&service.Service{APIKey: apiKey, RateLimit: &testRateLimit{}, Wallet: &testWallet{}})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/balance", nil)
+	req.Header.Set(APIKeyHeader, "gw_test")
+	req.Header.Set("X-Forwarded-For", "10.0.0.1")
+	w := serve(router, req, "203.0.113.5:41000")
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, "203.0.113.5", apiKey.clientIP)
+}
+
+func TestAPIKeyAuth_TrustedProxy_UsesForwardedFor(t *testing.T) {
+	apiKey := &testAPIKey{allowedIP: "198.51.100.7", scopes: []models.Scope{models.ScopeBalanceRead}}
+	router := newTestRouter(&service.Service{APIKey: apiKey, RateLimit: &testRateLimit{}, Wallet: &testWallet{}}, "10.0.0.0/8")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/balance", nil)
+	req.Header.Set(APIKeyHeader, "gw_test")
+	req.Header.Set("X-Forwarded-For", "198.51.100.7")
+	w := serve(router, req, "10.1.2.3:41000")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "198.51.100.7", apiKey.clientIP)
+}
+
+func TestRateLimit_RotatingForwardedFor_SharesBucket(t *testing.T) {
+	rateLimit := &testRateLimit{}
+	router := newTestRouter(&service.Service{RateLimit: rateLimit, Currency: testCurrency{}})
+
+	for _, forwardedFor := range []string{"192.0.2.1", "192.0.2.2", "192.0.2.3"} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/currencies", nil)
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		serve(router, req, "203.0.113.5:41000")
+	}
+
+	assert.Equal(t, []string{"ip:203.0.113.5", "ip:203.0.113.5", "ip:203.0.113.5"}, rateLimit.subjects)
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"gw-currency-wallet/internal/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ForgotPassword godoc
+// @Summary Запрос сброса пароля
+// @Description Отправляет на email письмо со ссылкой для сброса пароля. Ссылка одноразовая и действует 1 час.
+// @Description Ответ не зависит от того, зарегистрирован ли email.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.ForgotPasswordRequest true "Email аккаунта"
+// @Success 200 {object} dto.Message "Reset link sent if the account exists"
+// @Failure 400 {object} dto.Problem "Invalid request"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/password/forgot [post]
+func (h *Handler) ForgotPassword(c *gin.Context) {
+	var in dto.ForgotPasswordRequest
+
+	if err := c.BindJSON(&in); err != nil {
+		sendInvalidRequest(c, err)
+		return
+	}
+
+	if err := h.s.Password.Forgot(c, in.Email); err != nil {
+		sendError(c, err)
+		return
+	}
+
+	sendOK(c, &dto.Message{Message: "If the account exists, a password reset link has been sent"})
+}
+
+// ResetPassword godoc
+// @Summary Сброс пароля
+// @Description Устанавливает новый пароль по токену из письма. Все сессии пользователя при этом завершаются.
+// @Description Новый пароль проверяется по той же политике, что и при регистрации.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.ResetPasswordRequest true "Токен и новый пароль"
+// @Success 200 {object} dto.Message "Password has been reset"
+// @Failure 400 {object} dto.Problem "Invalid or expired token or password does not meet the policy"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/password/reset [post]
+func (h *Handler) ResetPassword(c *gin.Context) {
+	var in dto.ResetPasswordRequest
+
+	if err := c.BindJSON(&in); err != nil {
+		sendInvalidRequest(c, err)
+		return
+	}
+
+	if err := h.s.Password.Reset(c, in.Token, in.Password); err != nil {
+		sendError(c, err)
+		return
+	}
+
+	sendOK(c, &dto.Message{Message: "Password has been reset"})
+}
@@ -1,34 +1,72 @@
 package handler
 
 import (
+	"errors"
 	"gw-currency-wallet/internal/dto"
+	"gw-currency-wallet/internal/logger"
+	"gw-currency-wallet/internal/service"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
-func sendBadRequest(c *gin.Context, err error) {
-	send(c, http.StatusBadRequest, dto.ErrorMessage{Error: err.Error()})
+const problemContentType = "application/problem+json"
+
+func sendAccepted(c *gin.Context, body any) {
+	send(c, http.StatusAccepted, body)
 }
 
 func sendCreated(c *gin.Context, message string) {
 	send(c, http.StatusCreated, dto.Message{Message: message})
 }
 
-func sendInternalError(c *gin.Context) {
-	send(c, http.StatusInternalServerError, dto.Message{Message: "server error"})
-}
-
 func sendOK(c *gin.Context, body any) {
 	send(c, http.StatusOK, body)
 }
 
-func sendUnauthorized(c *gin.Context, err error) {
-	send(c, http.StatusUnauthorized, dto.Message{Message: err.Error()})
+// sendError единственный путь ответа с ошибкой. Ошибка каталога service.Error отдаётся
+// со своими кодом и статусом, любая другая записывается в лог и отдаётся как service.ErrInternal.
+func sendError(c *gin.Context, err error) {
+	var e *service.Error
+	if !errors.As(err, &e) {
+		logger.L(c).Error(err.Error())
+		e = service.ErrInternal
+	}
+
+	var retryable service.Retryable
+	if errors.As(err, &retryable) {
+		retryAfter := retryable.RetryAfterSeconds()
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		e = e.WithDetails(map[string]any{"retry_after": retryAfter})
+	}
+
+	problem := dto.Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(e.Status),
+		Status:   e.Status,
+		Detail:   e.Message,
+		Instance: c.Request.URL.Path,
+		Code:     e.Code,
+		Details:  e.Details,
+	}
+
+	logger.L(c).Info("HTTP Response", zap.Int("status", e.Status), zap.Any("body", problem))
+	c.Header("Content-Type", problemContentType)
+	c.AbortWithStatusJSON(e.Status, problem)
+}
+
+// sendInvalidRequest отвечает на тело или параметры запроса, не прошедшие разбор и проверку
+func sendInvalidRequest(c *gin.Context, err error) {
+	sendError(c, service.ErrInvalidRequest.WithDetails(map[string]any{"reason": err.Error()}))
+}
+
+func sendInternalError(c *gin.Context) {
+	sendError(c, service.ErrInternal)
 }
 
 func send(c *gin.Context, status int, body any) {
-	zap.L().Info("HTTP Response", zap.Int("status", status), zap.Any("body", body))
+	logger.L(c).Info("HTTP Response", zap.Int("status", status), zap.Any("body", body))
 	c.AbortWithStatusJSON(status, body)
 }
@@ -2,34 +2,93 @@ package handler
 
 import (
 	_ "gw-currency-wallet/docs"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/tracing"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"go.uber.org/zap"
 )
 
 func (h *Handler) Router() *gin.Engine {
 	router := gin.Default()
+	// X-Forwarded-For учитывается только от доверенных прокси, иначе c.ClientIP() — адрес соединения:
+	// по нему работают allowlist ключей API, блокировка входа и ограничение частоты запросов
+	if err := router.SetTrustedProxies(h.trustedProxies); err != nil {
+		zap.L().Fatal(err.Error())
+	}
+	// контекст запроса с его ID доступен через *gin.Context, который передаётся в сервисы
+	router.ContextWithFallback = true
+	if h.m != nil {
+		router.Use(h.m.GinMiddleware())
+		router.GET("/metrics", gin.WrapH(h.m.Handler()))
+	}
+	router.Use(tracing.GinMiddleware(), h.requestIDMiddleware)
+
+	limitAuth := h.rateLimitMiddleware(models.RateLimitGroupAuth)
+	limitExchange := h.rateLimitMiddleware(models.RateLimitGroupExchange)
+	limitWallet := h.rateLimitMiddleware(models.RateLimitGroupWallet)
+	limitDefault := h.rateLimitMiddleware(models.RateLimitGroupDefault)
 
 	v1 := router.Group("api/v1")
 	{
-		v1.POST("register", h.Register)
-		v1.POST("login", h.Login)
+		v1.POST("register", limitAuth, h.Register)
+		v1.POST("login", limitAuth, h.Login)
+		v1.POST("login/2fa", limitAuth, h.LoginTwoFactor)
+		v1.POST("token/refresh", limitAuth, h.RefreshToken)
+		v1.POST("password/forgot", limitAuth, h.ForgotPassword)
+		v1.POST("password/reset", limitAuth, h.ResetPassword)
+		v1.GET("verify-email", limitAuth, h.VerifyEmailLink)
+		v1.POST("verify-email", limitAuth, h.VerifyEmail)
+		v1.GET("currencies", limitDefault, h.GetCurrencies)
 
 		withAuth := v1.Group("", h.authMiddleware)
 		{
-			withAuth.GET("balance", h.GetWallets)
-			withAuth.POST("exchange", h.Exchange)
-			withAuth.GET("exchange/rates", h.GetRates)
+			withAuth.GET("balance", limitDefault, h.requireScope(models.ScopeBalanceRead), h.GetWallets)
+			withAuth.POST("exchange", limitExchange, h.requireScope(models.ScopeExchangeExecute), h.notFrozenMiddleware, h.Exchange)
+			withAuth.POST("exchange/quote", limitExchange, h.requireScope(models.ScopeExchangeExecute), h.notFrozenMiddleware, h.CreateQuote)
+			withAuth.GET("exchange/rates", limitExchange, h.requireScope(models.ScopeExchangeRead), h.GetRates)
+			withAuth.POST("transfer", limitWallet, h.requireScope(models.ScopeTransferExecute), h.notFrozenMiddleware, h.verifiedMiddleware, h.Transfer)
+
+			wallet := withAuth.Group("wallet", limitWallet)
+			{
+				wallet.POST("deposit", h.requireScope(models.ScopeWalletDeposit), h.notFrozenMiddleware, h.Deposit)
+				wallet.POST("withdraw", h.requireScope(models.ScopeWalletWithdraw), h.notFrozenMiddleware, h.verifiedMiddleware, h.Withdraw)
+				wallet.GET("transactions", h.requireScope(models.ScopeTransactionsRead), h.GetTransactions)
+				wallet.POST("holds", h.requireScope(models.ScopeHoldsManage), h.notFrozenMiddleware, h.verifiedMiddleware, h.CreateHold)
+				wallet.POST("holds/:id/capture", h.requireScope(models.ScopeHoldsManage), h.notFrozenMiddleware, h.verifiedMiddleware, h.CaptureHold)
+				wallet.POST("holds/:id/void", h.requireScope(models.ScopeHoldsManage), h.VoidHold)
+			}
+
+			sessionOnly := withAuth.Group("", limitDefault, h.sessionOnlyMiddleware)
+			{
+				sessionOnly.POST("logout", h.Logout)
+				sessionOnly.POST("logout/all", h.LogoutAll)
+				sessionOnly.POST("2fa/enroll", h.EnrollTwoFactor)
+				sessionOnly.POST("2fa/confirm", h.ConfirmTwoFactor)
+				sessionOnly.POST("verify-email/resend", h.ResendVerificationEmail)
+				sessionOnly.POST("api-keys", h.CreateAPIKey)
+				sessionOnly.GET("api-keys", h.GetAPIKeys)
+				sessionOnly.DELETE("api-keys/:id", h.RevokeAPIKey)
+			}
 
-			wallet := withAuth.Group("wallet")
+			admin := sessionOnly.Group("admin")
 			{
-				wallet.POST("deposit", h.Deposit)
-				wallet.POST("withdraw", h.Withdraw)
+				admin.GET("accounts", h.requirePermission(models.PermissionAccountsRead), h.SearchAccounts)
+				admin.GET("accounts/:email/wallets", h.requirePermission(models.PermissionWalletsRead), h.GetAccountWallets)
+				admin.POST("accounts/:email/freeze", h.requirePermission(models.PermissionAccountsFreeze), h.FreezeAccount)
+				admin.POST("accounts/:email/unfreeze", h.requirePermission(models.PermissionAccountsFreeze), h.UnfreezeAccount)
+				admin.PUT("accounts/:email/role", h.requirePermission(models.PermissionRolesManage), h.SetAccountRole)
+				admin.POST("accounts/:email/adjustments", h.requirePermission(models.PermissionBalanceAdjust), h.AdjustBalance)
+				admin.GET("lockouts", h.requirePermission(models.PermissionLockoutsManage), h.GetLockouts)
+				admin.POST("lockouts/unlock", h.requirePermission(models.PermissionLockoutsManage), h.UnlockLogin)
+				admin.GET("audit", h.requirePermission(models.PermissionAuditRead), h.GetAuditLog)
 			}
 		}
 
 	}
+	router.GET("/.well-known/jwks.json", h.GetJWKS)
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	return router
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"gw-currency-wallet/internal/dto"
+	"gw-currency-wallet/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetTransactions godoc
+// @Summary История операций пользователя
+// @Description Возвращает операции пользователя (пополнения, выводы, обмены, переводы, списания по холдам, корректировки администратором) от новых к старым с постраничной навигацией по курсору.
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Param currency query string false "Фильтр по валюте"
+// @Param type query string false "Фильтр по типу операции" Enums(deposit, withdraw, exchange, transfer, capture, adjustment, opening_balance)
+// @Param from query string false "Начало периода (RFC 3339, включительно)"
+// @Param to query string false "Конец периода (RFC 3339, не включительно)"
+// @Param cursor query string false "Курсор следующей страницы из предыдущего ответа"
+// @Param limit query int false "Размер страницы (1-100, по умолчанию 20)"
+// @Success 200 {object} dto.GetTransactionsResponse "Transaction history"
+// @Failure 400 {object} dto.Problem "Invalid filter or cursor"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/wallet/transactions [get]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *Handler) GetTransactions(c *gin.Context) {
+	var in dto.GetTransactionsRequest
+
+	if err := c.BindQuery(&in); err != nil {
+		sendInvalidRequest(c, err)
+		return
+	}
+
+	email, ok := getAccountFromContext(c)
+	if !ok {
+		sendInternalError(c)
+		return
+	}
+
+	page, err := h.s.Ledger.History(c, email, models.TransactionFilter{
+		Currency: in.Currency,
+		Type:     in.Type,
+		From:     in.From,
+		To:       in.To,
+	}, in.Cursor, in.Limit)
+	if err != nil {
+		sendError(c, err)
+		return
+	}
+
+	transactions := make([]dto.Transaction, 0, len(page.Transactions))
+	for _, t := range page.Transactions {
+		entries := make([]dto.TransactionEntry, 0, len(t.Entries))
+		for _, e := range t.Entries {
+			entries = append(entries, dto.TransactionEntry{
+				Currency: e.Currency,
+				Amount:   e.Amount,
+			})
+		}
+		transactions = append(transactions, dto.Transaction{
+			ID:        t.ID,
+			Type:      t.Type,
+			CreatedAt: t.CreatedAt,
+			Entries:   entries,
+		})
+	}
+
+	sendOK(c, &dto.GetTransactionsResponse{
+		Transactions: transactions,
+		NextCursor:   page.NextCursor,
+	})
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"gw-currency-wallet/internal/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EnrollTwoFactor godoc
+// @Summary Подключение двухфакторной аутентификации
+// @Description Создаёт секрет TOTP и ссылку otpauth:// для приложения-аутентификатора. 2FA начинает действовать
+// @Description только после подтверждения первым кодом; повторный вызов до подтверждения заменяет секрет.
+// @Tags auth
+// @Produce json
+// @Success 200 {object} dto.TwoFactorEnrollResponse "TOTP secret"
+// @Failure 401 {object} dto.Problem "Unauthorized"
+// @Failure 409 {object} dto.Problem "Two-factor authentication is already enabled"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/2fa/enroll [post]
+// @Security BearerAuth
+func (h *Handler) EnrollTwoFactor(c *gin.Context) {
+	email, ok := getAccountFromContext(c)
+	if !ok {
+		sendInternalError(c)
+		return
+	}
+
+	enrollment, err := h.s.TwoFactor.Enroll(c, email)
+	if err != nil {
+		sendError(c, err)
+		return
+	}
+
+	sendOK(c, &dto.TwoFactorEnrollResponse{
+		Secret:     enrollment.Secret,
+		OTPAuthURI: enrollment.URI,
+	})
+}
+
+// ConfirmTwoFactor godoc
+// @Summary Подтверждение двухфакторной аутентификации
+// @Description Включает 2FA, если код из приложения-аутентификатора верен, и возвращает одноразовые коды
+// @Description восстановления. Коды показываются только один раз.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.TwoFactorCodeRequest true "Код TOTP"
+// @Success 200 {object} dto.RecoveryCodesResponse "Recovery codes"
+// @Failure 400 {object} dto.Problem "Invalid code or enrollment not started"
+// @Failure 401 {object} dto.Problem "Unauthorized"
+// @Failure 409 {object} dto.Problem "Two-factor authentication is already enabled"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/2fa/confirm [post]
+// @Security BearerAuth
+func (h *Handler) ConfirmTwoFactor(c *gin.Context) {
+	var in dto.TwoFactorCodeRequest
+
+	if err := c.BindJSON(&in); err != nil {
+		sendInvalidRequest(c, err)
+		return
+	}
+
+	email, ok := getAccountFromContext(c)
+	if !ok {
+		sendInternalError(c)
+		return
+	}
+
+	codes, err := h.s.TwoFactor.Confirm(c, email, in.Code)
+	if err != nil {
+		sendError(c, err)
+		return
+	}
+
+	sendOK(c, &dto.RecoveryCodesResponse{
+		RecoveryCodes: codes,
+	})
+}
+
+// LoginTwoFactor godoc
+// @Summary Второй шаг авторизации
+// @Description Обменивает токен второго шага из /api/v1/login и код TOTP или код восстановления на пару токенов.
+// @Description После 5 неверных кодов токен второго шага перестаёт действовать.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.TwoFactorLoginRequest true "Токен второго шага и код"
+// @Success 200 {object} dto.LoginResponse "JWT-token"
+// @Failure 400 {object} dto.Problem "Invalid code"
+// @Failure 401 {object} dto.Problem "Invalid or expired challenge"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/login/2fa [post]
+func (h *Handler) LoginTwoFactor(c *gin.Context) {
+	var in dto.TwoFactorLoginRequest
+
+	if err := c.BindJSON(&in); err != nil {
+		sendInvalidRequest(c, err)
+		return
+	}
+
+	tokens, err := h.s.TwoFactor.Verify(c, in.ChallengeToken, in.Code, c.ClientIP())
+	if err != nil {
+		sendError(c, err)
+		return
+	}
+
+	sendOK(c, toLoginResponse(tokens))
+}
@@ -1,24 +1,28 @@
 package handler
 
 import (
-	"errors"
 	"gw-currency-wallet/internal/dto"
-	"gw-currency-wallet/internal/service"
+	"gw-currency-wallet/internal/models"
 
 	"github.com/gin-gonic/gin"
-	"go.uber.org/zap"
 )
 
+// IdempotencyKeyHeader заголовок, по которому повтор запроса не приводит
+// к повторному движению средств
+const IdempotencyKeyHeader = "Idempotency-Key"
+
 // GetWallets godoc
 // @Summary Получение кошельков пользователя
-// @Description Возвращает список кошельков и их балансы для авторизованного пользователя.
+// @Description Возвращает балансы кошельков авторизованного пользователя: доступную сумму и сумму, зарезервированную холдами.
 // @Tags wallet
 // @Accept json
 // @Produce json
 // @Success 200 {object} dto.GetWalletsResponse "User wallets"
-// @Failure 500 {object} dto.Message "Internal server error"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
 // @Router /api/v1/balance [get]
 // @Security BearerAuth
+// @Security ApiKeyAuth
 func (h *Handler) GetWallets(c *gin.Context) {
 	email, ok := getAccountFromContext(c)
 	if !ok {
@@ -26,15 +30,22 @@ func (h *Handler) GetWallets(c *gin.Context) {
 		return
 	}
 
-	wallets, err := h.s.Wallet.GetAllByEmail(c, email)
+	balances, err := h.s.Wallet.GetBalances(c, email)
 	if err != nil {
-		zap.L().Error(err.Error())
-		sendInternalError(c)
+		sendError(c, err)
 		return
 	}
 
+	out := make(map[string]dto.WalletBalance, len(balances))
+	for currency, balance := range balances {
+		out[currency] = dto.WalletBalance{
+			Available: balance.Available,
+			Held:      balance.Held,
+		}
+	}
+
 	sendOK(c, &dto.GetWalletsResponse{
-		Balance: wallets,
+		Balance: out,
 	})
 }
 
@@ -45,16 +56,21 @@ func (h *Handler) GetWallets(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param input body dto.DepositRequest true "Сумма и валюта для пополнения"
+// @Param Idempotency-Key header string false "Ключ идемпотентности для безопасного повтора запроса"
 // @Success 200 {object} dto.DepositResponse "Account topped up successfully"
-// @Failure 400 {object} dto.Message "Invalid amount or currency"
-// @Failure 500 {object} dto.Message "Internal server error"
+// @Failure 400 {object} dto.Problem "Invalid amount or currency"
+// @Failure 403 {object} dto.Problem "Account is frozen"
+// @Failure 409 {object} dto.Problem "Idempotency key reused with a different request"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
 // @Router /api/v1/wallet/deposit [post]
 // @Security BearerAuth
+// @Security ApiKeyAuth
 func (h *Handler) Deposit(c *gin.Context) {
 	var in dto.DepositRequest
 
 	if err := c.BindJSON(&in); err != nil {
-		sendBadRequest(c, err)
+		sendInvalidRequest(c, err)
 		return
 	}
 
@@ -64,23 +80,10 @@ func (h *Handler) Deposit(c *gin.Context) {
 		return
 	}
 
-	wallets, err := h.s.Deposit(c, email, in.Currency, in.Amount)
+	wallets, err := h.s.Deposit(c, email, c.GetHeader(IdempotencyKeyHeader), in.Currency, in.Amount)
 	if err != nil {
-		switch {
-		case errors.Is(err, service.ErrNegativeAmount):
-			sendBadRequest(c, service.ErrNegativeAmount)
-			return
-		case errors.Is(err, service.ErrZeroAmount):
-			sendBadRequest(c, service.ErrZeroAmount)
-			return
-		case errors.Is(err, service.ErrNonExistentCurrency):
-			sendBadRequest(c, service.ErrNonExistentCurrency)
-			return
-		default:
-			zap.L().Error(err.Error())
-			sendInternalError(c)
-			return
-		}
+		sendError(c, err)
+		return
 	}
 
 	sendOK(c, &dto.DepositResponse{
@@ -96,16 +99,21 @@ func (h *Handler) Deposit(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param input body dto.WithdrawRequest true "Сумма и валюта для вывода"
+// @Param Idempotency-Key header string false "Ключ идемпотентности для безопасного повтора запроса"
 // @Success 200 {object} dto.WithdrawResponse "Withdrawal successful"
-// @Failure 400 {object} dto.Message "Insufficient funds or invalid amount"
-// @Failure 500 {object} dto.Message "Internal server error"
+// @Failure 400 {object} dto.Problem "Insufficient funds or invalid amount"
+// @Failure 403 {object} dto.Problem "Email is not verified or account is frozen"
+// @Failure 409 {object} dto.Problem "Idempotency key reused with a different request"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
 // @Router /api/v1/wallet/withdraw [post]
 // @Security BearerAuth
+// @Security ApiKeyAuth
 func (h *Handler) Withdraw(c *gin.Context) {
 	var in dto.WithdrawRequest
 
 	if err := c.BindJSON(&in); err != nil {
-		sendBadRequest(c, err)
+		sendInvalidRequest(c, err)
 		return
 	}
 
@@ -115,26 +123,10 @@ func (h *Handler) Withdraw(c *gin.Context) {
 		return
 	}
 
-	wallets, err := h.s.Withdraw(c, email, in.Currency, in.Amount)
+	wallets, err := h.s.Withdraw(c, email, c.GetHeader(IdempotencyKeyHeader), in.Currency, in.Amount)
 	if err != nil {
-		switch {
-		case errors.Is(err, service.ErrNegativeAmount):
-			sendBadRequest(c, service.ErrNegativeAmount)
-			return
-		case errors.Is(err, service.ErrZeroAmount):
-			sendBadRequest(c, service.ErrZeroAmount)
-			return
-		case errors.Is(err, service.ErrNonExistentCurrency):
-			sendBadRequest(c, service.ErrNonExistentCurrency)
-			return
-		case errors.Is(err, service.ErrInsufficientBalance):
-			sendBadRequest(c, service.ErrInsufficientBalance)
-			return
-		default:
-			zap.L().Error(err.Error())
-			sendInternalError(c)
-			return
-		}
+		sendError(c, err)
+		return
 	}
 
 	sendOK(c, &dto.WithdrawResponse{
@@ -150,13 +142,17 @@ func (h *Handler) Withdraw(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {object} dto.GetRatesResponse "Exchange rates"
-// @Failure 500 {object} dto.Message "Failed to retrieve exchange rates"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Failure 503 {object} dto.Problem "Exchange rate service is unavailable"
+// @Failure 504 {object} dto.Problem "Exchange rate service timed out"
 // @Router /api/v1/exchange/rates [get]
 // @Security BearerAuth
+// @Security ApiKeyAuth
 func (h *Handler) GetRates(c *gin.Context) {
 	rates, err := h.s.Wallet.GetRates(c)
 	if err != nil {
-		sendInternalError(c)
+		sendError(c, err)
 		return
 	}
 
@@ -168,20 +164,33 @@ func (h *Handler) GetRates(c *gin.Context) {
 // Exchange godoc
 // @Summary Обмен валют
 // @Description Позволяет пользователю обменять одну валюту на другую по текущему курсу.
+// @Description Если передан quote_id, обмен выполняется по курсу зафиксированной котировки.
+// @Description Необязательные min_receive_amount и max_rate защищают от неблагоприятного изменения курса;
+// @Description max_rate — максимальная цена единицы to_currency в from_currency.
+// @Description С суммы в валюте получения удерживается комиссия по тарифу валютной пары.
 // @Tags exchange
 // @Accept json
 // @Produce json
 // @Param input body dto.ExchangeRequest true "Данные для обмена валют"
+// @Param Idempotency-Key header string false "Ключ идемпотентности для безопасного повтора запроса"
 // @Success 200 {object} dto.ExchangeResponse "Exchange successful"
-// @Failure 400 {object} dto.Message "Insufficient funds or invalid currencies"
-// @Failure 500 {object} dto.Message "Internal server error"
+// @Failure 400 {object} dto.Problem "Insufficient funds or invalid currencies"
+// @Failure 403 {object} dto.Problem "Account is frozen"
+// @Failure 404 {object} dto.Problem "Quote not found"
+// @Failure 409 {object} dto.Problem "Idempotency key reused with a different request, quote already used or slippage exceeded"
+// @Failure 410 {object} dto.Problem "Quote expired"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Failure 503 {object} dto.Problem "Exchange rate service is unavailable"
+// @Failure 504 {object} dto.Problem "Exchange rate service timed out"
 // @Router /api/v1/exchange [post]
 // @Security BearerAuth
+// @Security ApiKeyAuth
 func (h *Handler) Exchange(c *gin.Context) {
 	var in dto.ExchangeRequest
 
 	if err := c.BindJSON(&in); err != nil {
-		sendBadRequest(c, err)
+		sendInvalidRequest(c, err)
 		return
 	}
 
@@ -191,31 +200,121 @@ func (h *Handler) Exchange(c *gin.Context) {
 		return
 	}
 
-	exchangedAmount, wallets, err := h.s.Wallet.Exchange(c, email, in.FromCurrency, in.ToCurrency, in.Amount)
+	amounts, wallets, err := h.s.Wallet.Exchange(c, email, c.GetHeader(IdempotencyKeyHeader), models.ExchangeOrder{
+		From:             in.FromCurrency,
+		To:               in.ToCurrency,
+		Amount:           in.Amount,
+		QuoteID:          in.QuoteID,
+		MinReceiveAmount: in.MinReceiveAmount,
+		MaxRate:          in.MaxRate,
+	})
 	if err != nil {
-		switch {
-		case errors.Is(err, service.ErrNegativeAmount):
-			sendBadRequest(c, service.ErrNegativeAmount)
-			return
-		case errors.Is(err, service.ErrZeroAmount):
-			sendBadRequest(c, service.ErrZeroAmount)
-			return
-		case errors.Is(err, service.ErrNonExistentCurrency):
-			sendBadRequest(c, service.ErrNonExistentCurrency)
-			return
-		case errors.Is(err, service.ErrInsufficientBalance):
-			sendBadRequest(c, service.ErrInsufficientBalance)
-			return
-		default:
-			zap.L().Error(err.Error())
-			sendInternalError(c)
-			return
-		}
+		sendError(c, err)
+		return
 	}
 
 	sendOK(c, &dto.ExchangeResponse{
 		Message:         "Exchange successful",
-		ExchangedAmount: exchangedAmount,
+		ExchangedAmount: amounts.Net,
+		Rate:            amounts.Rate,
+		GrossAmount:     amounts.Gross,
+		Fee:             amounts.Fee,
+		NetAmount:       amounts.Net,
 		NewBalance:      wallets,
 	})
 }
+
+// Transfer godoc
+// @Summary Перевод другому пользователю
+// @Description Переводит сумму в указанной валюте другому пользователю, найденному по имени пользователя или email.
+// @Tags wallet
+// @Accept json
+// @Produce json
+// @Param input body dto.TransferRequest true "Получатель, сумма и валюта перевода"
+// @Param Idempotency-Key header string false "Ключ идемпотентности для безопасного повтора запроса"
+// @Success 200 {object} dto.TransferResponse "Transfer successful"
+// @Failure 400 {object} dto.Problem "Insufficient funds, invalid amount or transfer to yourself"
+// @Failure 403 {object} dto.Problem "Email is not verified or account is frozen"
+// @Failure 404 {object} dto.Problem "Recipient not found"
+// @Failure 409 {object} dto.Problem "Idempotency key reused with a different request"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Router /api/v1/transfer [post]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *Handler) Transfer(c *gin.Context) {
+	var in dto.TransferRequest
+
+	if err := c.BindJSON(&in); err != nil {
+		sendInvalidRequest(c, err)
+		return
+	}
+
+	email, ok := getAccountFromContext(c)
+	if !ok {
+		sendInternalError(c)
+		return
+	}
+
+	wallets, err := h.s.Wallet.Transfer(c, email, c.GetHeader(IdempotencyKeyHeader), in.To, in.Currency, in.Amount)
+	if err != nil {
+		sendError(c, err)
+		return
+	}
+
+	sendOK(c, &dto.TransferResponse{
+		Message:    "Transfer successful",
+		NewBalance: wallets,
+	})
+}
+
+// CreateQuote godoc
+// @Summary Котировка обмена
+// @Description Фиксирует курс обмена на короткое время. Полученный quote_id передаётся в /exchange,
+// @Description котировку можно использовать только один раз.
+// @Tags exchange
+// @Accept json
+// @Produce json
+// @Param input body dto.ExchangeQuoteRequest true "Данные для котировки"
+// @Success 200 {object} dto.ExchangeQuoteResponse "Quote created"
+// @Failure 400 {object} dto.Problem "Invalid amount or currencies"
+// @Failure 403 {object} dto.Problem "Account is frozen"
+// @Failure 429 {object} dto.Problem "Rate limit exceeded"
+// @Failure 500 {object} dto.Problem "Internal server error"
+// @Failure 503 {object} dto.Problem "Exchange rate service is unavailable"
+// @Failure 504 {object} dto.Problem "Exchange rate service timed out"
+// @Router /api/v1/exchange/quote [post]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+func (h *Handler) CreateQuote(c *gin.Context) {
+	var in dto.ExchangeQuoteRequest
+
+	if err := c.BindJSON(&in); err != nil {
+		sendInvalidRequest(c, err)
+		return
+	}
+
+	email, ok := getAccountFromContext(c)
+	if !ok {
+		sendInternalError(c)
+		return
+	}
+
+	quote, err := h.s.Quote.Create(c, email, in.FromCurrency, in.ToCurrency, in.Amount)
+	if err != nil {
+		sendError(c, err)
+		return
+	}
+
+	sendOK(c, &dto.ExchangeQuoteResponse{
+		QuoteID:       quote.ID,
+		FromCurrency:  quote.From,
+		ToCurrency:    quote.To,
+		Amount:        quote.Amount,
+		Rate:          quote.Rate,
+		GrossAmount:   quote.ReceiveAmount.Add(quote.Fee),
+		Fee:           quote.Fee,
+		ReceiveAmount: quote.ReceiveAmount,
+		ExpiresAt:     quote.ExpiresAt,
+	})
+}
@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/service"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRates_ExchangeErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{name: "timeout", err: service.ErrTimeout, status: http.StatusGatewayTimeout, code: "exchange_rate_timeout"},
+		{name: "unavailable", err: service.ErrGetRate, status: http.StatusServiceUnavailable, code: "exchange_rate_unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newTestRouter(&service.Service{
+				APIKey:    &testAPIKey{allowedIP: "203.0.113.5", scopes: []models.Scope{models.ScopeExchangeRead}},
+				RateLimit: &testRateLimit{},
+				Wallet:    &testWallet{ratesErr: tt.err},
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/exchange/rates", nil)
+			req.Header.Set(APIKeyHeader, "gw_test")
+			w := serve(router, req, "203.0.113.5:41000")
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Contains(t, w.Body.String(), tt.code)
+		})
+	}
+}
+
+func TestCreateQuote_FrozenAccount_Forbidden(t *testing.T) {
+	router := newTestRouter(&service.Service{
+		APIKey:    &testAPIKey{allowedIP: "203.0.113.5", scopes: []models.Scope{models.ScopeExchangeExecute}},
+		Account:   &testAccount{frozen: true},
+		RateLimit: &testRateLimit{},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/exchange/quote", strings.NewReader(`{"from_currency":"USD","to_currency":"EUR","amount":"10"}`))
+	req.Header.Set(APIKeyHeader, "gw_test")
+	req.Header.Set("Content-Type", "application/json")
+	w := serve(router, req, "203.0.113.5:41000")
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "account_frozen")
+}
@@ -0,0 +1,87 @@
+package hasher
+
+import (
+	"errors"
+	"fmt"
+	"gw-currency-wallet/config"
+)
+
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+var (
+	ErrMismatch    = errors.New("password does not match")
+	ErrUnknownHash = errors.New("unknown password hash format")
+)
+
+// Hasher хеширует и проверяет пароли одним алгоритмом
+type Hasher interface {
+	Hash(password string) (string, error)
+	// Compare возвращает ErrMismatch, если пароль не подходит к хешу
+	Compare(hash, password string) error
+	// Identify сообщает, создан ли хеш этим алгоритмом
+	Identify(hash string) bool
+	// NeedsRehash сообщает, что хеш этого алгоритма создан с устаревшими параметрами
+	NeedsRehash(hash string) bool
+}
+
+// Multi создаёт хеши основным алгоритмом, а проверяет хеши любого из известных.
+// Хеш другого алгоритма или с устаревшими параметрами нужно пересчитать.
+type Multi struct {
+	primary Hasher
+	hashers []Hasher
+}
+
+func NewMulti(primary Hasher, legacy ...Hasher) *Multi {
+	return &Multi{
+		primary: primary,
+		hashers: append([]Hasher{primary}, legacy...),
+	}
+}
+
+// New выбирает основной алгоритм по cfg.Algorithm, по умолчанию argon2id
+func New(cfg *config.PasswordHashConfig) (*Multi, error) {
+	argon := NewArgon2id(Argon2idParams{
+		Memory:      uint32(cfg.Argon2Memory),
+		Iterations:  uint32(cfg.Argon2Iterations),
+		Parallelism: uint8(cfg.Argon2Parallelism),
+	})
+	bcrypt := NewBcrypt(cfg.BcryptCost)
+
+	switch cfg.Algorithm {
+	case AlgorithmArgon2id, "":
+		return NewMulti(argon, bcrypt), nil
+	case AlgorithmBcrypt:
+		return NewMulti(bcrypt, argon), nil
+	default:
+		return nil, fmt.Errorf("unknown password hash algorithm: %s", cfg.Algorithm)
+	}
+}
+
+func (m *Multi) Hash(password string) (string, error) {
+	return m.primary.Hash(password)
+}
+
+func (m *Multi) Compare(hash, password string) error {
+	for _, h := range m.hashers {
+		if h.Identify(hash) {
+			return h.Compare(hash, password)
+		}
+	}
+	return ErrUnknownHash
+}
+
+func (m *Multi) Identify(hash string) bool {
+	for _, h := range m.hashers {
+		if h.Identify(hash) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Multi) NeedsRehash(hash string) bool {
+	return !m.primary.Identify(hash) || m.primary.NeedsRehash(hash)
+}
@@ -0,0 +1,54 @@
+package hasher
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Небольшие параметры, чтобы тесты работали быстро
+var testArgon2Params = Argon2idParams{Memory: 1024, Iterations: 1, Parallelism: 1}
+
+func TestArgon2id_HashAndCompare(t *testing.T) {
+	h := NewArgon2id(testArgon2Params)
+
+	hash, err := h.Hash("correct horse battery staple")
+	assert.NoError(t, err)
+	assert.Regexp(t, `^\$argon2id\$v=19\$m=1024,t=1,p=1\$[A-Za-z0-9+/]+\$[A-Za-z0-9+/]+$`, hash)
+
+	assert.NoError(t, h.Compare(hash, "correct horse battery staple"))
+	assert.ErrorIs(t, h.Compare(hash, "wrong"), ErrMismatch)
+	assert.False(t, h.NeedsRehash(hash))
+}
+
+func TestArgon2id_ChangedParams_NeedsRehash(t *testing.T) {
+	hash, err := NewArgon2id(testArgon2Params).Hash("password")
+	assert.NoError(t, err)
+
+	stronger := NewArgon2id(Argon2idParams{Memory: 2048, Iterations: 1, Parallelism: 1})
+
+	assert.NoError(t, stronger.Compare(hash, "password"))
+	assert.True(t, stronger.NeedsRehash(hash))
+}
+
+func TestMulti_VerifiesLegacyBcryptAndRequestsRehash(t *testing.T) {
+	legacy, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	m := NewMulti(NewArgon2id(testArgon2Params), NewBcrypt(bcrypt.MinCost))
+
+	assert.NoError(t, m.Compare(string(legacy), "password"))
+	assert.ErrorIs(t, m.Compare(string(legacy), "wrong"), ErrMismatch)
+	assert.True(t, m.NeedsRehash(string(legacy)))
+
+	upgraded, err := m.Hash("password")
+	assert.NoError(t, err)
+	assert.False(t, m.NeedsRehash(upgraded))
+}
+
+func TestMulti_UnknownHash_ReturnsError(t *testing.T) {
+	m := NewMulti(NewArgon2id(testArgon2Params))
+
+	assert.ErrorIs(t, m.Compare("plaintext", "plaintext"), ErrUnknownHash)
+}
@@ -0,0 +1,18 @@
+package logger
+
+import (
+	"context"
+	"gw-currency-wallet/internal/requestid"
+
+	"go.uber.org/zap"
+)
+
+// L возвращает глобальный логгер, который добавляет к записям request_id из ctx.
+// Без ID в контексте возвращается zap.L().
+func L(ctx context.Context) *zap.Logger {
+	if id := requestid.From(ctx); id != "" {
+		return zap.L().With(zap.String("request_id", id))
+	}
+
+	return zap.L()
+}
@@ -0,0 +1,23 @@
+package logger
+
+import (
+	"gw-currency-wallet/internal/requestid"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestL_AddsRequestID(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	defer zap.ReplaceGlobals(zap.New(core))()
+
+	L(requestid.With(t.Context(), "req-1")).Info("with id")
+	L(t.Context()).Info("without id")
+
+	entries := logs.AllUntimed()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "req-1", entries[0].ContextMap()["request_id"])
+	assert.NotContains(t, entries[1].ContextMap(), "request_id")
+}
@@ -0,0 +1,46 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"gw-currency-wallet/internal/logger"
+	"os"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// FileMailer для разработки и тестов: дописывает письма в файл, а если путь
+// не задан — пишет в лог адресата и тему. Текст письма в лог не попадает:
+// в нём токены сброса пароля и подтверждения email.
+type FileMailer struct {
+	path string
+	mu   sync.Mutex
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	if m.path == "" {
+		logger.L(ctx).Info("mail", zap.String("to", msg.To), zap.String("subject", msg.Subject))
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, err := os.OpenFile(m.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(f, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n", time.Now().Format(time.RFC3339), msg.To, msg.Subject, msg.Body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+func NewFileMailer(path string) *FileMailer {
+	return &FileMailer{path: path}
+}
@@ -0,0 +1,37 @@
+package mailer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestFileMailer_AppendsMessages(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mail.log")
+	m := NewFileMailer(path)
+
+	assert.NoError(t, m.Send(t.Context(), Message{To: "alice@example.com", Subject: "First", Body: "one"}))
+	assert.NoError(t, m.Send(t.Context(), Message{To: "bob@example.com", Subject: "Second", Body: "two"}))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "To: alice@example.com\nSubject: First\n\none")
+	assert.Contains(t, string(data), "To: bob@example.com\nSubject: Second\n\ntwo")
+}
+
+func TestFileMailer_WithoutPath_DoesNotLogBody(t *testing.T) {
+	core, logs := observer.New(zap.InfoLevel)
+	defer zap.ReplaceGlobals(zap.New(core))()
+
+	m := NewFileMailer("")
+	assert.NoError(t, m.Send(t.Context(), Message{To: "alice@example.com", Subject: "Reset", Body: "token=secret"}))
+
+	entries := logs.AllUntimed()
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "alice@example.com", entries[0].ContextMap()["to"])
+	assert.NotContains(t, entries[0].ContextMap(), "body")
+}
@@ -0,0 +1,39 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/config"
+)
+
+const (
+	DriverSMTP = "smtp"
+	DriverFile = "file"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer отправляет письма пользователям
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewMailer выбирает реализацию по cfg.Driver. Драйвер обязателен, чтобы письма
+// со ссылками сброса пароля не ушли молча в лог.
+func NewMailer(cfg *config.MailerConfig) (Mailer, error) {
+	switch cfg.Driver {
+	case DriverSMTP:
+		return NewSMTPMailer(cfg), nil
+	case DriverFile:
+		return NewFileMailer(cfg.FilePath), nil
+	case "":
+		return nil, errors.New("mailer driver is required")
+	default:
+		return nil, fmt.Errorf("unknown mailer driver: %s", cfg.Driver)
+	}
+}
@@ -0,0 +1,13 @@
+package mailer
+
+import (
+	"gw-currency-wallet/config"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMailer_DriverRequired(t *testing.T) {
+	_, err := NewMailer(&config.MailerConfig{})
+	assert.Error(t, err)
+}
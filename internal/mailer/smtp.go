@@ -0,0 +1,137 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"gw-currency-wallet/config"
+	"mime"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+const (
+	DefaultSMTPPort    = 587
+	DefaultSMTPTimeout = 30 * time.Second
+)
+
+// SMTPMailer отправляет письма через SMTP-сервер. Если задан логин, используется
+// аутентификация PLAIN, которую net/smtp разрешает только поверх TLS или на localhost.
+type SMTPMailer struct {
+	addr    string
+	host    string
+	auth    smtp.Auth
+	from    string
+	timeout time.Duration
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", m.from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(msg.Body)
+
+	err := m.send(ctx, msg.To, buf.Bytes())
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return err
+}
+
+// send повторяет smtp.SendMail, но соединение ограничено таймаутом m.timeout
+// и закрывается досрочно при отмене ctx
+func (m *SMTPMailer) send(ctx context.Context, to string, data []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	if err = conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	c, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err = c.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return err
+		}
+	}
+
+	if m.auth != nil {
+		if ok, _ := c.Extension("AUTH"); ok {
+			if err = c.Auth(m.auth); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err = c.Mail(m.from); err != nil {
+		return err
+	}
+	if err = c.Rcpt(to); err != nil {
+		return err
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = w.Write(data); err != nil {
+		return err
+	}
+	if err = w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+func NewSMTPMailer(cfg *config.MailerConfig) *SMTPMailer {
+	port := cfg.SMTPPort
+	if port == 0 {
+		port = DefaultSMTPPort
+	}
+
+	timeout := cfg.SMTPTimeout
+	if timeout == 0 {
+		timeout = DefaultSMTPTimeout
+	}
+
+	m := &SMTPMailer{
+		addr:    fmt.Sprintf("%s:%d", cfg.SMTPHost, port),
+		host:    cfg.SMTPHost,
+		from:    cfg.From,
+		timeout: timeout,
+	}
+
+	if cfg.SMTPUsername != "" {
+		m.auth = smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPHost)
+	}
+
+	return m
+}
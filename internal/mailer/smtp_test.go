@@ -0,0 +1,59 @@
+package mailer
+
+import (
+	"context"
+	"gw-currency-wallet/config"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// silentSMTPServer принимает соединения и ничего не отвечает
+func silentSMTPServer(t *testing.T) *config.MailerConfig {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			t.Cleanup(func() { _ = conn.Close() })
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(l.Addr().String())
+	p, _ := strconv.Atoi(port)
+
+	return &config.MailerConfig{SMTPHost: host, SMTPPort: p, From: "wallet@example.com"}
+}
+
+func TestSMTPMailer_Send_StopsOnContextDeadline(t *testing.T) {
+	m := NewSMTPMailer(silentSMTPServer(t))
+
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := m.Send(ctx, Message{To: "alice@example.com", Subject: "Hi", Body: "text"})
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestSMTPMailer_Send_StopsOnTimeout(t *testing.T) {
+	cfg := silentSMTPServer(t)
+	cfg.SMTPTimeout = 100 * time.Millisecond
+	m := NewSMTPMailer(cfg)
+
+	start := time.Now()
+	err := m.Send(t.Context(), Message{To: "alice@example.com", Subject: "Hi", Body: "text"})
+
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
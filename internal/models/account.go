@@ -4,4 +4,9 @@ type Account struct {
 	Email        string
 	PasswordHash string
 	Username     string
+	// Verified email подтверждён
+	Verified bool
+	Role     Role
+	// Frozen операции со средствами запрещены администратором
+	Frozen bool
 }
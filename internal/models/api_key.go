@@ -0,0 +1,58 @@
+package models
+
+import "time"
+
+type Scope = string
+
+const (
+	ScopeBalanceRead      Scope = "balance:read"
+	ScopeTransactionsRead Scope = "transactions:read"
+	ScopeWalletDeposit    Scope = "wallet:deposit"
+	ScopeWalletWithdraw   Scope = "wallet:withdraw"
+	ScopeHoldsManage      Scope = "holds:manage"
+	ScopeExchangeRead     Scope = "exchange:read"
+	ScopeExchangeExecute  Scope = "exchange:execute"
+	ScopeTransferExecute  Scope = "transfer:execute"
+)
+
+// Scopes все области доступа, которые можно выдать ключу API
+var Scopes = []Scope{
+	ScopeBalanceRead,
+	ScopeTransactionsRead,
+	ScopeWalletDeposit,
+	ScopeWalletWithdraw,
+	ScopeHoldsManage,
+	ScopeExchangeRead,
+	ScopeExchangeExecute,
+	ScopeTransferExecute,
+}
+
+func IsValidScope(scope Scope) bool {
+	for _, s := range Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKey ключ API без секрета. AllowedIPs содержит адреса и подсети в нотации CIDR;
+// пустой список разрешает любой адрес.
+type APIKey struct {
+	ID         string
+	Email      string
+	Name       string
+	Prefix     string
+	Scopes     []Scope
+	AllowedIPs []string
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+	CreatedAt  time.Time
+}
+
+// NewAPIKey ключ API вместе с секретом, который показывается только при создании
+type NewAPIKey struct {
+	APIKey
+	Key string
+}
@@ -1,8 +1,54 @@
 package models
 
-import "github.com/golang-jwt/jwt/v5"
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
 
 type AuthClaims struct {
-	Email string `json:"email"`
+	Email     string `json:"email"`
+	SessionID string `json:"sid"`
+	// Role пуста в токенах, выданных до появления ролей, и означает RoleUser
+	Role Role `json:"role,omitempty"`
 	jwt.RegisteredClaims
 }
+
+// TokenPair короткоживущий access-токен и refresh-токен для его обновления.
+// AccessExpiresAt — срок действия access-токена.
+type TokenPair struct {
+	AccessToken     string
+	RefreshToken    string
+	AccessExpiresAt time.Time
+}
+
+// JWK открытый ключ проверки подписи токенов (RFC 7517)
+type JWK struct {
+	KeyType   string
+	KeyID     string
+	Use       string
+	Algorithm string
+	// N и E заданы для RSA, Curve и X — для Ed25519
+	N     string
+	E     string
+	Curve string
+	X     string
+}
+
+// LoginResult результат проверки пароля: пара токенов либо, если включена
+// двухфакторная аутентификация, Challenge для второго шага
+type LoginResult struct {
+	Tokens    *TokenPair
+	Challenge *TwoFactorChallenge
+}
+
+type TwoFactorChallenge struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// TOTPEnrollment секрет TOTP и ссылка otpauth:// для приложения-аутентификатора
+type TOTPEnrollment struct {
+	Secret string
+	URI    string
+}
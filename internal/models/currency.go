@@ -0,0 +1,13 @@
+package models
+
+import "gw-currency-wallet/pkg"
+
+// Currency валюта из справочника ISO 4217. MinorUnits — допустимое число
+// знаков после запятой в суммах.
+type Currency struct {
+	Code       pkg.Currency
+	Name       string
+	MinorUnits int32
+	Symbol     string
+	Enabled    bool
+}
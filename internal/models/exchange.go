@@ -0,0 +1,41 @@
+package models
+
+import (
+	"gw-currency-wallet/pkg"
+	"time"
+)
+
+// ExchangeOrder заявка на обмен. Если указан QuoteID, обмен выполняется
+// по курсу зафиксированной котировки. MinReceiveAmount и MaxRate
+// необязательны и ограничивают проскальзывание курса; MaxRate — максимальная
+// цена единицы To в единицах From.
+type ExchangeOrder struct {
+	From             pkg.Currency
+	To               pkg.Currency
+	Amount           pkg.Amount
+	QuoteID          string
+	MinReceiveAmount *pkg.Amount
+	MaxRate          *pkg.Rate
+}
+
+// ExchangeAmounts разбивка суммы обмена в валюте получения:
+// Net = Gross - Fee
+type ExchangeAmounts struct {
+	Rate  pkg.Rate
+	Gross pkg.Amount
+	Fee   pkg.Amount
+	Net   pkg.Amount
+}
+
+// ExchangeQuote котировка обмена. ReceiveAmount — сумма к получению
+// за вычетом комиссии Fee.
+type ExchangeQuote struct {
+	ID            string
+	From          pkg.Currency
+	To            pkg.Currency
+	Amount        pkg.Amount
+	Rate          pkg.Rate
+	ReceiveAmount pkg.Amount
+	Fee           pkg.Amount
+	ExpiresAt     time.Time
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"gw-currency-wallet/pkg"
+	"time"
+)
+
+type HoldStatus = string
+
+const (
+	HoldActive   HoldStatus = "active"
+	HoldCaptured HoldStatus = "captured"
+	HoldVoided   HoldStatus = "voided"
+	// HoldExpired не хранится в базе: активный холд с истёкшим сроком
+	// считается просроченным
+	HoldExpired HoldStatus = "expired"
+)
+
+type Hold struct {
+	ID             string
+	Currency       pkg.Currency
+	Amount         pkg.Amount
+	CapturedAmount pkg.Amount
+	Status         HoldStatus
+	ExpiresAt      time.Time
+	CreatedAt      time.Time
+}
+
+// WalletBalance баланс кошелька: Held зарезервировано холдами,
+// Available доступно для списания. Общий баланс равен их сумме.
+type WalletBalance struct {
+	Available pkg.Amount
+	Held      pkg.Amount
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"gw-currency-wallet/pkg"
+	"time"
+)
+
+type TransactionType = string
+
+const (
+	TransactionDeposit  TransactionType = "deposit"
+	TransactionWithdraw TransactionType = "withdraw"
+	TransactionExchange TransactionType = "exchange"
+	TransactionTransfer TransactionType = "transfer"
+	TransactionCapture  TransactionType = "capture"
+	// TransactionAdjustment ручная корректировка баланса администратором
+	TransactionAdjustment TransactionType = "adjustment"
+	// TransactionOpeningBalance перенос остатков, накопленных до появления журнала
+	TransactionOpeningBalance TransactionType = "opening_balance"
+)
+
+type EntryDirection = string
+
+const (
+	EntryDebit  EntryDirection = "debit"
+	EntryCredit EntryDirection = "credit"
+)
+
+// Системные счета журнала. Счёт пользователя в журнале — его email.
+const (
+	SystemCashAccount     = "system:cash"
+	SystemExchangeAccount = "system:exchange"
+	SystemRevenueAccount  = "system:revenue"
+	// SystemSettlementAccount получает средства, списанные по холдам
+	SystemSettlementAccount = "system:settlement"
+	// SystemAdjustmentAccount противоположная сторона ручных корректировок баланса
+	SystemAdjustmentAccount = "system:adjustment"
+)
+
+type LedgerEntry struct {
+	Account   string
+	Currency  pkg.Currency
+	Direction EntryDirection
+	Amount    pkg.Amount
+}
+
+func Debit(account string, currency pkg.Currency, amount pkg.Amount) LedgerEntry {
+	return LedgerEntry{Account: account, Currency: currency, Direction: EntryDebit, Amount: amount}
+}
+
+func Credit(account string, currency pkg.Currency, amount pkg.Amount) LedgerEntry {
+	return LedgerEntry{Account: account, Currency: currency, Direction: EntryCredit, Amount: amount}
+}
+
+type TransactionFilter struct {
+	Currency pkg.Currency
+	Type     TransactionType
+	From     time.Time
+	To       time.Time
+}
+
+// TransactionCursor позиция последней выданной записи истории
+type TransactionCursor struct {
+	CreatedAt time.Time
+	ID        int64
+}
+
+type Transaction struct {
+	ID        int64
+	Type      TransactionType
+	CreatedAt time.Time
+	Entries   []TransactionEntry
+}
+
+// TransactionEntry изменение баланса владельца истории: поступление положительно,
+// списание отрицательно
+type TransactionEntry struct {
+	Currency pkg.Currency
+	Amount   pkg.Amount
+}
+
+type TransactionPage struct {
+	Transactions []Transaction
+	NextCursor   string
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+type LoginScope = string
+
+const (
+	LoginScopeAccount LoginScope = "account"
+	LoginScopeIP      LoginScope = "ip"
+)
+
+// LockoutEvent запись о блокировке входа. UnlockedAt задан, если блокировку снял администратор.
+type LockoutEvent struct {
+	ID          int64
+	Scope       LoginScope
+	Subject     string
+	Failures    int
+	LockedUntil time.Time
+	CreatedAt   time.Time
+	UnlockedAt  *time.Time
+	UnlockedBy  string
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+type RateLimitGroup = string
+
+const (
+	RateLimitGroupAuth     RateLimitGroup = "auth"
+	RateLimitGroupExchange RateLimitGroup = "exchange"
+	RateLimitGroupWallet   RateLimitGroup = "wallet"
+	RateLimitGroupDefault  RateLimitGroup = "default"
+)
+
+// RateLimitResult состояние корзины после запроса. Reset — через сколько корзина
+// наполнится полностью, RetryAfter — через сколько появится запас для отклонённого запроса.
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Period     time.Duration
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
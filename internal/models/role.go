@@ -0,0 +1,78 @@
+package models
+
+import "time"
+
+type Role = string
+
+const (
+	RoleUser    Role = "user"
+	RoleSupport Role = "support"
+	RoleAdmin   Role = "admin"
+)
+
+type Permission = string
+
+const (
+	PermissionAccountsRead   Permission = "accounts:read"
+	PermissionWalletsRead    Permission = "wallets:read"
+	PermissionAccountsFreeze Permission = "accounts:freeze"
+	PermissionBalanceAdjust  Permission = "balance:adjust"
+	PermissionRolesManage    Permission = "roles:manage"
+	PermissionLockoutsManage Permission = "lockouts:manage"
+	PermissionAuditRead      Permission = "audit:read"
+)
+
+var rolePermissions = map[Role][]Permission{
+	RoleSupport: {
+		PermissionAccountsRead,
+		PermissionWalletsRead,
+		PermissionLockoutsManage,
+		PermissionAuditRead,
+	},
+	RoleAdmin: {
+		PermissionAccountsRead,
+		PermissionWalletsRead,
+		PermissionAccountsFreeze,
+		PermissionBalanceAdjust,
+		PermissionRolesManage,
+		PermissionLockoutsManage,
+		PermissionAuditRead,
+	},
+}
+
+func IsValidRole(role Role) bool {
+	return role == RoleUser || role == RoleSupport || role == RoleAdmin
+}
+
+// HasPermission сообщает, разрешено ли роли действие. У обычного пользователя
+// административных прав нет.
+func HasPermission(role Role, permission Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+type AuditAction = string
+
+const (
+	AuditFreeze     AuditAction = "freeze"
+	AuditUnfreeze   AuditAction = "unfreeze"
+	AuditAdjustment AuditAction = "adjustment"
+	AuditSetRole    AuditAction = "set_role"
+	AuditUnlock     AuditAction = "unlock_login"
+)
+
+// AuditEntry запись журнала действий администраторов. Subject — email аккаунта
+// или, для снятия блокировки входа, IP-адрес.
+type AuditEntry struct {
+	ID        int64
+	Actor     string
+	Action    AuditAction
+	Subject   string
+	Reason    string
+	Details   map[string]any
+	CreatedAt time.Time
+}
@@ -2,10 +2,21 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/logger"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"go.uber.org/zap"
+)
+
+const (
+	uniqueViolationCode = "23505"
+
+	accountPrimaryKeyConstraint = "account_pkey"
+	accountUsernameConstraint   = "account_username_lower_key"
+	accountEmailConstraint      = "account_email_lower_key"
 )
 
 type AccountRepository struct {
@@ -17,35 +28,33 @@ func (r *AccountRepository) GetByUsername(ctx context.Context, username string)
 
 	account, err := q.GetAccountByUsername(ctx, username)
 	if err != nil {
-		zap.L().Error(err.Error())
-		return nil, err
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, nil
+		default:
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
 	}
 
 	return &account, nil
 }
 
-func (r *AccountRepository) IsEmailExist(ctx context.Context, email string) (bool, error) {
+func (r *AccountRepository) GetByEmail(ctx context.Context, email string) (*db.AppAccount, error) {
 	q := r.getQueries(ctx)
 
-	isExist, err := q.IsAccountExistsByEmail(ctx, email)
+	account, err := q.GetAccountByEmail(ctx, email)
 	if err != nil {
-		zap.L().Error(err.Error())
-		return false, err
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, nil
+		default:
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
 	}
 
-	return isExist, nil
-}
-
-func (r *AccountRepository) IsUsernameExist(ctx context.Context, username string) (bool, error) {
-	q := r.getQueries(ctx)
-
-	isExist, err := q.IsAccountExistsByUsername(ctx, username)
-	if err != nil {
-		zap.L().Error(err.Error())
-		return false, err
-	}
-
-	return isExist, nil
+	return &account, nil
 }
 
 func (r *AccountRepository) Create(ctx context.Context, email, username, passwordHash string) (*db.AppAccount, error) {
@@ -57,13 +66,38 @@ func (r *AccountRepository) Create(ctx context.Context, email, username, passwor
 		Password: passwordHash,
 	})
 	if err != nil {
-		zap.L().Error(err.Error())
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+			switch pgErr.ConstraintName {
+			case accountUsernameConstraint:
+				return nil, ErrUsernameTaken
+			case accountEmailConstraint, accountPrimaryKeyConstraint:
+				return nil, ErrEmailTaken
+			}
+		}
+		logger.L(ctx).Error(err.Error())
 		return nil, err
 	}
 
 	return &row, nil
 }
 
+func (r *AccountRepository) RehashPassword(ctx context.Context, email, currentHash, newHash string) (bool, error) {
+	q := r.getQueries(ctx)
+
+	affected, err := q.RehashAccountPassword(ctx, db.RehashAccountPasswordParams{
+		NewPassword:     newHash,
+		Email:           email,
+		CurrentPassword: currentHash,
+	})
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
 func NewAccountRepository(pool *pgxpool.Pool, queries *db.Queries) *AccountRepository {
 	return &AccountRepository{
 		TxRepositoryImpl{
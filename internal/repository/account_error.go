@@ -0,0 +1,8 @@
+package repository
+
+import "errors"
+
+var (
+	ErrUsernameTaken = errors.New("username is already taken")
+	ErrEmailTaken    = errors.New("email is already taken")
+)
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/logger"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AdminRepository struct {
+	TxRepositoryImpl
+}
+
+// SearchAccounts ищет аккаунты, email или имя которых содержит query без учёта регистра.
+// Символы % и _ в query должны быть экранированы вызывающей стороной.
+func (r *AdminRepository) SearchAccounts(ctx context.Context, query string, limit int) ([]db.AppAccount, error) {
+	q := r.getQueries(ctx)
+
+	rows, err := q.SearchAccounts(ctx, db.SearchAccountsParams{
+		Query:    query,
+		PageSize: int32(limit),
+	})
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// SetFrozen возвращает false, если аккаунт не найден
+func (r *AdminRepository) SetFrozen(ctx context.Context, email string, frozen bool) (bool, error) {
+	q := r.getQueries(ctx)
+
+	n, err := q.SetAccountFrozen(ctx, db.SetAccountFrozenParams{
+		Frozen: frozen,
+		Email:  email,
+	})
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+// SetRole возвращает false, если аккаунт не найден
+func (r *AdminRepository) SetRole(ctx context.Context, email, role string) (bool, error) {
+	q := r.getQueries(ctx)
+
+	n, err := q.SetAccountRole(ctx, db.SetAccountRoleParams{
+		Email: email,
+		Role:  role,
+	})
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+func (r *AdminRepository) CreateAuditEntry(ctx context.Context, actor, action, subject, reason string, details []byte) error {
+	q := r.getQueries(ctx)
+
+	if err := q.CreateAdminAuditEntry(ctx, db.CreateAdminAuditEntryParams{
+		Actor:   actor,
+		Action:  action,
+		Subject: subject,
+		Reason:  reason,
+		Details: details,
+	}); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// ListAuditEntries возвращает записи журнала от новых к старым. Пустой subject — все записи.
+func (r *AdminRepository) ListAuditEntries(ctx context.Context, subject string, limit int) ([]db.AppAdminAuditLog, error) {
+	q := r.getQueries(ctx)
+
+	rows, err := q.ListAdminAuditEntries(ctx, db.ListAdminAuditEntriesParams{
+		Subject:  pgtype.Text{String: subject, Valid: subject != ""},
+		PageSize: int32(limit),
+	})
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func NewAdminRepository(pool *pgxpool.Pool, queries *db.Queries) *AdminRepository {
+	return &AdminRepository{
+		TxRepositoryImpl{
+			db: pool,
+			q:  queries,
+		},
+	}
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/logger"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type APIKeyRepository struct {
+	TxRepositoryImpl
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, email, name, prefix, keyHash string, scopes, allowedIPs []string, expiresAt *time.Time) (*db.AppApiKey, error) {
+	q := r.getQueries(ctx)
+
+	var expires pgtype.Timestamptz
+	if expiresAt != nil {
+		expires = pgtype.Timestamptz{Time: *expiresAt, Valid: true}
+	}
+
+	row, err := q.CreateAPIKey(ctx, db.CreateAPIKeyParams{
+		Email:      email,
+		Name:       name,
+		Prefix:     prefix,
+		KeyHash:    keyHash,
+		Scopes:     scopes,
+		AllowedIps: allowedIPs,
+		ExpiresAt:  expires,
+	})
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return &row, nil
+}
+
+// GetByPrefix возвращает nil, если ключ не найден
+func (r *APIKeyRepository) GetByPrefix(ctx context.Context, prefix string) (*db.AppApiKey, error) {
+	q := r.getQueries(ctx)
+
+	row, err := q.GetAPIKeyByPrefix(ctx, prefix)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, nil
+		default:
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+	}
+
+	return &row, nil
+}
+
+func (r *APIKeyRepository) List(ctx context.Context, email string) ([]db.AppApiKey, error) {
+	q := r.getQueries(ctx)
+
+	rows, err := q.ListAPIKeys(ctx, email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// Revoke возвращает false, если действующий ключ с таким id у аккаунта не найден
+// или id не является UUID
+func (r *APIKeyRepository) Revoke(ctx context.Context, email, id string) (bool, error) {
+	q := r.getQueries(ctx)
+
+	var keyID pgtype.UUID
+	if err := keyID.Scan(id); err != nil {
+		return false, nil
+	}
+
+	affected, err := q.RevokeAPIKey(ctx, db.RevokeAPIKeyParams{
+		ID:    keyID,
+		Email: email,
+	})
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// Touch обновляет время последнего использования не чаще раза в минуту
+func (r *APIKeyRepository) Touch(ctx context.Context, id pgtype.UUID) error {
+	q := r.getQueries(ctx)
+
+	if err := q.TouchAPIKey(ctx, id); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func NewAPIKeyRepository(pool *pgxpool.Pool, queries *db.Queries) *APIKeyRepository {
+	return &APIKeyRepository{
+		TxRepositoryImpl{
+			db: pool,
+			q:  queries,
+		},
+	}
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/logger"
+	"gw-currency-wallet/pkg"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CurrencyRepository struct {
+	TxRepositoryImpl
+}
+
+func (r *CurrencyRepository) Get(ctx context.Context, code pkg.Currency) (*db.AppCurrency, error) {
+	q := r.getQueries(ctx)
+
+	row, err := q.GetCurrency(ctx, code)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, nil
+		default:
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+	}
+
+	return &row, nil
+}
+
+func (r *CurrencyRepository) List(ctx context.Context) ([]db.AppCurrency, error) {
+	q := r.getQueries(ctx)
+
+	rows, err := q.ListCurrencies(ctx)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func NewCurrencyRepository(pool *pgxpool.Pool, queries *db.Queries) *CurrencyRepository {
+	return &CurrencyRepository{
+		TxRepositoryImpl{
+			db: pool,
+			q:  queries,
+		},
+	}
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/logger"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type EmailVerificationRepository struct {
+	TxRepositoryImpl
+}
+
+func (r *EmailVerificationRepository) CreateToken(ctx context.Context, tokenHash, email string, expiresAt time.Time) error {
+	q := r.getQueries(ctx)
+
+	if err := q.CreateEmailVerificationToken(ctx, db.CreateEmailVerificationTokenParams{
+		TokenHash: tokenHash,
+		Email:     email,
+		ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	}); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// GetTokenForUpdate возвращает nil, если токен не найден
+func (r *EmailVerificationRepository) GetTokenForUpdate(ctx context.Context, tokenHash string) (*db.AppEmailVerificationToken, error) {
+	q := r.getQueries(ctx)
+
+	row, err := q.GetEmailVerificationTokenForUpdate(ctx, tokenHash)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, nil
+		default:
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+	}
+
+	return &row, nil
+}
+
+// LastSentAt время создания последнего токена аккаунта; nil, если токенов не было
+func (r *EmailVerificationRepository) LastSentAt(ctx context.Context, email string) (*time.Time, error) {
+	q := r.getQueries(ctx)
+
+	sentAt, err := q.GetLastEmailVerificationSentAt(ctx, email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	if !sentAt.Valid {
+		return nil, nil
+	}
+
+	return &sentAt.Time, nil
+}
+
+// UseTokens отмечает использованными все действующие токены аккаунта
+func (r *EmailVerificationRepository) UseTokens(ctx context.Context, email string) error {
+	q := r.getQueries(ctx)
+
+	if err := q.UseEmailVerificationTokens(ctx, email); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (r *EmailVerificationRepository) MarkVerified(ctx context.Context, email string) error {
+	q := r.getQueries(ctx)
+
+	if err := q.VerifyAccountEmail(ctx, email); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func NewEmailVerificationRepository(pool *pgxpool.Pool, queries *db.Queries) *EmailVerificationRepository {
+	return &EmailVerificationRepository{
+		TxRepositoryImpl{
+			db: pool,
+			q:  queries,
+		},
+	}
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/logger"
+	"gw-currency-wallet/pkg"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type FeeRepository struct {
+	TxRepositoryImpl
+}
+
+// Get возвращает наиболее точное правило для пары: сначала точное совпадение,
+// затем правила с '*'. Возвращает nil, если подходящего правила нет.
+func (r *FeeRepository) Get(ctx context.Context, from, to pkg.Currency) (*db.AppExchangeFee, error) {
+	q := r.getQueries(ctx)
+
+	row, err := q.GetExchangeFee(ctx, db.GetExchangeFeeParams{
+		FromCurrency: from,
+		ToCurrency:   to,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, nil
+		default:
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+	}
+
+	return &row, nil
+}
+
+func NewFeeRepository(pool *pgxpool.Pool, queries *db.Queries) *FeeRepository {
+	return &FeeRepository{
+		TxRepositoryImpl{
+			db: pool,
+			q:  queries,
+		},
+	}
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/logger"
+	"gw-currency-wallet/pkg"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+)
+
+func (r *WalletRepository) CreateHold(ctx context.Context, email string, currency pkg.Currency, amount pkg.Amount, expiresAt time.Time) (*db.AppHold, error) {
+	q := r.getQueries(ctx)
+
+	row, err := q.CreateHold(ctx, db.CreateHoldParams{
+		Email:     email,
+		Currency:  currency,
+		Amount:    amount,
+		ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	})
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return &row, nil
+}
+
+// GetHoldForUpdate возвращает nil, если холд не найден, принадлежит другому
+// пользователю или id не является UUID
+func (r *WalletRepository) GetHoldForUpdate(ctx context.Context, id, email string) (*db.AppHold, error) {
+	q := r.getQueries(ctx)
+
+	var holdID pgtype.UUID
+	if err := holdID.Scan(id); err != nil {
+		return nil, nil
+	}
+
+	row, err := q.GetHoldForUpdate(ctx, db.GetHoldForUpdateParams{
+		ID:    holdID,
+		Email: email,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, nil
+		default:
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+	}
+
+	return &row, nil
+}
+
+// GetHeldAmount возвращает сумму действующих холдов по кошельку
+func (r *WalletRepository) GetHeldAmount(ctx context.Context, email string, currency pkg.Currency) (pkg.Amount, error) {
+	q := r.getQueries(ctx)
+
+	held, err := q.GetHeldAmount(ctx, db.GetHeldAmountParams{
+		Email:    email,
+		Currency: currency,
+	})
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return decimal.Zero, err
+	}
+
+	return held, nil
+}
+
+func (r *WalletRepository) GetHeldAmounts(ctx context.Context, email string) ([]db.GetHeldAmountsByEmailRow, error) {
+	q := r.getQueries(ctx)
+
+	rows, err := q.GetHeldAmountsByEmail(ctx, email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *WalletRepository) UpdateHoldStatus(ctx context.Context, id pgtype.UUID, status string, capturedAmount pkg.Amount) (*db.AppHold, error) {
+	q := r.getQueries(ctx)
+
+	row, err := q.UpdateHoldStatus(ctx, db.UpdateHoldStatusParams{
+		ID:             id,
+		Status:         status,
+		CapturedAmount: capturedAmount,
+	})
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return &row, nil
+}
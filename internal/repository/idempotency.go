@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type IdempotencyRepository struct {
+	TxRepositoryImpl
+}
+
+func (r *IdempotencyRepository) Claim(ctx context.Context, email, key, operation, requestHash string) (bool, error) {
+	q := r.getQueries(ctx)
+
+	affected, err := q.ClaimIdempotencyKey(ctx, db.ClaimIdempotencyKeyParams{
+		Email:       email,
+		Key:         key,
+		Operation:   operation,
+		RequestHash: requestHash,
+	})
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+func (r *IdempotencyRepository) Get(ctx context.Context, email, key string) (*db.AppIdempotencyKey, error) {
+	q := r.getQueries(ctx)
+
+	row, err := q.GetIdempotencyKey(ctx, db.GetIdempotencyKeyParams{
+		Email: email,
+		Key:   key,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, nil
+		default:
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+	}
+
+	return &row, nil
+}
+
+func (r *IdempotencyRepository) SaveResponse(ctx context.Context, email, key string, response []byte) error {
+	q := r.getQueries(ctx)
+
+	if err := q.SaveIdempotencyResponse(ctx, db.SaveIdempotencyResponseParams{
+		Email:    email,
+		Key:      key,
+		Response: response,
+	}); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func NewIdempotencyRepository(pool *pgxpool.Pool, queries *db.Queries) *IdempotencyRepository {
+	return &IdempotencyRepository{
+		TxRepositoryImpl{
+			db: pool,
+			q:  queries,
+		},
+	}
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/logger"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/pkg"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type LedgerRepository struct {
+	TxRepositoryImpl
+}
+
+func (r *LedgerRepository) CreateTransaction(ctx context.Context, txType string) (*db.AppTransaction, error) {
+	q := r.getQueries(ctx)
+
+	row, err := q.CreateTransaction(ctx, txType)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return &row, nil
+}
+
+func (r *LedgerRepository) CreateEntry(ctx context.Context, transactionID int64, account string, currency pkg.Currency, direction string, amount pkg.Amount) error {
+	q := r.getQueries(ctx)
+
+	if err := q.CreateLedgerEntry(ctx, db.CreateLedgerEntryParams{
+		TransactionID: transactionID,
+		Account:       account,
+		Currency:      currency,
+		Direction:     direction,
+		Amount:        amount,
+	}); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (r *LedgerRepository) ListByAccount(ctx context.Context, account string, filter models.TransactionFilter, cursor *models.TransactionCursor, limit int32) ([]db.AppTransaction, error) {
+	q := r.getQueries(ctx)
+
+	params := db.ListAccountTransactionsParams{
+		Account:  account,
+		Currency: pgtype.Text{String: filter.Currency, Valid: filter.Currency != ""},
+		Type:     pgtype.Text{String: filter.Type, Valid: filter.Type != ""},
+		FromTime: pgtype.Timestamptz{Time: filter.From, Valid: !filter.From.IsZero()},
+		ToTime:   pgtype.Timestamptz{Time: filter.To, Valid: !filter.To.IsZero()},
+		PageSize: limit,
+	}
+	if cursor != nil {
+		params.CursorTime = pgtype.Timestamptz{Time: cursor.CreatedAt, Valid: true}
+		params.CursorID = pgtype.Int8{Int64: cursor.ID, Valid: true}
+	}
+
+	rows, err := q.ListAccountTransactions(ctx, params)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *LedgerRepository) GetEntriesByTransactions(ctx context.Context, account string, transactionIDs []int64) ([]db.AppLedgerEntry, error) {
+	q := r.getQueries(ctx)
+
+	rows, err := q.GetAccountEntriesByTransactions(ctx, db.GetAccountEntriesByTransactionsParams{
+		Account:        account,
+		TransactionIds: transactionIDs,
+	})
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func NewLedgerRepository(pool *pgxpool.Pool, queries *db.Queries) *LedgerRepository {
+	return &LedgerRepository{
+		TxRepositoryImpl{
+			db: pool,
+			q:  queries,
+		},
+	}
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/logger"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type LoginAttemptRepository struct {
+	TxRepositoryImpl
+}
+
+// Get возвращает nil, если неудачных попыток не было
+func (r *LoginAttemptRepository) Get(ctx context.Context, scope, subject string) (*db.AppLoginAttempt, error) {
+	q := r.getQueries(ctx)
+
+	row, err := q.GetLoginAttempt(ctx, db.GetLoginAttemptParams{
+		Scope:   scope,
+		Subject: subject,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, nil
+		default:
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+	}
+
+	return &row, nil
+}
+
+// RecordFailure увеличивает счётчик неудачных попыток. Если предыдущая неудача была
+// раньше resetBefore, счётчик начинается заново.
+func (r *LoginAttemptRepository) RecordFailure(ctx context.Context, scope, subject string, resetBefore time.Time) (*db.AppLoginAttempt, error) {
+	q := r.getQueries(ctx)
+
+	row, err := q.RecordLoginFailure(ctx, db.RecordLoginFailureParams{
+		Scope:       scope,
+		Subject:     subject,
+		ResetBefore: pgtype.Timestamptz{Time: resetBefore, Valid: true},
+	})
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return &row, nil
+}
+
+func (r *LoginAttemptRepository) SetLockedUntil(ctx context.Context, scope, subject string, lockedUntil time.Time, failures int32) error {
+	q := r.getQueries(ctx)
+
+	if err := q.SetLoginLockedUntil(ctx, db.SetLoginLockedUntilParams{
+		Scope:       scope,
+		Subject:     subject,
+		LockedUntil: pgtype.Timestamptz{Time: lockedUntil, Valid: true},
+		Failures:    failures,
+	}); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (r *LoginAttemptRepository) Delete(ctx context.Context, scope, subject string) error {
+	q := r.getQueries(ctx)
+
+	if err := q.DeleteLoginAttempt(ctx, db.DeleteLoginAttemptParams{
+		Scope:   scope,
+		Subject: subject,
+	}); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (r *LoginAttemptRepository) CreateLockoutEvent(ctx context.Context, scope, subject string, failures int32, lockedUntil time.Time) error {
+	q := r.getQueries(ctx)
+
+	if err := q.CreateLockoutEvent(ctx, db.CreateLockoutEventParams{
+		Scope:       scope,
+		Subject:     subject,
+		Failures:    failures,
+		LockedUntil: pgtype.Timestamptz{Time: lockedUntil, Valid: true},
+	}); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (r *LoginAttemptRepository) ListLockoutEvents(ctx context.Context, scope, subject string, limit int32) ([]db.AppLockoutEvent, error) {
+	q := r.getQueries(ctx)
+
+	rows, err := q.ListLockoutEvents(ctx, db.ListLockoutEventsParams{
+		Scope:   scope,
+		Subject: subject,
+		Limit:   limit,
+	})
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *LoginAttemptRepository) MarkUnlocked(ctx context.Context, scope, subject, unlockedBy string) error {
+	q := r.getQueries(ctx)
+
+	if err := q.MarkLockoutEventsUnlocked(ctx, db.MarkLockoutEventsUnlockedParams{
+		Scope:      scope,
+		Subject:    subject,
+		UnlockedBy: pgtype.Text{String: unlockedBy, Valid: unlockedBy != ""},
+	}); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func NewLoginAttemptRepository(pool *pgxpool.Pool, queries *db.Queries) *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		TxRepositoryImpl{
+			db: pool,
+			q:  queries,
+		},
+	}
+}
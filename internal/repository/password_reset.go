@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/logger"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PasswordResetRepository struct {
+	TxRepositoryImpl
+}
+
+func (r *PasswordResetRepository) CreateToken(ctx context.Context, tokenHash, email string, expiresAt time.Time) error {
+	q := r.getQueries(ctx)
+
+	if err := q.CreatePasswordResetToken(ctx, db.CreatePasswordResetTokenParams{
+		TokenHash: tokenHash,
+		Email:     email,
+		ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	}); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// GetTokenForUpdate возвращает nil, если токен не найден
+func (r *PasswordResetRepository) GetTokenForUpdate(ctx context.Context, tokenHash string) (*db.AppPasswordResetToken, error) {
+	q := r.getQueries(ctx)
+
+	row, err := q.GetPasswordResetTokenForUpdate(ctx, tokenHash)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, nil
+		default:
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+	}
+
+	return &row, nil
+}
+
+// UseTokens отмечает использованными все действующие токены аккаунта
+func (r *PasswordResetRepository) UseTokens(ctx context.Context, email string) error {
+	q := r.getQueries(ctx)
+
+	if err := q.UsePasswordResetTokens(ctx, email); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (r *PasswordResetRepository) UpdatePassword(ctx context.Context, email, passwordHash string) error {
+	q := r.getQueries(ctx)
+
+	if err := q.UpdateAccountPassword(ctx, db.UpdateAccountPasswordParams{
+		Email:    email,
+		Password: passwordHash,
+	}); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func NewPasswordResetRepository(pool *pgxpool.Pool, queries *db.Queries) *PasswordResetRepository {
+	return &PasswordResetRepository{
+		TxRepositoryImpl{
+			db: pool,
+			q:  queries,
+		},
+	}
+}
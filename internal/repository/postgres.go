@@ -10,7 +10,20 @@ func NewPostgresRepository(pool *pgxpool.Pool) (*Repository, error) {
 	queries := db.New(pool)
 
 	return &Repository{
-		Account: NewAccountRepository(pool, queries),
-		Wallet:  NewWalletRepository(pool, queries),
+		Account:           NewAccountRepository(pool, queries),
+		Wallet:            NewWalletRepository(pool, queries),
+		Ledger:            NewLedgerRepository(pool, queries),
+		Idempotency:       NewIdempotencyRepository(pool, queries),
+		Quote:             NewQuoteRepository(pool, queries),
+		Fee:               NewFeeRepository(pool, queries),
+		Currency:          NewCurrencyRepository(pool, queries),
+		Session:           NewSessionRepository(pool, queries),
+		LoginAttempt:      NewLoginAttemptRepository(pool, queries),
+		TwoFactor:         NewTwoFactorRepository(pool, queries),
+		PasswordReset:     NewPasswordResetRepository(pool, queries),
+		EmailVerification: NewEmailVerificationRepository(pool, queries),
+		Admin:             NewAdminRepository(pool, queries),
+		APIKey:            NewAPIKeyRepository(pool, queries),
+		RateLimit:         NewRateLimitRepository(pool, queries),
 	}, nil
 }
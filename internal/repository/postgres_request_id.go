@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"gw-currency-wallet/internal/requestid"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const applicationName = "gw-currency-wallet"
+
+// TrackRequestID выставляет application_name соединения равным ID запроса, для которого
+// соединение берётся из пула, чтобы запросы в pg_stat_activity и журнале Postgres (%a
+// в log_line_prefix) можно было связать с логами сервиса. Без ID используется имя приложения.
+func TrackRequestID(config *pgxpool.Config) {
+	config.ConnConfig.RuntimeParams["application_name"] = applicationName
+	config.PrepareConn = func(ctx context.Context, conn *pgx.Conn) (bool, error) {
+		name := requestid.From(ctx)
+		if name == "" {
+			name = applicationName
+		}
+
+		// сервер сообщает об изменении application_name, поэтому лишний запрос не нужен
+		if conn.PgConn().ParameterStatus("application_name") == name {
+			return true, nil
+		}
+
+		if _, err := conn.Exec(ctx, "SELECT set_config('application_name', $1, false)", name); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+}
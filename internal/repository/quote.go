@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/logger"
+	"gw-currency-wallet/pkg"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type QuoteRepository struct {
+	TxRepositoryImpl
+}
+
+func (r *QuoteRepository) Create(ctx context.Context, email string, from, to pkg.Currency, amount pkg.Amount, rate pkg.Rate, receiveAmount, fee pkg.Amount, expiresAt time.Time) (*db.AppExchangeQuote, error) {
+	q := r.getQueries(ctx)
+
+	row, err := q.CreateExchangeQuote(ctx, db.CreateExchangeQuoteParams{
+		Email:         email,
+		FromCurrency:  from,
+		ToCurrency:    to,
+		Amount:        amount,
+		Rate:          rate,
+		ReceiveAmount: receiveAmount,
+		Fee:           fee,
+		ExpiresAt:     pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	})
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return &row, nil
+}
+
+// GetForUpdate возвращает nil, если котировка не найдена, принадлежит другому
+// пользователю или id не является UUID
+func (r *QuoteRepository) GetForUpdate(ctx context.Context, id, email string) (*db.AppExchangeQuote, error) {
+	q := r.getQueries(ctx)
+
+	var quoteID pgtype.UUID
+	if err := quoteID.Scan(id); err != nil {
+		return nil, nil
+	}
+
+	row, err := q.GetExchangeQuoteForUpdate(ctx, db.GetExchangeQuoteForUpdateParams{
+		ID:    quoteID,
+		Email: email,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, nil
+		default:
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+	}
+
+	return &row, nil
+}
+
+func (r *QuoteRepository) MarkUsed(ctx context.Context, id pgtype.UUID) error {
+	q := r.getQueries(ctx)
+
+	if err := q.MarkExchangeQuoteUsed(ctx, id); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func NewQuoteRepository(pool *pgxpool.Pool, queries *db.Queries) *QuoteRepository {
+	return &QuoteRepository{
+		TxRepositoryImpl{
+			db: pool,
+			q:  queries,
+		},
+	}
+}
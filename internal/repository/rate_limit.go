@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/logger"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	RateLimitBackendMemory   = "memory"
+	RateLimitBackendPostgres = "postgres"
+)
+
+// NewRateLimit выбирает хранилище счётчиков частоты запросов по backend. По умолчанию
+// счётчики хранятся в памяти процесса.
+func NewRateLimit(backend string, pool *pgxpool.Pool) (RateLimit, error) {
+	switch backend {
+	case RateLimitBackendMemory, "":
+		return NewMemoryRateLimitRepository(), nil
+	case RateLimitBackendPostgres:
+		return NewRateLimitRepository(pool, db.New(pool)), nil
+	default:
+		return nil, fmt.Errorf("unknown rate limit backend: %s", backend)
+	}
+}
+
+// RateLimitRepository хранит корзины в базе, поэтому лимиты общие для всех экземпляров.
+// Запас пересчитывается одним запросом по времени базы, и часы экземпляров не влияют на результат.
+type RateLimitRepository struct {
+	TxRepositoryImpl
+}
+
+func (r *RateLimitRepository) Take(ctx context.Context, key string, capacity, refillRate float64) (tokens float64, allowed bool, err error) {
+	q := r.getQueries(ctx)
+
+	row, err := q.TakeRateLimitToken(ctx, db.TakeRateLimitTokenParams{
+		Key:        key,
+		Capacity:   capacity,
+		RefillRate: refillRate,
+	})
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return 0, false, err
+	}
+
+	return row.Tokens, row.Allowed, nil
+}
+
+func (r *RateLimitRepository) DeleteIdle(ctx context.Context, before time.Time) (int64, error) {
+	q := r.getQueries(ctx)
+
+	deleted, err := q.DeleteIdleRateLimitBuckets(ctx, pgtype.Timestamptz{Time: before, Valid: true})
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return 0, err
+	}
+
+	return deleted, nil
+}
+
+func NewRateLimitRepository(pool *pgxpool.Pool, queries *db.Queries) *RateLimitRepository {
+	return &RateLimitRepository{
+		TxRepositoryImpl{
+			db: pool,
+			q:  queries,
+		},
+	}
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memoryBucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// MemoryRateLimitRepository хранит корзины в памяти процесса. Подходит для одного
+// экземпляра: при нескольких каждый считает запросы отдельно.
+type MemoryRateLimitRepository struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+	now     func() time.Time
+}
+
+func (r *MemoryRateLimitRepository) Take(_ context.Context, key string, capacity, refillRate float64) (tokens float64, allowed bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+
+	bucket, ok := r.buckets[key]
+	if !ok {
+		bucket = &memoryBucket{tokens: capacity, updatedAt: now}
+		r.buckets[key] = bucket
+	}
+
+	bucket.tokens = min(capacity, bucket.tokens+now.Sub(bucket.updatedAt).Seconds()*refillRate)
+	bucket.updatedAt = now
+
+	if bucket.tokens < 1 {
+		return bucket.tokens, false, nil
+	}
+
+	bucket.tokens--
+
+	return bucket.tokens, true, nil
+}
+
+func (r *MemoryRateLimitRepository) DeleteIdle(_ context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for key, bucket := range r.buckets {
+		if bucket.updatedAt.Before(before) {
+			delete(r.buckets, key)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+func NewMemoryRateLimitRepository() *MemoryRateLimitRepository {
+	return &MemoryRateLimitRepository{
+		buckets: make(map[string]*memoryBucket),
+		now:     time.Now,
+	}
+}
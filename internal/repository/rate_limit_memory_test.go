@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryRateLimit_TakeAndRefill(t *testing.T) {
+	now := time.Date(2025, 12, 19, 9, 0, 0, 0, time.UTC)
+	r := NewMemoryRateLimitRepository()
+	r.now = func() time.Time { return now }
+
+	for i := 0; i < 3; i++ {
+		_, allowed, err := r.Take(t.Context(), "auth:ip:10.0.0.1", 3, 1)
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+	}
+
+	tokens, allowed, err := r.Take(t.Context(), "auth:ip:10.0.0.1", 3, 1)
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+	assert.Equal(t, float64(0), tokens)
+
+	// Другой клиент считается отдельно
+	_, allowed, _ = r.Take(t.Context(), "auth:ip:10.0.0.2", 3, 1)
+	assert.True(t, allowed)
+
+	now = now.Add(1500 * time.Millisecond)
+	tokens, allowed, _ = r.Take(t.Context(), "auth:ip:10.0.0.1", 3, 1)
+	assert.True(t, allowed)
+	assert.Equal(t, 0.5, tokens)
+
+	// Запас не превышает ёмкость корзины
+	now = now.Add(time.Hour)
+	tokens, _, _ = r.Take(t.Context(), "auth:ip:10.0.0.1", 3, 1)
+	assert.Equal(t, float64(2), tokens)
+}
+
+func TestMemoryRateLimit_DeleteIdle(t *testing.T) {
+	now := time.Date(2025, 12, 19, 9, 0, 0, 0, time.UTC)
+	r := NewMemoryRateLimitRepository()
+	r.now = func() time.Time { return now }
+
+	_, _, _ = r.Take(t.Context(), "old", 3, 1)
+	now = now.Add(time.Hour)
+	_, _, _ = r.Take(t.Context(), "fresh", 3, 1)
+
+	deleted, err := r.DeleteIdle(t.Context(), now.Add(-time.Minute))
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	assert.Len(t, r.buckets, 1)
+	assert.Contains(t, r.buckets, "fresh")
+}
@@ -3,8 +3,11 @@ package repository
 import (
 	"context"
 	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/models"
 	"gw-currency-wallet/pkg"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
@@ -15,22 +18,155 @@ type Wallet interface {
 	TxRepository
 	GetAllByEmail(ctx context.Context, email string) ([]db.AppWallet, error)
 	GetForUpdate(ctx context.Context, email string, currency pkg.Currency) (*db.AppWallet, error)
-	Update(ctx context.Context, email string, currency pkg.Currency, newValue float32) (*db.AppWallet, error)
+	Update(ctx context.Context, email string, currency pkg.Currency, newValue pkg.Amount) (*db.AppWallet, error)
 	IsExistCurrency(ctx context.Context, email string, currency pkg.Currency) (bool, error)
 	Create(ctx context.Context, email string, currency pkg.Currency) error
+	CreateHold(ctx context.Context, email string, currency pkg.Currency, amount pkg.Amount, expiresAt time.Time) (*db.AppHold, error)
+	GetHoldForUpdate(ctx context.Context, id, email string) (*db.AppHold, error)
+	GetHeldAmount(ctx context.Context, email string, currency pkg.Currency) (pkg.Amount, error)
+	GetHeldAmounts(ctx context.Context, email string) ([]db.GetHeldAmountsByEmailRow, error)
+	UpdateHoldStatus(ctx context.Context, id pgtype.UUID, status string, capturedAmount pkg.Amount) (*db.AppHold, error)
 }
 
 type Account interface {
 	TxRepository
-	IsEmailExist(ctx context.Context, email string) (bool, error)
-	IsUsernameExist(ctx context.Context, username string) (bool, error)
 	Create(ctx context.Context, email, username, passwordHash string) (*db.AppAccount, error)
 	GetByUsername(ctx context.Context, username string) (*db.AppAccount, error)
+	GetByEmail(ctx context.Context, email string) (*db.AppAccount, error)
+	// RehashPassword заменяет хеш, только если он всё ещё равен currentHash.
+	// Возвращает false, если пароль успели сменить.
+	RehashPassword(ctx context.Context, email, currentHash, newHash string) (bool, error)
+}
+
+type Ledger interface {
+	TxRepository
+	CreateTransaction(ctx context.Context, txType string) (*db.AppTransaction, error)
+	CreateEntry(ctx context.Context, transactionID int64, account string, currency pkg.Currency, direction string, amount pkg.Amount) error
+	ListByAccount(ctx context.Context, account string, filter models.TransactionFilter, cursor *models.TransactionCursor, limit int32) ([]db.AppTransaction, error)
+	GetEntriesByTransactions(ctx context.Context, account string, transactionIDs []int64) ([]db.AppLedgerEntry, error)
+}
+
+type Idempotency interface {
+	TxRepository
+	Claim(ctx context.Context, email, key, operation, requestHash string) (claimed bool, err error)
+	Get(ctx context.Context, email, key string) (*db.AppIdempotencyKey, error)
+	SaveResponse(ctx context.Context, email, key string, response []byte) error
+}
+
+type Currency interface {
+	TxRepository
+	Get(ctx context.Context, code pkg.Currency) (*db.AppCurrency, error)
+	List(ctx context.Context) ([]db.AppCurrency, error)
+}
+
+type Fee interface {
+	TxRepository
+	Get(ctx context.Context, from, to pkg.Currency) (*db.AppExchangeFee, error)
+}
+
+type Quote interface {
+	TxRepository
+	Create(ctx context.Context, email string, from, to pkg.Currency, amount pkg.Amount, rate pkg.Rate, receiveAmount, fee pkg.Amount, expiresAt time.Time) (*db.AppExchangeQuote, error)
+	GetForUpdate(ctx context.Context, id, email string) (*db.AppExchangeQuote, error)
+	MarkUsed(ctx context.Context, id pgtype.UUID) error
+}
+
+type Session interface {
+	TxRepository
+	Create(ctx context.Context, email string) (*db.AppSession, error)
+	Get(ctx context.Context, id string) (*db.AppSession, error)
+	Revoke(ctx context.Context, id pgtype.UUID) error
+	RevokeAll(ctx context.Context, email string) error
+	CreateRefreshToken(ctx context.Context, tokenHash string, sessionID pgtype.UUID, expiresAt time.Time) error
+	GetRefreshTokenForUpdate(ctx context.Context, tokenHash string) (*db.AppRefreshToken, error)
+	MarkRefreshTokenUsed(ctx context.Context, tokenHash string) error
+}
+
+type LoginAttempt interface {
+	TxRepository
+	Get(ctx context.Context, scope, subject string) (*db.AppLoginAttempt, error)
+	RecordFailure(ctx context.Context, scope, subject string, resetBefore time.Time) (*db.AppLoginAttempt, error)
+	SetLockedUntil(ctx context.Context, scope, subject string, lockedUntil time.Time, failures int32) error
+	Delete(ctx context.Context, scope, subject string) error
+	CreateLockoutEvent(ctx context.Context, scope, subject string, failures int32, lockedUntil time.Time) error
+	ListLockoutEvents(ctx context.Context, scope, subject string, limit int32) ([]db.AppLockoutEvent, error)
+	MarkUnlocked(ctx context.Context, scope, subject, unlockedBy string) error
+}
+
+type TwoFactor interface {
+	TxRepository
+	GetTOTP(ctx context.Context, email string) (*db.AppAccountTotp, error)
+	GetTOTPForUpdate(ctx context.Context, email string) (*db.AppAccountTotp, error)
+	SaveTOTPSecret(ctx context.Context, email, secret string) error
+	EnableTOTP(ctx context.Context, email string, step int64) error
+	UpdateTOTPLastUsedStep(ctx context.Context, email string, step int64) error
+	ReplaceRecoveryCodes(ctx context.Context, email string, codeHashes []string) error
+	UseRecoveryCode(ctx context.Context, email, codeHash string) (bool, error)
+	CreateChallenge(ctx context.Context, tokenHash, email string, expiresAt time.Time) error
+	GetChallengeForUpdate(ctx context.Context, tokenHash string) (*db.AppLoginChallenge, error)
+	IncrementChallengeAttempts(ctx context.Context, tokenHash string) error
+	MarkChallengeUsed(ctx context.Context, tokenHash string) error
+}
+
+type PasswordReset interface {
+	TxRepository
+	CreateToken(ctx context.Context, tokenHash, email string, expiresAt time.Time) error
+	GetTokenForUpdate(ctx context.Context, tokenHash string) (*db.AppPasswordResetToken, error)
+	UseTokens(ctx context.Context, email string) error
+	UpdatePassword(ctx context.Context, email, passwordHash string) error
+}
+
+type EmailVerification interface {
+	TxRepository
+	CreateToken(ctx context.Context, tokenHash, email string, expiresAt time.Time) error
+	GetTokenForUpdate(ctx context.Context, tokenHash string) (*db.AppEmailVerificationToken, error)
+	LastSentAt(ctx context.Context, email string) (*time.Time, error)
+	UseTokens(ctx context.Context, email string) error
+	MarkVerified(ctx context.Context, email string) error
+}
+
+type Admin interface {
+	TxRepository
+	SearchAccounts(ctx context.Context, query string, limit int) ([]db.AppAccount, error)
+	SetFrozen(ctx context.Context, email string, frozen bool) (bool, error)
+	SetRole(ctx context.Context, email, role string) (bool, error)
+	CreateAuditEntry(ctx context.Context, actor, action, subject, reason string, details []byte) error
+	ListAuditEntries(ctx context.Context, subject string, limit int) ([]db.AppAdminAuditLog, error)
+}
+
+type APIKey interface {
+	TxRepository
+	Create(ctx context.Context, email, name, prefix, keyHash string, scopes, allowedIPs []string, expiresAt *time.Time) (*db.AppApiKey, error)
+	GetByPrefix(ctx context.Context, prefix string) (*db.AppApiKey, error)
+	List(ctx context.Context, email string) ([]db.AppApiKey, error)
+	Revoke(ctx context.Context, email, id string) (bool, error)
+	Touch(ctx context.Context, id pgtype.UUID) error
+}
+
+// RateLimit хранит корзины ограничения частоты запросов (token bucket). Take пополняет
+// корзину key с момента прошлого запроса, но не больше capacity, и расходует из неё
+// один запрос, если запаса хватает. tokens — запас после запроса.
+type RateLimit interface {
+	Take(ctx context.Context, key string, capacity, refillRate float64) (tokens float64, allowed bool, err error)
+	DeleteIdle(ctx context.Context, before time.Time) (int64, error)
 }
 
 type Repository struct {
 	Wallet
 	Account
+	Ledger
+	Idempotency
+	Quote
+	Fee
+	Currency
+	Session
+	LoginAttempt
+	TwoFactor
+	PasswordReset
+	EmailVerification
+	Admin
+	APIKey
+	RateLimit
 }
 
 func NewRepository(pool *pgxpool.Pool) *Repository {
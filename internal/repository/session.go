@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/logger"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SessionRepository struct {
+	TxRepositoryImpl
+}
+
+func (r *SessionRepository) Create(ctx context.Context, email string) (*db.AppSession, error) {
+	q := r.getQueries(ctx)
+
+	row, err := q.CreateSession(ctx, email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return &row, nil
+}
+
+// Get возвращает nil, если сессия не найдена или id не является UUID
+func (r *SessionRepository) Get(ctx context.Context, id string) (*db.AppSession, error) {
+	q := r.getQueries(ctx)
+
+	var sessionID pgtype.UUID
+	if err := sessionID.Scan(id); err != nil {
+		return nil, nil
+	}
+
+	row, err := q.GetSession(ctx, sessionID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, nil
+		default:
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+	}
+
+	return &row, nil
+}
+
+func (r *SessionRepository) Revoke(ctx context.Context, id pgtype.UUID) error {
+	q := r.getQueries(ctx)
+
+	if err := q.RevokeSession(ctx, id); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) RevokeAll(ctx context.Context, email string) error {
+	q := r.getQueries(ctx)
+
+	if err := q.RevokeAccountSessions(ctx, email); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) CreateRefreshToken(ctx context.Context, tokenHash string, sessionID pgtype.UUID, expiresAt time.Time) error {
+	q := r.getQueries(ctx)
+
+	if err := q.CreateRefreshToken(ctx, db.CreateRefreshTokenParams{
+		TokenHash: tokenHash,
+		SessionID: sessionID,
+		ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	}); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// GetRefreshTokenForUpdate возвращает nil, если токен не найден
+func (r *SessionRepository) GetRefreshTokenForUpdate(ctx context.Context, tokenHash string) (*db.AppRefreshToken, error) {
+	q := r.getQueries(ctx)
+
+	row, err := q.GetRefreshTokenForUpdate(ctx, tokenHash)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, nil
+		default:
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+	}
+
+	return &row, nil
+}
+
+func (r *SessionRepository) MarkRefreshTokenUsed(ctx context.Context, tokenHash string) error {
+	q := r.getQueries(ctx)
+
+	if err := q.MarkRefreshTokenUsed(ctx, tokenHash); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func NewSessionRepository(pool *pgxpool.Pool, queries *db.Queries) *SessionRepository {
+	return &SessionRepository{
+		TxRepositoryImpl{
+			db: pool,
+			q:  queries,
+		},
+	}
+}
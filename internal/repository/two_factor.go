@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/logger"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TwoFactorRepository struct {
+	TxRepositoryImpl
+}
+
+// GetTOTP возвращает nil, если аккаунт не начинал подключение 2FA
+func (r *TwoFactorRepository) GetTOTP(ctx context.Context, email string) (*db.AppAccountTotp, error) {
+	q := r.getQueries(ctx)
+
+	row, err := q.GetAccountTOTP(ctx, email)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, nil
+		default:
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+	}
+
+	return &row, nil
+}
+
+// GetTOTPForUpdate возвращает nil, если аккаунт не начинал подключение 2FA
+func (r *TwoFactorRepository) GetTOTPForUpdate(ctx context.Context, email string) (*db.AppAccountTotp, error) {
+	q := r.getQueries(ctx)
+
+	row, err := q.GetAccountTOTPForUpdate(ctx, email)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, nil
+		default:
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+	}
+
+	return &row, nil
+}
+
+// SaveTOTPSecret сохраняет новый секрет. Секрет уже включённой 2FA не заменяется.
+func (r *TwoFactorRepository) SaveTOTPSecret(ctx context.Context, email, secret string) error {
+	q := r.getQueries(ctx)
+
+	if err := q.UpsertAccountTOTP(ctx, db.UpsertAccountTOTPParams{
+		Email:  email,
+		Secret: secret,
+	}); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (r *TwoFactorRepository) EnableTOTP(ctx context.Context, email string, step int64) error {
+	q := r.getQueries(ctx)
+
+	if err := q.EnableAccountTOTP(ctx, db.EnableAccountTOTPParams{
+		Email:        email,
+		LastUsedStep: step,
+	}); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (r *TwoFactorRepository) UpdateTOTPLastUsedStep(ctx context.Context, email string, step int64) error {
+	q := r.getQueries(ctx)
+
+	if err := q.UpdateTOTPLastUsedStep(ctx, db.UpdateTOTPLastUsedStepParams{
+		Email:        email,
+		LastUsedStep: step,
+	}); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// ReplaceRecoveryCodes удаляет прежние коды восстановления и сохраняет новые.
+// Вызывается внутри транзакции.
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, email string, codeHashes []string) error {
+	q := r.getQueries(ctx)
+
+	if err := q.DeleteRecoveryCodes(ctx, email); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	for _, codeHash := range codeHashes {
+		if err := q.CreateRecoveryCode(ctx, db.CreateRecoveryCodeParams{
+			Email:    email,
+			CodeHash: codeHash,
+		}); err != nil {
+			logger.L(ctx).Error(err.Error())
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UseRecoveryCode отмечает код использованным. Возвращает false, если кода нет или он уже использован.
+func (r *TwoFactorRepository) UseRecoveryCode(ctx context.Context, email, codeHash string) (bool, error) {
+	q := r.getQueries(ctx)
+
+	rows, err := q.UseRecoveryCode(ctx, db.UseRecoveryCodeParams{
+		Email:    email,
+		CodeHash: codeHash,
+	})
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return false, err
+	}
+
+	return rows > 0, nil
+}
+
+func (r *TwoFactorRepository) CreateChallenge(ctx context.Context, tokenHash, email string, expiresAt time.Time) error {
+	q := r.getQueries(ctx)
+
+	if err := q.CreateLoginChallenge(ctx, db.CreateLoginChallengeParams{
+		TokenHash: tokenHash,
+		Email:     email,
+		ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	}); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// GetChallengeForUpdate возвращает nil, если токен не найден
+func (r *TwoFactorRepository) GetChallengeForUpdate(ctx context.Context, tokenHash string) (*db.AppLoginChallenge, error) {
+	q := r.getQueries(ctx)
+
+	row, err := q.GetLoginChallengeForUpdate(ctx, tokenHash)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, nil
+		default:
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+	}
+
+	return &row, nil
+}
+
+func (r *TwoFactorRepository) IncrementChallengeAttempts(ctx context.Context, tokenHash string) error {
+	q := r.getQueries(ctx)
+
+	if err := q.IncrementLoginChallengeAttempts(ctx, tokenHash); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (r *TwoFactorRepository) MarkChallengeUsed(ctx context.Context, tokenHash string) error {
+	q := r.getQueries(ctx)
+
+	if err := q.MarkLoginChallengeUsed(ctx, tokenHash); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func NewTwoFactorRepository(pool *pgxpool.Pool, queries *db.Queries) *TwoFactorRepository {
+	return &TwoFactorRepository{
+		TxRepositoryImpl{
+			db: pool,
+			q:  queries,
+		},
+	}
+}
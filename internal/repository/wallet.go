@@ -4,11 +4,11 @@ import (
 	"context"
 	"errors"
 	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/logger"
 	"gw-currency-wallet/pkg"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"go.uber.org/zap"
 )
 
 type WalletRepository struct {
@@ -23,7 +23,7 @@ func (r *WalletRepository) IsExistCurrency(ctx context.Context, email string, cu
 		Currency: currency,
 	})
 	if err != nil {
-		zap.L().Error(err.Error())
+		logger.L(ctx).Error(err.Error())
 		return false, err
 	}
 
@@ -37,7 +37,7 @@ func (r *WalletRepository) Create(ctx context.Context, email string, currency pk
 		Email:    email,
 		Currency: currency,
 	}); err != nil {
-		zap.L().Error(err.Error())
+		logger.L(ctx).Error(err.Error())
 		return err
 	}
 
@@ -56,7 +56,7 @@ func (r *WalletRepository) GetForUpdate(ctx context.Context, email string, curre
 		case errors.Is(err, pgx.ErrNoRows):
 			return nil, nil
 		default:
-			zap.L().Error(err.Error())
+			logger.L(ctx).Error(err.Error())
 			return nil, err
 		}
 	}
@@ -64,7 +64,7 @@ func (r *WalletRepository) GetForUpdate(ctx context.Context, email string, curre
 	return &row, nil
 }
 
-func (r *WalletRepository) Update(ctx context.Context, email string, currency pkg.Currency, newValue float32) (*db.AppWallet, error) {
+func (r *WalletRepository) Update(ctx context.Context, email string, currency pkg.Currency, newValue pkg.Amount) (*db.AppWallet, error) {
 	q := r.getQueries(ctx)
 
 	row, err := q.UpdateWallet(ctx, db.UpdateWalletParams{
@@ -73,7 +73,7 @@ func (r *WalletRepository) Update(ctx context.Context, email string, currency pk
 		Balance:  newValue,
 	})
 	if err != nil {
-		zap.L().Error(err.Error())
+		logger.L(ctx).Error(err.Error())
 		return nil, err
 	}
 
@@ -89,7 +89,7 @@ func (r *WalletRepository) GetAllByEmail(ctx context.Context, email string) ([]d
 		case errors.Is(err, pgx.ErrNoRows):
 			return nil, nil
 		default:
-			zap.L().Error(err.Error())
+			logger.L(ctx).Error(err.Error())
 			return nil, err
 		}
 	}
@@ -0,0 +1,52 @@
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"regexp"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	Header      = "X-Request-ID"
+	MetadataKey = "x-request-id"
+)
+
+// validID ограничивает ID безопасными символами и длиной application_name в Postgres (63 байта)
+var validID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,63}$`)
+
+type ctxKey struct{}
+
+// New генерирует случайный ID из 32 шестнадцатеричных символов
+func New() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// IsValid проверяет ID, присланный клиентом. Недопустимый ID заменяется новым.
+func IsValid(id string) bool {
+	return validID.MatchString(id)
+}
+
+func With(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// From возвращает ID запроса или пустую строку, если его нет в контексте
+func From(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// UnaryClientInterceptor передаёт ID запроса в метаданных исходящих gRPC-вызовов
+func UnaryClientInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if id := From(ctx); id != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, MetadataKey, id)
+	}
+
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
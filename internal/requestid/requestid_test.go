@@ -0,0 +1,32 @@
+package requestid
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestIsValid(t *testing.T) {
+	assert.True(t, IsValid("3f2b8c1e-7a4d-4e0b-9c1a-2d5f6e7a8b9c"))
+	assert.True(t, IsValid(New()))
+	assert.False(t, IsValid(""))
+	assert.False(t, IsValid("abc */ DROP TABLE"))
+	assert.False(t, IsValid(string(make([]byte, 64))))
+}
+
+func TestUnaryClientInterceptor_AddsMetadata(t *testing.T) {
+	ctx := With(t.Context(), "req-1")
+
+	var got []string
+	invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		got = md.Get(MetadataKey)
+		return nil
+	}
+
+	assert.NoError(t, UnaryClientInterceptor(ctx, "/exchange.ExchangeService/GetExchangeRates", nil, nil, nil, invoker))
+	assert.Equal(t, []string{"req-1"}, got)
+}
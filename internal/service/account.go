@@ -2,81 +2,170 @@ package service
 
 import (
 	"context"
+	"errors"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/logger"
 	"gw-currency-wallet/internal/models"
 	"gw-currency-wallet/internal/repository"
-
-	"go.uber.org/zap"
+	"strings"
+	"sync"
 )
 
+// dummyPassword пароль, хеш которого сравнивается при входе по несуществующему логину
+const dummyPassword = "gw-currency-wallet-dummy-password"
+
 type AccountService struct {
 	r repository.Account
 	s *Service
+
+	dummyHashOnce sync.Once
+	dummyHash     string
 }
 
-func (s *AccountService) Login(ctx context.Context, username, password string) (string, error) {
-	account, err := s.r.GetByUsername(ctx, username)
+// Login принимает имя пользователя или email без учёта регистра. Неудачные попытки
+// учитываются по аккаунту и по IP-адресу клиента, при их превышении возвращается *LoginThrottledError.
+// Если у аккаунта включена двухфакторная аутентификация, вместо токенов возвращается
+// Challenge, который обменивается на токены в TwoFactor.Verify. Хеш пароля устаревшего
+// алгоритма или с прежними параметрами пересчитывается после успешной проверки.
+func (s *AccountService) Login(ctx context.Context, login, password, clientIP string) (*models.LoginResult, error) {
+	account, err := s.Find(ctx, login)
 	if err != nil {
-		zap.L().Error(err.Error())
-		return "", err
+		logger.L(ctx).Error(err.Error())
+		return nil, err
 	}
 
-	if account == nil {
-		zap.L().Warn("invalid username")
-		return "", ErrInvalidCredentials
+	// Попытки для несуществующих логинов тоже считаются, чтобы по ответу нельзя было
+	// отличить существующий аккаунт
+	subject := strings.ToLower(strings.TrimSpace(login))
+	if account != nil {
+		subject = strings.ToLower(account.Email)
 	}
 
-	if err = s.s.Auth.ComparePassword(account.Password, password); err != nil {
-		zap.L().Warn("invalid password")
-		return "", ErrInvalidCredentials
+	if err = s.s.LoginGuard.Check(ctx, subject, clientIP); err != nil {
+		return nil, err
 	}
 
-	token, err := s.s.Auth.GenerateJWT(account.Email)
-	if err != nil {
-		zap.L().Error(err.Error())
-		return "", err
+	// Пароль проверяется и для несуществующего логина, чтобы наличие аккаунта
+	// не выдавало время ответа
+	passwordHash := s.dummyPasswordHash(ctx)
+	if account != nil {
+		passwordHash = account.PasswordHash
 	}
 
-	return token, nil
-}
+	if s.s.Auth.ComparePassword(passwordHash, password) != nil || account == nil {
+		logger.L(ctx).Warn(ErrInvalidCredentials.Error())
 
-func (s *AccountService) Register(ctx context.Context, email, username, password string) (*models.Account, error) {
-	passwordHash, err := s.s.Auth.HashPassword(password)
+		if err = s.s.LoginGuard.Fail(ctx, subject, clientIP); err != nil {
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+
+		return nil, ErrInvalidCredentials
+	}
+
+	s.rehashPassword(ctx, account, password)
+
+	enabled, err := s.s.TwoFactor.IsEnabled(ctx, account.Email)
 	if err != nil {
-		zap.L().Error(err.Error())
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	// С 2FA счётчик аккаунта сбрасывается только после второго шага
+	if enabled {
+		challenge, err := s.s.TwoFactor.Challenge(ctx, account.Email)
+		if err != nil {
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+
+		return &models.LoginResult{Challenge: challenge}, nil
+	}
+
+	if err = s.s.LoginGuard.Succeed(ctx, subject); err != nil {
+		logger.L(ctx).Error(err.Error())
 		return nil, err
 	}
 
-	isExist, err := s.r.IsUsernameExist(ctx, username)
+	tokens, err := s.s.Session.Start(ctx, account.Email)
 	if err != nil {
-		zap.L().Error(err.Error())
+		logger.L(ctx).Error(err.Error())
 		return nil, err
 	}
-	if isExist {
-		zap.L().Warn(ErrUsernameAlreadyExists.Error())
-		return nil, ErrUsernameAlreadyExists
+
+	return &models.LoginResult{Tokens: tokens}, nil
+}
+
+// Register проверяет пароль по политике паролей, создаёт аккаунт и отправляет письмо для подтверждения email. Уникальность имени
+// пользователя и email без учёта регистра гарантирует база данных, поэтому параллельные
+// регистрации не создают дубликатов.
+func (s *AccountService) Register(ctx context.Context, email, username, password string) (*models.Account, error) {
+	email = strings.TrimSpace(email)
+	username = strings.TrimSpace(username)
+
+	if err := s.s.Auth.ValidatePassword(password, username); err != nil {
+		logger.L(ctx).Warn(err.Error())
+		return nil, err
 	}
 
-	isExist, err = s.r.IsEmailExist(ctx, username)
+	passwordHash, err := s.s.Auth.HashPassword(password)
 	if err != nil {
-		zap.L().Error(err.Error())
+		logger.L(ctx).Error(err.Error())
 		return nil, err
 	}
-	if isExist {
-		zap.L().Warn(ErrEmailAlreadyExists.Error())
-		return nil, ErrEmailAlreadyExists
-	}
 
 	dbAccount, err := s.r.Create(ctx, email, username, passwordHash)
 	if err != nil {
-		zap.L().Error(err.Error())
-		return nil, err
+		switch {
+		case errors.Is(err, repository.ErrUsernameTaken):
+			logger.L(ctx).Warn(ErrUsernameAlreadyExists.Error())
+			return nil, ErrUsernameAlreadyExists
+		case errors.Is(err, repository.ErrEmailTaken):
+			logger.L(ctx).Warn(ErrEmailAlreadyExists.Error())
+			return nil, ErrEmailAlreadyExists
+		default:
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
 	}
 
-	return &models.Account{
-		Email:        dbAccount.Email,
-		PasswordHash: passwordHash,
-		Username:     dbAccount.Username,
-	}, err
+	// Аккаунт уже создан: если письмо не ушло, его можно запросить повторно
+	if err = s.s.EmailVerification.Send(ctx, dbAccount.Email); err != nil {
+		logger.L(ctx).Error(err.Error())
+	}
+
+	return toAccount(dbAccount), nil
+}
+
+// Find ищет аккаунт по email или имени пользователя без учёта регистра. Строка с "@"
+// сначала ищется как email, затем как имя пользователя. Возвращает nil, если аккаунт не найден.
+func (s *AccountService) Find(ctx context.Context, usernameOrEmail string) (*models.Account, error) {
+	var account *db.AppAccount
+	var err error
+
+	usernameOrEmail = strings.TrimSpace(usernameOrEmail)
+
+	if strings.Contains(usernameOrEmail, "@") {
+		account, err = s.r.GetByEmail(ctx, usernameOrEmail)
+		if err != nil {
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+	}
+
+	if account == nil {
+		account, err = s.r.GetByUsername(ctx, usernameOrEmail)
+		if err != nil {
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+	}
+
+	if account == nil {
+		return nil, nil
+	}
+
+	return toAccount(account), nil
 }
 
 func NewAccountService(repo repository.Account, srv *Service) *AccountService {
@@ -85,3 +174,55 @@ func NewAccountService(repo repository.Account, srv *Service) *AccountService {
 		r: repo,
 	}
 }
+
+// dummyPasswordHash хеш dummyPassword основным алгоритмом с текущими параметрами,
+// поэтому его проверка стоит столько же, сколько проверка пароля существующего аккаунта
+func (s *AccountService) dummyPasswordHash(ctx context.Context) string {
+	s.dummyHashOnce.Do(func() {
+		hash, err := s.s.Auth.HashPassword(dummyPassword)
+		if err != nil {
+			logger.L(ctx).Error(err.Error())
+			return
+		}
+		s.dummyHash = hash
+	})
+
+	return s.dummyHash
+}
+
+// rehashPassword пересчитывает хеш, пока известен пароль. Ошибка не мешает входу:
+// хеш будет пересчитан при следующем входе.
+func (s *AccountService) rehashPassword(ctx context.Context, account *models.Account, password string) {
+	if !s.s.Auth.NeedsRehash(account.PasswordHash) {
+		return
+	}
+
+	passwordHash, err := s.s.Auth.HashPassword(password)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return
+	}
+
+	// Хеш заменяется, только если пароль не сменили после его чтения: иначе
+	// сброс пароля, завершившийся во время входа, был бы перезаписан старым паролем
+	updated, err := s.r.RehashPassword(ctx, account.Email, account.PasswordHash, passwordHash)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return
+	}
+
+	if !updated {
+		logger.L(ctx).Info("password changed concurrently, rehash skipped")
+	}
+}
+
+func toAccount(account *db.AppAccount) *models.Account {
+	return &models.Account{
+		Email:        account.Email,
+		PasswordHash: account.Password,
+		Username:     account.Username,
+		Verified:     account.VerifiedAt.Valid,
+		Role:         account.Role,
+		Frozen:       account.FrozenAt.Valid,
+	}
+}
@@ -1,9 +1,9 @@
 package service
 
-import "errors"
+import "net/http"
 
 var (
-	ErrUsernameAlreadyExists = errors.New("username already exists")
-	ErrEmailAlreadyExists    = errors.New("email already exists")
-	ErrInvalidCredentials    = errors.New("invalid username or password")
+	ErrUsernameAlreadyExists = newError("username_taken", http.StatusBadRequest, "username already exists")
+	ErrEmailAlreadyExists    = newError("email_taken", http.StatusBadRequest, "email already exists")
+	ErrInvalidCredentials    = newError("invalid_credentials", http.StatusBadRequest, "invalid username or password")
 )
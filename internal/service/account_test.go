@@ -0,0 +1,247 @@
+package service
+
+import (
+	"gw-currency-wallet/config"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/repository"
+	mock_repository "gw-currency-wallet/internal/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestRegister_UsernameTaken_ReturnsUsernameExists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockAccount(ctrl)
+	srv := NewAccountService(mockRepo, newTestService())
+
+	mockRepo.EXPECT().Create(t.Context(), "alice@example.com", "Alice", gomock.Any()).Return(nil, repository.ErrUsernameTaken)
+
+	_, err := srv.Register(t.Context(), "alice@example.com", "Alice", "correct-horse-42")
+
+	assert.ErrorIs(t, err, ErrUsernameAlreadyExists)
+}
+
+func TestRegister_EmailTaken_ReturnsEmailExists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockAccount(ctrl)
+	srv := NewAccountService(mockRepo, newTestService())
+
+	mockRepo.EXPECT().Create(t.Context(), "Alice@Example.com", "alice", gomock.Any()).Return(nil, repository.ErrEmailTaken)
+
+	_, err := srv.Register(t.Context(), " Alice@Example.com ", "alice", "correct-horse-42")
+
+	assert.ErrorIs(t, err, ErrEmailAlreadyExists)
+}
+
+func TestFind_EmailNotFound_FallsBackToUsername(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockAccount(ctrl)
+	srv := NewAccountService(mockRepo, newTestService())
+
+	mockRepo.EXPECT().GetByEmail(t.Context(), "bob@home").Return(nil, nil)
+	mockRepo.EXPECT().GetByUsername(t.Context(), "bob@home").Return(&db.AppAccount{
+		Email:    "bob@example.com",
+		Username: "bob@home",
+	}, nil)
+
+	account, err := srv.Find(t.Context(), "bob@home")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "bob@example.com", account.Email)
+}
+
+func TestLogin_WrongPassword_RecordsFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockAccount(ctrl)
+	srv := NewAccountService(mockRepo, newTestService())
+	mockAttempts := mock_repository.NewMockLoginAttempt(ctrl)
+	srv.s.LoginGuard = NewLoginGuardService(mockAttempts, &config.AuthConfig{})
+
+	hash, err := srv.s.Auth.HashPassword("password123")
+	assert.NoError(t, err)
+
+	mockRepo.EXPECT().GetByEmail(t.Context(), "ALICE@example.com").Return(&db.AppAccount{
+		Email:    "Alice@example.com",
+		Username: "alice",
+		Password: hash,
+	}, nil)
+
+	// Попытки считаются по нормализованному email аккаунта, а не по введённой строке
+	mockAttempts.EXPECT().Get(t.Context(), models.LoginScopeAccount, "alice@example.com").Return(nil, nil)
+	mockAttempts.EXPECT().Get(t.Context(), models.LoginScopeIP, "10.0.0.1").Return(nil, nil)
+
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+	mockTx.EXPECT().Commit(gomock.Any()).Return(nil)
+	mockAttempts.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+	mockAttempts.EXPECT().RecordFailure(gomock.Any(), models.LoginScopeAccount, "alice@example.com", gomock.Any()).
+		Return(&db.AppLoginAttempt{Scope: models.LoginScopeAccount, Subject: "alice@example.com", Failures: 1}, nil)
+	mockAttempts.EXPECT().SetLockedUntil(gomock.Any(), models.LoginScopeAccount, "alice@example.com", gomock.Any(), int32(1)).Return(nil)
+	mockAttempts.EXPECT().RecordFailure(gomock.Any(), models.LoginScopeIP, "10.0.0.1", gomock.Any()).
+		Return(&db.AppLoginAttempt{Scope: models.LoginScopeIP, Subject: "10.0.0.1", Failures: 1}, nil)
+	mockAttempts.EXPECT().SetLockedUntil(gomock.Any(), models.LoginScopeIP, "10.0.0.1", gomock.Any(), int32(1)).Return(nil)
+
+	_, err = srv.Login(t.Context(), "ALICE@example.com", "wrong", "10.0.0.1")
+
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+// comparingAuth запоминает хеши, с которыми сравнивался пароль
+type comparingAuth struct {
+	Auth
+	compared []string
+}
+
+func (a *comparingAuth) ComparePassword(hashedPassword, password string) error {
+	a.compared = append(a.compared, hashedPassword)
+	return a.Auth.ComparePassword(hashedPassword, password)
+}
+
+func TestLogin_UnknownLogin_ComparesDummyHash(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockAccount(ctrl)
+	s := newTestService()
+	auth := &comparingAuth{Auth: s.Auth}
+	s.Auth = auth
+	srv := NewAccountService(mockRepo, s)
+	mockAttempts := mock_repository.NewMockLoginAttempt(ctrl)
+	s.LoginGuard = NewLoginGuardService(mockAttempts, &config.AuthConfig{})
+
+	mockRepo.EXPECT().GetByEmail(t.Context(), "ghost@example.com").Return(nil, nil)
+	mockRepo.EXPECT().GetByUsername(t.Context(), "ghost@example.com").Return(nil, nil)
+
+	mockAttempts.EXPECT().Get(t.Context(), models.LoginScopeAccount, "ghost@example.com").Return(nil, nil)
+	mockAttempts.EXPECT().Get(t.Context(), models.LoginScopeIP, "10.0.0.1").Return(nil, nil)
+
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+	mockTx.EXPECT().Commit(gomock.Any()).Return(nil)
+	mockAttempts.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+	mockAttempts.EXPECT().RecordFailure(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(&db.AppLoginAttempt{Failures: 1}, nil).Times(2)
+	mockAttempts.EXPECT().SetLockedUntil(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), int32(1)).Return(nil).Times(2)
+
+	_, err := srv.Login(t.Context(), "ghost@example.com", "password123", "10.0.0.1")
+
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	// пароль проверен с хешем основного алгоритма, как для существующего аккаунта
+	assert.Len(t, auth.compared, 1)
+	assert.False(t, s.Auth.NeedsRehash(auth.compared[0]))
+}
+
+func TestRegister_WeakPassword_ReturnsPolicyError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := NewAccountService(mock_repository.NewMockAccount(ctrl), newTestService())
+
+	tests := []struct {
+		password string
+		err      error
+	}{
+		{"short1", ErrPasswordTooShort},
+		{"Password123", ErrPasswordBreached},
+		{"my-Alice-wallet", ErrPasswordContainsUsername},
+	}
+
+	for _, tt := range tests {
+		_, err := srv.Register(t.Context(), "alice@example.com", "alice", tt.password)
+		assert.ErrorIs(t, err, tt.err, tt.password)
+	}
+}
+
+func TestLogin_LegacyBcryptHash_IsUpgraded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockAccount(ctrl)
+	srv := NewAccountService(mockRepo, newTestService())
+	mockAttempts := mock_repository.NewMockLoginAttempt(ctrl)
+	mockTwoFactor := mock_repository.NewMockTwoFactor(ctrl)
+	srv.s.LoginGuard = NewLoginGuardService(mockAttempts, &config.AuthConfig{})
+	srv.s.TwoFactor = NewTwoFactorService(mockTwoFactor, srv.s)
+
+	legacy, err := bcrypt.GenerateFromPassword([]byte("correct-horse-42"), bcrypt.MinCost)
+	assert.NoError(t, err)
+
+	mockRepo.EXPECT().GetByEmail(t.Context(), "alice@example.com").Return(&db.AppAccount{
+		Email:    "alice@example.com",
+		Username: "alice",
+		Password: string(legacy),
+	}, nil)
+	mockAttempts.EXPECT().Get(t.Context(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+	// Счётчик аккаунта не сбрасывается до второго шага: Delete не ожидается
+	mockRepo.EXPECT().RehashPassword(t.Context(), "alice@example.com", string(legacy), gomock.Any()).DoAndReturn(
+		func(_ any, _, _, passwordHash string) (bool, error) {
+			assert.Regexp(t, `^\$argon2id\$`, passwordHash)
+			assert.NoError(t, srv.s.Auth.ComparePassword(passwordHash, "correct-horse-42"))
+			return true, nil
+		})
+	// Вход завершается вторым шагом, чтобы не создавать сессию в тесте
+	mockTwoFactor.EXPECT().GetTOTP(t.Context(), "alice@example.com").Return(&db.AppAccountTotp{
+		Email:     "alice@example.com",
+		EnabledAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}, nil)
+	mockTwoFactor.EXPECT().CreateChallenge(t.Context(), gomock.Any(), "alice@example.com", gomock.Any()).Return(nil)
+
+	result, err := srv.Login(t.Context(), "alice@example.com", "correct-horse-42", "10.0.0.1")
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result.Challenge)
+}
+
+func TestLogin_WithoutTwoFactor_ResetsAccountFailures(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockAccount(ctrl)
+	srv := NewAccountService(mockRepo, newTestService())
+	mockAttempts := mock_repository.NewMockLoginAttempt(ctrl)
+	mockTwoFactor := mock_repository.NewMockTwoFactor(ctrl)
+	mockSession := mock_repository.NewMockSession(ctrl)
+	srv.s.LoginGuard = NewLoginGuardService(mockAttempts, &config.AuthConfig{})
+	srv.s.TwoFactor = NewTwoFactorService(mockTwoFactor, srv.s)
+	srv.s.Session = NewSessionService(mockSession, srv.s)
+	srv.s.Account = srv
+
+	hash, err := srv.s.Auth.HashPassword("correct-horse-42")
+	assert.NoError(t, err)
+
+	mockRepo.EXPECT().GetByEmail(t.Context(), "alice@example.com").Return(&db.AppAccount{
+		Email:    "alice@example.com",
+		Username: "alice",
+		Password: hash,
+	}, nil).Times(2)
+	mockAttempts.EXPECT().Get(t.Context(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+	mockTwoFactor.EXPECT().GetTOTP(t.Context(), "alice@example.com").Return(nil, nil)
+	mockAttempts.EXPECT().Delete(t.Context(), models.LoginScopeAccount, "alice@example.com").Return(nil)
+
+	sessionTx := mock_repository.NewMockTx(ctrl)
+	sessionTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+	sessionTx.EXPECT().Commit(gomock.Any()).Return(nil)
+	mockSession.EXPECT().WithTx(gomock.Any()).Return(t.Context(), sessionTx, nil)
+	mockSession.EXPECT().Create(gomock.Any(), "alice@example.com").Return(testSession(t), nil)
+	mockSession.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	result, err := srv.Login(t.Context(), "alice@example.com", "correct-horse-42", "10.0.0.1")
+
+	assert.NoError(t, err)
+	assert.NotNil(t, result.Tokens)
+}
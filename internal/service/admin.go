@@ -0,0 +1,387 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"gw-currency-wallet/internal/logger"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/repository"
+	"gw-currency-wallet/pkg"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultAdminPageSize = 20
+	MaxAdminPageSize     = 100
+)
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// adjustmentRequest и adjustmentResult — параметры и результат корректировки
+// для ключа идемпотентности
+type adjustmentRequest struct {
+	Email    string       `json:"email"`
+	Currency pkg.Currency `json:"currency"`
+	Amount   pkg.Amount   `json:"amount"`
+	Reason   string       `json:"reason"`
+}
+
+type adjustmentResult struct {
+	TransactionID int64              `json:"transaction_id"`
+	Wallets       pkg.AccountWallets `json:"wallets"`
+}
+
+// AdminService операции поддержки и администраторов. Каждое изменяющее действие
+// записывается в журнал вместе с обязательной причиной в той же транзакции.
+type AdminService struct {
+	r repository.Admin
+	s *Service
+}
+
+func (s *AdminService) SearchAccounts(ctx context.Context, query string, limit int) ([]models.Account, error) {
+	rows, err := s.r.SearchAccounts(ctx, likeEscaper.Replace(strings.TrimSpace(query)), pageSize(limit))
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	accounts := make([]models.Account, 0, len(rows))
+	for _, row := range rows {
+		accounts = append(accounts, *toAccount(&row))
+	}
+
+	return accounts, nil
+}
+
+func (s *AdminService) GetAccount(ctx context.Context, email string) (*models.Account, error) {
+	account, err := s.s.Account.Find(ctx, email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	if account == nil || !strings.EqualFold(account.Email, email) {
+		logger.L(ctx).Warn(ErrAccountNotFound.Error(), zap.String("email", email))
+		return nil, ErrAccountNotFound
+	}
+
+	return account, nil
+}
+
+func (s *AdminService) Balances(ctx context.Context, email string) (map[pkg.Currency]models.WalletBalance, error) {
+	account, err := s.GetAccount(ctx, email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	balances, err := s.s.Wallet.GetBalances(ctx, account.Email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return balances, nil
+}
+
+// Freeze запрещает аккаунту операции со средствами. Вход и просмотр баланса остаются доступны.
+func (s *AdminService) Freeze(ctx context.Context, actor, email, reason string) error {
+	return s.setFrozen(ctx, actor, email, reason, true)
+}
+
+func (s *AdminService) Unfreeze(ctx context.Context, actor, email, reason string) error {
+	return s.setFrozen(ctx, actor, email, reason, false)
+}
+
+// SetRole меняет роль аккаунта и завершает его сессии, чтобы новая роль
+// действовала сразу, а не после истечения выданных токенов
+func (s *AdminService) SetRole(ctx context.Context, actor, email string, role models.Role, reason string) error {
+	if !models.IsValidRole(role) {
+		logger.L(ctx).Warn(ErrInvalidRole.Error(), zap.String("role", role))
+		return ErrInvalidRole
+	}
+
+	if strings.TrimSpace(reason) == "" {
+		logger.L(ctx).Warn(ErrReasonRequired.Error())
+		return ErrReasonRequired
+	}
+
+	account, err := s.GetAccount(ctx, email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	if account.Email == actor {
+		logger.L(ctx).Warn(ErrCannotChangeOwnRole.Error(), zap.String("actor", actor))
+		return ErrCannotChangeOwnRole
+	}
+
+	c, tx, err := s.r.WithTx(ctx)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			logger.L(ctx).Error(err.Error())
+		}
+	}()
+
+	if _, err = s.r.SetRole(c, account.Email, role); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	if err = s.s.Session.RevokeAll(c, account.Email); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	if err = s.audit(c, actor, models.AuditSetRole, account.Email, reason, map[string]any{
+		"from": account.Role,
+		"to":   role,
+	}); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	if err = tx.Commit(c); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// AdjustBalance вносит ручную корректировку баланса: положительная сумма зачисляется,
+// отрицательная списывается. Ключ идемпотентности относится к администратору.
+func (s *AdminService) AdjustBalance(ctx context.Context, actor, email, idempotencyKey string, currency pkg.Currency, amount pkg.Amount, reason string) (int64, pkg.AccountWallets, error) {
+	if strings.TrimSpace(reason) == "" {
+		logger.L(ctx).Warn(ErrReasonRequired.Error())
+		return 0, nil, ErrReasonRequired
+	}
+
+	account, err := s.GetAccount(ctx, email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return 0, nil, err
+	}
+
+	c, tx, err := s.r.WithTx(ctx)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return 0, nil, err
+	}
+
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			logger.L(ctx).Error(err.Error())
+		}
+	}()
+
+	request := adjustmentRequest{Email: account.Email, Currency: currency, Amount: amount, Reason: reason}
+	result, err := idempotent(c, s.s.Idempotency, actor, idempotencyKey, models.TransactionAdjustment, request, func() (adjustmentResult, error) {
+		transactionID, wallets, err := s.s.Wallet.Adjust(c, account.Email, currency, amount)
+		if err != nil {
+			logger.L(ctx).Error(err.Error())
+			return adjustmentResult{}, err
+		}
+
+		if err = s.audit(c, actor, models.AuditAdjustment, account.Email, reason, map[string]any{
+			"currency":       currency,
+			"amount":         amount,
+			"transaction_id": transactionID,
+		}); err != nil {
+			logger.L(ctx).Error(err.Error())
+			return adjustmentResult{}, err
+		}
+
+		return adjustmentResult{TransactionID: transactionID, Wallets: wallets}, nil
+	})
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return 0, nil, err
+	}
+
+	if err = tx.Commit(c); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return 0, nil, err
+	}
+
+	return result.TransactionID, result.Wallets, nil
+}
+
+// UnlockLogin снимает блокировку входа по аккаунту или IP-адресу
+func (s *AdminService) UnlockLogin(ctx context.Context, actor string, scope models.LoginScope, subject, reason string) error {
+	if scope != models.LoginScopeAccount && scope != models.LoginScopeIP {
+		logger.L(ctx).Warn(ErrInvalidLoginScope.Error(), zap.String("scope", scope))
+		return ErrInvalidLoginScope
+	}
+
+	if strings.TrimSpace(reason) == "" {
+		logger.L(ctx).Warn(ErrReasonRequired.Error())
+		return ErrReasonRequired
+	}
+
+	// Попытки входа по аккаунту считаются по email в нижнем регистре
+	subject = strings.TrimSpace(subject)
+	if scope == models.LoginScopeAccount {
+		subject = strings.ToLower(subject)
+	}
+
+	c, tx, err := s.r.WithTx(ctx)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			logger.L(ctx).Error(err.Error())
+		}
+	}()
+
+	if err = s.s.LoginGuard.Unlock(c, scope, subject, actor); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	if err = s.audit(c, actor, models.AuditUnlock, subject, reason, map[string]any{
+		"scope": scope,
+	}); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	if err = tx.Commit(c); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	logger.L(ctx).Info("login unlocked", zap.String("scope", scope), zap.String("subject", subject), zap.String("by", actor))
+
+	return nil
+}
+
+func (s *AdminService) LoginLockouts(ctx context.Context, scope models.LoginScope, subject string) ([]models.LockoutEvent, error) {
+	if scope != models.LoginScopeAccount && scope != models.LoginScopeIP {
+		logger.L(ctx).Warn(ErrInvalidLoginScope.Error(), zap.String("scope", scope))
+		return nil, ErrInvalidLoginScope
+	}
+
+	subject = strings.TrimSpace(subject)
+	if scope == models.LoginScopeAccount {
+		subject = strings.ToLower(subject)
+	}
+
+	return s.s.LoginGuard.Lockouts(ctx, scope, subject)
+}
+
+// AuditLog записи журнала от новых к старым. Пустой subject — записи по всем аккаунтам.
+func (s *AdminService) AuditLog(ctx context.Context, subject string, limit int) ([]models.AuditEntry, error) {
+	rows, err := s.r.ListAuditEntries(ctx, strings.TrimSpace(subject), pageSize(limit))
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	entries := make([]models.AuditEntry, 0, len(rows))
+	for _, row := range rows {
+		entry := models.AuditEntry{
+			ID:        row.ID,
+			Actor:     row.Actor,
+			Action:    row.Action,
+			Subject:   row.Subject,
+			Reason:    row.Reason,
+			CreatedAt: row.CreatedAt.Time,
+		}
+		if len(row.Details) > 0 {
+			if err = json.Unmarshal(row.Details, &entry.Details); err != nil {
+				logger.L(ctx).Error(err.Error())
+				return nil, err
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func NewAdminService(r repository.Admin, s *Service) *AdminService {
+	return &AdminService{
+		r: r,
+		s: s,
+	}
+}
+
+func (s *AdminService) setFrozen(ctx context.Context, actor, email, reason string, frozen bool) error {
+	if strings.TrimSpace(reason) == "" {
+		logger.L(ctx).Warn(ErrReasonRequired.Error())
+		return ErrReasonRequired
+	}
+
+	account, err := s.GetAccount(ctx, email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	c, tx, err := s.r.WithTx(ctx)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			logger.L(ctx).Error(err.Error())
+		}
+	}()
+
+	if _, err = s.r.SetFrozen(c, account.Email, frozen); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	action := models.AuditFreeze
+	if !frozen {
+		action = models.AuditUnfreeze
+	}
+
+	if err = s.audit(c, actor, action, account.Email, reason, nil); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	if err = tx.Commit(c); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (s *AdminService) audit(ctx context.Context, actor string, action models.AuditAction, subject, reason string, details map[string]any) error {
+	var data []byte
+	if details != nil {
+		var err error
+		if data, err = json.Marshal(details); err != nil {
+			return err
+		}
+	}
+
+	return s.r.CreateAuditEntry(ctx, actor, action, subject, strings.TrimSpace(reason), data)
+}
+
+func pageSize(limit int) int {
+	if limit <= 0 {
+		return DefaultAdminPageSize
+	}
+	return min(limit, MaxAdminPageSize)
+}
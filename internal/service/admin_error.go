@@ -0,0 +1,13 @@
+package service
+
+import "net/http"
+
+var (
+	ErrAccountNotFound     = newError("account_not_found", http.StatusNotFound, "account not found")
+	ErrAccountFrozen       = newError("account_frozen", http.StatusForbidden, "account is frozen")
+	ErrPermissionDenied    = newError("permission_denied", http.StatusForbidden, "permission denied")
+	ErrReasonRequired      = newError("reason_required", http.StatusBadRequest, "reason is required")
+	ErrInvalidRole         = newError("invalid_role", http.StatusBadRequest, "invalid role")
+	ErrCannotChangeOwnRole = newError("own_role_change", http.StatusBadRequest, "cannot change your own role")
+	ErrInvalidLoginScope   = newError("invalid_login_scope", http.StatusBadRequest, "invalid login scope")
+)
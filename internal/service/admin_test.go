@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gw-currency-wallet/config"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/models"
+	mock_repository "gw-currency-wallet/internal/repository/mocks"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const testAdmin = "admin@example.com"
+
+func expectAdminTx(t *testing.T, ctrl *gomock.Controller, mockRepo *mock_repository.MockAdmin) {
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+	mockTx.EXPECT().Commit(gomock.Any()).Return(nil)
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+}
+
+func TestHasPermission(t *testing.T) {
+	assert.False(t, models.HasPermission(models.RoleUser, models.PermissionAccountsRead))
+	assert.True(t, models.HasPermission(models.RoleSupport, models.PermissionAccountsRead))
+	assert.False(t, models.HasPermission(models.RoleSupport, models.PermissionBalanceAdjust))
+	assert.True(t, models.HasPermission(models.RoleAdmin, models.PermissionBalanceAdjust))
+	assert.False(t, models.HasPermission("", models.PermissionAccountsRead))
+}
+
+func TestAdjustBalance_NegativeAmount_DebitsWalletAndAudits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockAdmin(ctrl)
+	mockAccount := mock_repository.NewMockAccount(ctrl)
+
+	s := newTestService()
+	s.Account = NewAccountService(mockAccount, s)
+	srv := NewAdminService(mockRepo, s)
+	mockWallet := mock_repository.NewMockWallet(ctrl)
+	mockLedger := mock_repository.NewMockLedger(ctrl)
+	srv.s.Currency = newTestCurrencyService(ctrl)
+	srv.s.Ledger = NewLedgerService(mockLedger)
+	srv.s.Wallet = NewWalletService(mockWallet, srv.s)
+
+	email := "alice@example.com"
+	amount := decimal.NewFromInt(30)
+
+	mockAccount.EXPECT().GetByEmail(t.Context(), email).Return(&db.AppAccount{Email: email, Role: models.RoleUser}, nil)
+	expectAdminTx(t, ctrl, mockRepo)
+
+	mockWallet.EXPECT().IsExistCurrency(gomock.Any(), email, "USD").Return(true, nil)
+	mockWallet.EXPECT().GetForUpdate(gomock.Any(), email, "USD").Return(&db.AppWallet{Email: email, Currency: "USD", Balance: decimal.NewFromInt(100)}, nil)
+	mockWallet.EXPECT().GetHeldAmount(gomock.Any(), email, "USD").Return(decimal.Zero, nil)
+	mockWallet.EXPECT().Update(gomock.Any(), email, "USD", decimal.NewFromInt(70)).Return(nil, nil)
+	mockWallet.EXPECT().GetAllByEmail(gomock.Any(), email).Return([]db.AppWallet{{Email: email, Currency: "USD", Balance: decimal.NewFromInt(70)}}, nil)
+
+	mockLedger.EXPECT().CreateTransaction(gomock.Any(), models.TransactionAdjustment).Return(&db.AppTransaction{ID: 7}, nil)
+	mockLedger.EXPECT().CreateEntry(gomock.Any(), int64(7), email, "USD", models.EntryDebit, amount).Return(nil)
+	mockLedger.EXPECT().CreateEntry(gomock.Any(), int64(7), models.SystemAdjustmentAccount, "USD", models.EntryCredit, amount).Return(nil)
+
+	mockRepo.EXPECT().CreateAuditEntry(gomock.Any(), testAdmin, models.AuditAdjustment, email, "chargeback", gomock.Any()).DoAndReturn(
+		func(_ context.Context, _, _, _, _ string, details []byte) error {
+			assert.JSONEq(t, `{"amount":"-30","currency":"USD","transaction_id":7}`, string(details))
+			return nil
+		})
+
+	transactionID, wallets, err := srv.AdjustBalance(t.Context(), testAdmin, email, "", "USD", amount.Neg(), " chargeback ")
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), transactionID)
+	assert.True(t, decimal.NewFromInt(70).Equal(wallets["USD"]))
+}
+
+func TestAdjustBalance_MissingReason_ReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	s := newTestService()
+	s.Account = NewAccountService(mock_repository.NewMockAccount(ctrl), s)
+	srv := NewAdminService(mock_repository.NewMockAdmin(ctrl), s)
+
+	_, _, err := srv.AdjustBalance(t.Context(), testAdmin, "alice@example.com", "", "USD", decimal.NewFromInt(10), "  ")
+
+	assert.ErrorIs(t, err, ErrReasonRequired)
+}
+
+func TestSetRole_OwnAccount_ReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccount := mock_repository.NewMockAccount(ctrl)
+
+	s := newTestService()
+	s.Account = NewAccountService(mockAccount, s)
+	srv := NewAdminService(mock_repository.NewMockAdmin(ctrl), s)
+
+	mockAccount.EXPECT().GetByEmail(t.Context(), testAdmin).Return(&db.AppAccount{Email: testAdmin, Role: models.RoleAdmin}, nil)
+
+	err := srv.SetRole(t.Context(), testAdmin, testAdmin, models.RoleUser, "demote")
+
+	assert.ErrorIs(t, err, ErrCannotChangeOwnRole)
+}
+
+func TestSetRole_RevokesSessionsAndAudits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockAdmin(ctrl)
+	mockAccount := mock_repository.NewMockAccount(ctrl)
+
+	s := newTestService()
+	s.Account = NewAccountService(mockAccount, s)
+	srv := NewAdminService(mockRepo, s)
+	mockSession := mock_repository.NewMockSession(ctrl)
+	srv.s.Session = NewSessionService(mockSession, srv.s)
+
+	mockAccount.EXPECT().GetByEmail(t.Context(), "bob@example.com").Return(&db.AppAccount{Email: "bob@example.com", Role: models.RoleUser}, nil)
+	expectAdminTx(t, ctrl, mockRepo)
+	mockRepo.EXPECT().SetRole(gomock.Any(), "bob@example.com", models.RoleSupport).Return(true, nil)
+	mockSession.EXPECT().RevokeAll(gomock.Any(), "bob@example.com").Return(nil)
+	mockRepo.EXPECT().CreateAuditEntry(gomock.Any(), testAdmin, models.AuditSetRole, "bob@example.com", "joined support team", gomock.Any()).Return(nil)
+
+	err := srv.SetRole(t.Context(), testAdmin, "bob@example.com", models.RoleSupport, "joined support team")
+
+	assert.NoError(t, err)
+}
+
+func TestFreeze_UnknownAccount_ReturnsNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccount := mock_repository.NewMockAccount(ctrl)
+
+	s := newTestService()
+	s.Account = NewAccountService(mockAccount, s)
+	srv := NewAdminService(mock_repository.NewMockAdmin(ctrl), s)
+
+	mockAccount.EXPECT().GetByEmail(t.Context(), "ghost@example.com").Return(nil, nil)
+	mockAccount.EXPECT().GetByUsername(t.Context(), "ghost@example.com").Return(nil, nil)
+
+	err := srv.Freeze(t.Context(), testAdmin, "ghost@example.com", "fraud")
+
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+}
+
+func TestUnlockLogin_NormalizesAccountSubject(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockAdmin(ctrl)
+
+	s := newTestService()
+	s.Account = NewAccountService(mock_repository.NewMockAccount(ctrl), s)
+	srv := NewAdminService(mockRepo, s)
+	mockAttempts := mock_repository.NewMockLoginAttempt(ctrl)
+	srv.s.LoginGuard = NewLoginGuardService(mockAttempts, &config.AuthConfig{})
+
+	expectAdminTx(t, ctrl, mockRepo)
+	mockAttempts.EXPECT().Delete(gomock.Any(), models.LoginScopeAccount, "alice@example.com").Return(nil)
+	mockAttempts.EXPECT().MarkUnlocked(gomock.Any(), models.LoginScopeAccount, "alice@example.com", testAdmin).Return(nil)
+	mockRepo.EXPECT().CreateAuditEntry(gomock.Any(), testAdmin, models.AuditUnlock, "alice@example.com", "verified by phone", gomock.Any()).Return(nil)
+
+	err := srv.UnlockLogin(t.Context(), testAdmin, models.LoginScopeAccount, " Alice@Example.com ", "verified by phone")
+
+	assert.NoError(t, err)
+}
+
+func TestUnlockLogin_AuditFails_DoesNotCommit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockAdmin(ctrl)
+
+	s := newTestService()
+	s.Account = NewAccountService(mock_repository.NewMockAccount(ctrl), s)
+	srv := NewAdminService(mockRepo, s)
+	mockAttempts := mock_repository.NewMockLoginAttempt(ctrl)
+	srv.s.LoginGuard = NewLoginGuardService(mockAttempts, &config.AuthConfig{})
+
+	// Commit не ожидается: снятие блокировки откатывается вместе с записью журнала
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).Return(nil)
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+	mockAttempts.EXPECT().Delete(gomock.Any(), models.LoginScopeIP, "10.0.0.1").Return(nil)
+	mockAttempts.EXPECT().MarkUnlocked(gomock.Any(), models.LoginScopeIP, "10.0.0.1", testAdmin).Return(nil)
+	mockRepo.EXPECT().CreateAuditEntry(gomock.Any(), testAdmin, models.AuditUnlock, "10.0.0.1", "false positive", gomock.Any()).Return(errors.New("connection reset"))
+
+	err := srv.UnlockLogin(t.Context(), testAdmin, models.LoginScopeIP, "10.0.0.1", "false positive")
+
+	assert.Error(t, err)
+}
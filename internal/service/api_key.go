@@ -0,0 +1,296 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/logger"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/repository"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+)
+
+const (
+	MaxAPIKeysPerAccount = 20
+
+	apiKeyTag           = "gwk"
+	apiKeyPrefixBytes   = 6
+	apiKeySecretBytes   = 32
+	maxAPIKeyNameLength = 64
+)
+
+// APIKeyService ключи API для машинных клиентов. Ключ имеет вид gwk_<prefix>_<secret>
+// и показывается только при создании: в базе хранится его SHA-256, а prefix служит для поиска.
+type APIKeyService struct {
+	r repository.APIKey
+	s *Service
+}
+
+func (s *APIKeyService) Create(ctx context.Context, email, name string, scopes []models.Scope, allowedIPs []string, expiresAt *time.Time) (*models.NewAPIKey, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxAPIKeyNameLength {
+		return nil, ErrAPIKeyNameInvalid
+	}
+
+	scopes, err := normalizeScopes(scopes)
+	if err != nil {
+		logger.L(ctx).Warn(err.Error())
+		return nil, err
+	}
+
+	allowedIPs, err = normalizeAllowedIPs(allowedIPs)
+	if err != nil {
+		logger.L(ctx).Warn(err.Error())
+		return nil, err
+	}
+
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, ErrAPIKeyExpiryInPast
+	}
+
+	keys, err := s.List(ctx, email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	active := 0
+	for _, key := range keys {
+		if isAPIKeyActive(&key, time.Now()) {
+			active++
+		}
+	}
+	if active >= MaxAPIKeysPerAccount {
+		logger.L(ctx).Warn(ErrTooManyAPIKeys.Error(), zap.String("email", email))
+		return nil, ErrTooManyAPIKeys
+	}
+
+	prefix, key, err := generateAPIKey()
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	row, err := s.r.Create(ctx, email, name, prefix, hashToken(key), scopes, allowedIPs, expiresAt)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return &models.NewAPIKey{
+		APIKey: *toAPIKey(row),
+		Key:    key,
+	}, nil
+}
+
+func (s *APIKeyService) List(ctx context.Context, email string) ([]models.APIKey, error) {
+	rows, err := s.r.List(ctx, email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	keys := make([]models.APIKey, 0, len(rows))
+	for i := range rows {
+		keys = append(keys, *toAPIKey(&rows[i]))
+	}
+
+	return keys, nil
+}
+
+func (s *APIKeyService) Revoke(ctx context.Context, email, id string) error {
+	revoked, err := s.r.Revoke(ctx, email, id)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	if !revoked {
+		logger.L(ctx).Warn(ErrAPIKeyNotFound.Error(), zap.String("id", id))
+		return ErrAPIKeyNotFound
+	}
+
+	return nil
+}
+
+// Authenticate проверяет ключ и адрес клиента и возвращает ключ без секрета
+func (s *APIKeyService) Authenticate(ctx context.Context, key, clientIP string) (*models.APIKey, error) {
+	prefix, ok := parseAPIKey(key)
+	if !ok {
+		logger.L(ctx).Warn(ErrAPIKeyInvalid.Error())
+		return nil, ErrAPIKeyInvalid
+	}
+
+	row, err := s.r.GetByPrefix(ctx, prefix)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	if row == nil || subtle.ConstantTimeCompare([]byte(hashToken(key)), []byte(row.KeyHash)) != 1 {
+		logger.L(ctx).Warn(ErrAPIKeyInvalid.Error(), zap.String("prefix", prefix))
+		return nil, ErrAPIKeyInvalid
+	}
+
+	apiKey := toAPIKey(row)
+	if !isAPIKeyActive(apiKey, time.Now()) {
+		logger.L(ctx).Warn(ErrAPIKeyInvalid.Error(), zap.String("prefix", prefix))
+		return nil, ErrAPIKeyInvalid
+	}
+
+	if !isIPAllowed(apiKey.AllowedIPs, clientIP) {
+		logger.L(ctx).Warn(ErrAPIKeyIPNotAllowed.Error(), zap.String("prefix", prefix), zap.String("ip", clientIP))
+		return nil, ErrAPIKeyIPNotAllowed
+	}
+
+	// Время последнего использования справочное, его ошибка не мешает запросу
+	if err = s.r.Touch(ctx, row.ID); err != nil {
+		logger.L(ctx).Error(err.Error())
+	}
+
+	return apiKey, nil
+}
+
+func NewAPIKeyService(r repository.APIKey, s *Service) *APIKeyService {
+	return &APIKeyService{
+		r: r,
+		s: s,
+	}
+}
+
+func toAPIKey(row *db.AppApiKey) *models.APIKey {
+	key := &models.APIKey{
+		ID:         row.ID.String(),
+		Email:      row.Email,
+		Name:       row.Name,
+		Prefix:     row.Prefix,
+		Scopes:     row.Scopes,
+		AllowedIPs: row.AllowedIps,
+		CreatedAt:  row.CreatedAt.Time,
+	}
+	if row.ExpiresAt.Valid {
+		key.ExpiresAt = &row.ExpiresAt.Time
+	}
+	if row.LastUsedAt.Valid {
+		key.LastUsedAt = &row.LastUsedAt.Time
+	}
+	if row.RevokedAt.Valid {
+		key.RevokedAt = &row.RevokedAt.Time
+	}
+
+	return key
+}
+
+func isAPIKeyActive(key *models.APIKey, now time.Time) bool {
+	return key.RevokedAt == nil && (key.ExpiresAt == nil || now.Before(*key.ExpiresAt))
+}
+
+// generateAPIKey возвращает открытый префикс и полный ключ
+func generateAPIKey() (string, string, error) {
+	rawPrefix := make([]byte, apiKeyPrefixBytes)
+	if _, err := rand.Read(rawPrefix); err != nil {
+		return "", "", err
+	}
+
+	rawSecret := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(rawSecret); err != nil {
+		return "", "", err
+	}
+
+	prefix := hex.EncodeToString(rawPrefix)
+	key := apiKeyTag + "_" + prefix + "_" + base64.RawURLEncoding.EncodeToString(rawSecret)
+
+	return prefix, key, nil
+}
+
+// parseAPIKey извлекает префикс. Секрет может содержать "_", поэтому ключ
+// делится не более чем на три части.
+func parseAPIKey(key string) (string, bool) {
+	parts := strings.SplitN(key, "_", 3)
+	if len(parts) != 3 || parts[0] != apiKeyTag || len(parts[1]) != apiKeyPrefixBytes*2 || parts[2] == "" {
+		return "", false
+	}
+
+	return parts[1], true
+}
+
+// normalizeScopes проверяет области доступа и убирает повторы
+func normalizeScopes(scopes []models.Scope) ([]models.Scope, error) {
+	if len(scopes) == 0 {
+		return nil, ErrScopesRequired
+	}
+
+	normalized := make([]models.Scope, 0, len(scopes))
+	for _, scope := range scopes {
+		if !models.IsValidScope(scope) {
+			return nil, ErrInvalidScope
+		}
+		if !slices.Contains(normalized, scope) {
+			normalized = append(normalized, scope)
+		}
+	}
+	slices.Sort(normalized)
+
+	return normalized, nil
+}
+
+// normalizeAllowedIPs приводит адреса и подсети к нотации CIDR, одиночный адрес
+// становится подсетью /32 или /128
+func normalizeAllowedIPs(allowedIPs []string) ([]string, error) {
+	normalized := make([]string, 0, len(allowedIPs))
+	for _, entry := range allowedIPs {
+		entry = strings.TrimSpace(entry)
+
+		var prefix netip.Prefix
+		if strings.Contains(entry, "/") {
+			p, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, ErrInvalidAllowedIP
+			}
+			prefix = p.Masked()
+		} else {
+			addr, err := netip.ParseAddr(entry)
+			if err != nil {
+				return nil, ErrInvalidAllowedIP
+			}
+			addr = addr.Unmap()
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+
+		if !slices.Contains(normalized, prefix.String()) {
+			normalized = append(normalized, prefix.String())
+		}
+	}
+
+	return normalized, nil
+}
+
+func isIPAllowed(allowedIPs []string, clientIP string) bool {
+	if len(allowedIPs) == 0 {
+		return true
+	}
+
+	addr, err := netip.ParseAddr(clientIP)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+
+	for _, entry := range allowedIPs {
+		prefix, err := netip.ParsePrefix(entry)
+		if err == nil && prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,17 @@
+package service
+
+import "net/http"
+
+var (
+	ErrAPIKeyInvalid      = newError("api_key_invalid", http.StatusUnauthorized, "invalid, expired or revoked API key")
+	ErrAPIKeyNotFound     = newError("api_key_not_found", http.StatusNotFound, "API key not found")
+	ErrAPIKeyIPNotAllowed = newError("api_key_ip_not_allowed", http.StatusForbidden, "API key is not allowed from this IP address")
+	ErrAPIKeyScopeDenied  = newError("api_key_scope_denied", http.StatusForbidden, "API key lacks the required scope")
+	ErrAPIKeyNotAccepted  = newError("api_key_not_accepted", http.StatusForbidden, "API keys cannot be used for this operation")
+	ErrAPIKeyNameInvalid  = newError("api_key_name_invalid", http.StatusBadRequest, "API key name must be 1 to 64 characters")
+	ErrInvalidScope       = newError("invalid_scope", http.StatusBadRequest, "invalid scope")
+	ErrScopesRequired     = newError("scopes_required", http.StatusBadRequest, "at least one scope is required")
+	ErrInvalidAllowedIP   = newError("invalid_allowed_ip", http.StatusBadRequest, "invalid IP address or CIDR in allowlist")
+	ErrAPIKeyExpiryInPast = newError("api_key_expiry_in_past", http.StatusBadRequest, "API key expiry must be in the future")
+	ErrTooManyAPIKeys     = newError("too_many_api_keys", http.StatusConflict, "too many active API keys")
+)
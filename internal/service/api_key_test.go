@@ -0,0 +1,154 @@
+package service
+
+import (
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/models"
+	mock_repository "gw-currency-wallet/internal/repository/mocks"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const testAPIKey = "gwk_0123456789ab_c2VjcmV0LXNlY3JldC1zZWNyZXQ"
+
+func testStoredAPIKey(allowedIPs ...string) *db.AppApiKey {
+	return &db.AppApiKey{
+		Email:      "user@example.com",
+		Name:       "export",
+		Prefix:     "0123456789ab",
+		KeyHash:    hashToken(testAPIKey),
+		Scopes:     []string{models.ScopeBalanceRead},
+		AllowedIps: allowedIPs,
+		CreatedAt:  pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}
+}
+
+func TestCreateAPIKey_StoresHashAndNormalizesInput(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockAPIKey(ctrl)
+	srv := NewAPIKeyService(mockRepo, &Service{})
+
+	var storedHash, storedPrefix string
+	mockRepo.EXPECT().List(t.Context(), "user@example.com").Return(nil, nil)
+	mockRepo.EXPECT().Create(t.Context(), "user@example.com", "export", gomock.Any(), gomock.Any(),
+		[]string{models.ScopeBalanceRead, models.ScopeTransactionsRead},
+		[]string{"203.0.113.10/32", "10.0.0.0/8"},
+		(*time.Time)(nil),
+	).DoAndReturn(func(_ any, email, name, prefix, keyHash string, scopes, allowedIPs []string, _ *time.Time) (*db.AppApiKey, error) {
+		storedPrefix, storedHash = prefix, keyHash
+		return &db.AppApiKey{Email: email, Name: name, Prefix: prefix, KeyHash: keyHash, Scopes: scopes, AllowedIps: allowedIPs}, nil
+	})
+
+	key, err := srv.Create(t.Context(), "user@example.com", " export ",
+		[]models.Scope{models.ScopeTransactionsRead, models.ScopeBalanceRead, models.ScopeBalanceRead},
+		[]string{"203.0.113.10", "10.1.2.3/8"},
+		nil,
+	)
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(key.Key, "gwk_"+storedPrefix+"_"))
+	assert.Equal(t, hashToken(key.Key), storedHash)
+	assert.NotContains(t, storedHash, key.Key)
+}
+
+func TestCreateAPIKey_InvalidScope_ReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := NewAPIKeyService(mock_repository.NewMockAPIKey(ctrl), &Service{})
+
+	_, err := srv.Create(t.Context(), "user@example.com", "export", []models.Scope{"admin:everything"}, nil, nil)
+
+	assert.ErrorIs(t, err, ErrInvalidScope)
+}
+
+func TestCreateAPIKey_InvalidAllowedIP_ReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := NewAPIKeyService(mock_repository.NewMockAPIKey(ctrl), &Service{})
+
+	_, err := srv.Create(t.Context(), "user@example.com", "export", []models.Scope{models.ScopeBalanceRead}, []string{"not-an-ip"}, nil)
+
+	assert.ErrorIs(t, err, ErrInvalidAllowedIP)
+}
+
+func TestAuthenticateAPIKey_AllowedIP_ReturnsKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockAPIKey(ctrl)
+	srv := NewAPIKeyService(mockRepo, &Service{})
+
+	stored := testStoredAPIKey("10.0.0.0/8")
+	mockRepo.EXPECT().GetByPrefix(t.Context(), "0123456789ab").Return(stored, nil)
+	mockRepo.EXPECT().Touch(t.Context(), stored.ID).Return(nil)
+
+	key, err := srv.Authenticate(t.Context(), testAPIKey, "10.20.30.40")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "user@example.com", key.Email)
+	assert.Contains(t, key.Scopes, models.ScopeBalanceRead)
+	assert.NotContains(t, key.Scopes, models.ScopeWalletWithdraw)
+}
+
+func TestAuthenticateAPIKey_WrongSecret_ReturnsInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockAPIKey(ctrl)
+	srv := NewAPIKeyService(mockRepo, &Service{})
+
+	mockRepo.EXPECT().GetByPrefix(t.Context(), "0123456789ab").Return(testStoredAPIKey(), nil)
+
+	_, err := srv.Authenticate(t.Context(), "gwk_0123456789ab_wrong", "10.20.30.40")
+
+	assert.ErrorIs(t, err, ErrAPIKeyInvalid)
+}
+
+func TestAuthenticateAPIKey_Revoked_ReturnsInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockAPIKey(ctrl)
+	srv := NewAPIKeyService(mockRepo, &Service{})
+
+	stored := testStoredAPIKey()
+	stored.RevokedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	mockRepo.EXPECT().GetByPrefix(t.Context(), "0123456789ab").Return(stored, nil)
+
+	_, err := srv.Authenticate(t.Context(), testAPIKey, "10.20.30.40")
+
+	assert.ErrorIs(t, err, ErrAPIKeyInvalid)
+}
+
+func TestAuthenticateAPIKey_IPNotInAllowlist_ReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockAPIKey(ctrl)
+	srv := NewAPIKeyService(mockRepo, &Service{})
+
+	mockRepo.EXPECT().GetByPrefix(t.Context(), "0123456789ab").Return(testStoredAPIKey("203.0.113.10/32"), nil)
+
+	_, err := srv.Authenticate(t.Context(), testAPIKey, "198.51.100.7")
+
+	assert.ErrorIs(t, err, ErrAPIKeyIPNotAllowed)
+}
+
+func TestAuthenticateAPIKey_Malformed_ReturnsInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := NewAPIKeyService(mock_repository.NewMockAPIKey(ctrl), &Service{})
+
+	_, err := srv.Authenticate(t.Context(), "Bearer something", "10.20.30.40")
+
+	assert.ErrorIs(t, err, ErrAPIKeyInvalid)
+}
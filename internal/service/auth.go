@@ -1,64 +1,141 @@
 package service
 
 import (
+	"errors"
+	"fmt"
 	"gw-currency-wallet/config"
+	"gw-currency-wallet/internal/hasher"
 	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/pkg"
+	"sort"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
+	"go.uber.org/zap"
 )
 
 const (
-	DefaultJWTExpireDuration = time.Hour * 24
+	DefaultJWTExpireDuration    = time.Minute * 15
+	DefaultRefreshTokenDuration = time.Hour * 24 * 30
+
+	// TOTPIssuer название сервиса в приложении-аутентификаторе
+	TOTPIssuer = "gw-currency-wallet"
 )
 
+// AuthService подписывает токены активным асимметричным ключом, а проверяет любым
+// из загруженных ключей по kid. Если ключи не настроены, используется HS256 с SecretKey;
+// пока SecretKey задан, токены HS256 продолжают приниматься.
+//
+// Пароли хешируются алгоритмом из конфигурации (по умолчанию argon2id), хеши прежних
+// алгоритмов продолжают проверяться.
 type AuthService struct {
 	secretKey string
+	keys      map[string]*signingKey
+	active    *signingKey
+	hasher    hasher.Hasher
+	policy    *passwordPolicy
 }
 
 func NewAuthService(cfg *config.AuthConfig) *AuthService {
-	return &AuthService{secretKey: cfg.SecretKey}
+	s, err := newAuthService(cfg)
+	if err != nil {
+		zap.L().Fatal(err.Error())
+	}
+
+	return s
 }
 
-func (s *AuthService) HashPassword(password string) (string, error) {
-	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+func newAuthService(cfg *config.AuthConfig) (*AuthService, error) {
+	passwordHasher, err := hasher.New(&cfg.PasswordHash)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := newPasswordPolicy(&cfg.PasswordPolicy)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	s := &AuthService{
+		secretKey: cfg.SecretKey,
+		keys:      make(map[string]*signingKey, len(cfg.SigningKeys)),
+		hasher:    passwordHasher,
+		policy:    policy,
+	}
+
+	for kid, path := range cfg.SigningKeys {
+		key, err := loadSigningKey(kid, path)
+		if err != nil {
+			return nil, err
+		}
+		s.keys[kid] = key
+	}
+
+	if cfg.ActiveKeyID == "" {
+		if len(s.keys) > 0 {
+			return nil, errors.New("active signing key id is required when signing keys are configured")
+		}
+		return s, nil
+	}
+
+	active, ok := s.keys[cfg.ActiveKeyID]
+	if !ok {
+		return nil, fmt.Errorf("active signing key %s is not configured", cfg.ActiveKeyID)
+	}
+	if active.private == nil {
+		return nil, fmt.Errorf("active signing key %s has no private key", cfg.ActiveKeyID)
 	}
-	return string(hashedBytes), nil
+	s.active = active
+
+	return s, nil
+}
+
+func (s *AuthService) HashPassword(password string) (string, error) {
+	return s.hasher.Hash(password)
 }
 
 func (s *AuthService) ComparePassword(hashedPassword, password string) error {
-	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
-	return err
+	return s.hasher.Compare(hashedPassword, password)
 }
 
-func (s *AuthService) GenerateJWT(email string) (string, error) {
+// NeedsRehash сообщает, что хеш создан другим алгоритмом или с устаревшими параметрами
+func (s *AuthService) NeedsRehash(hashedPassword string) bool {
+	return s.hasher.NeedsRehash(hashedPassword)
+}
+
+// ValidatePassword проверяет новый пароль по политике паролей
+func (s *AuthService) ValidatePassword(password, username string) error {
+	return s.policy.validate(password, username)
+}
+
+func (s *AuthService) GenerateJWT(email, sessionID string, role models.Role) (string, error) {
 	claims := models.AuthClaims{
-		Email: email,
+		Email:     email,
+		SessionID: sessionID,
+		Role:      role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(DefaultJWTExpireDuration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	signedToken, err := token.SignedString([]byte(s.secretKey))
-	if err != nil {
-		return "", err
+	if s.active == nil {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		return token.SignedString([]byte(s.secretKey))
 	}
 
-	return signedToken, nil
+	token := jwt.NewWithClaims(s.active.method, claims)
+	token.Header["kid"] = s.active.id
+
+	return token.SignedString(s.active.private)
 }
 
 func (s *AuthService) GetClaims(tokenString string) (*models.AuthClaims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &models.AuthClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, jwt.ErrTokenSignatureInvalid
-		}
-		return []byte(s.secretKey), nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &models.AuthClaims{}, s.verificationKey, jwt.WithValidMethods([]string{
+		jwt.SigningMethodHS256.Alg(),
+		jwt.SigningMethodRS256.Alg(),
+		jwt.SigningMethodEdDSA.Alg(),
+	}))
 	if err != nil {
 		return nil, err
 	}
@@ -69,3 +146,55 @@ func (s *AuthService) GetClaims(tokenString string) (*models.AuthClaims, error)
 
 	return nil, ErrTokenInvalid
 }
+
+// GenerateTOTP создаёт секрет TOTP и ссылку otpauth:// для аккаунта
+func (s *AuthService) GenerateTOTP(account string) (*models.TOTPEnrollment, error) {
+	secret, err := pkg.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TOTPEnrollment{
+		Secret: secret,
+		URI:    pkg.TOTPURI(TOTPIssuer, account, secret),
+	}, nil
+}
+
+// ValidateTOTP проверяет код и возвращает номер его интервала. Коды из интервалов
+// не позже afterStep отклоняются.
+func (s *AuthService) ValidateTOTP(secret, code string, afterStep int64) (int64, bool) {
+	return pkg.ValidateTOTP(secret, code, time.Now(), afterStep)
+}
+
+// JWKS возвращает открытые ключи всех загруженных ключей, упорядоченные по kid
+func (s *AuthService) JWKS() []models.JWK {
+	ids := make([]string, 0, len(s.keys))
+	for kid := range s.keys {
+		ids = append(ids, kid)
+	}
+	sort.Strings(ids)
+
+	keys := make([]models.JWK, 0, len(ids))
+	for _, kid := range ids {
+		keys = append(keys, s.keys[kid].jwk())
+	}
+
+	return keys
+}
+
+func (s *AuthService) verificationKey(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		if s.secretKey == "" {
+			return nil, jwt.ErrTokenUnverifiable
+		}
+		return []byte(s.secretKey), nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := s.keys[kid]
+	if !ok || key.method.Alg() != token.Method.Alg() {
+		return nil, jwt.ErrTokenUnverifiable
+	}
+
+	return key.public, nil
+}
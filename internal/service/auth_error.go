@@ -1,5 +1,10 @@
 package service
 
-import "errors"
+import "net/http"
 
-var ErrTokenInvalid = errors.New("invalid token")
+var (
+	ErrTokenInvalid        = newError("token_invalid", http.StatusUnauthorized, "invalid token")
+	ErrRefreshTokenInvalid = newError("refresh_token_invalid", http.StatusUnauthorized, "invalid or expired refresh token")
+	ErrRefreshTokenReused  = newError("refresh_token_reused", http.StatusUnauthorized, "refresh token reuse detected, session revoked")
+	ErrSessionRevoked      = newError("session_revoked", http.StatusUnauthorized, "session has been revoked")
+)
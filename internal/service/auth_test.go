@@ -0,0 +1,153 @@
+package service
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"gw-currency-wallet/config"
+	"gw-currency-wallet/internal/models"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestKey(t *testing.T, name string, key any) string {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), name+".pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+
+	return path
+}
+
+func writeTestPublicKey(t *testing.T, name string, key any) string {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), name+".pem")
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	return path
+}
+
+func newTestRSAKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key
+}
+
+func TestGenerateJWT_RS256_SetsKidAndVerifies(t *testing.T) {
+	srv, err := newAuthService(&config.AuthConfig{
+		SigningKeys: map[string]string{"k1": writeTestKey(t, "k1", newTestRSAKey(t))},
+		ActiveKeyID: "k1",
+	})
+	require.NoError(t, err)
+
+	token, err := srv.GenerateJWT("user@example.com", testSessionID, models.RoleUser)
+	require.NoError(t, err)
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &jwt.MapClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "RS256", parsed.Method.Alg())
+	assert.Equal(t, "k1", parsed.Header["kid"])
+
+	claims, err := srv.GetClaims(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "user@example.com", claims.Email)
+}
+
+func TestGetClaims_RotatedKey_StillVerifies(t *testing.T) {
+	oldKey := newTestRSAKey(t)
+	_, newKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	before, err := newAuthService(&config.AuthConfig{
+		SigningKeys: map[string]string{"old": writeTestKey(t, "old", oldKey)},
+		ActiveKeyID: "old",
+	})
+	require.NoError(t, err)
+
+	oldToken, err := before.GenerateJWT("user@example.com", testSessionID, models.RoleUser)
+	require.NoError(t, err)
+
+	// После ротации старый ключ оставлен только открытой частью
+	after, err := newAuthService(&config.AuthConfig{
+		SigningKeys: map[string]string{
+			"old": writeTestPublicKey(t, "old", &oldKey.PublicKey),
+			"new": writeTestKey(t, "new", newKey),
+		},
+		ActiveKeyID: "new",
+	})
+	require.NoError(t, err)
+
+	newToken, err := after.GenerateJWT("user@example.com", testSessionID, models.RoleUser)
+	require.NoError(t, err)
+
+	_, err = after.GetClaims(oldToken)
+	assert.NoError(t, err)
+
+	_, err = after.GetClaims(newToken)
+	assert.NoError(t, err)
+
+	_, err = before.GetClaims(newToken)
+	assert.Error(t, err)
+}
+
+func TestGetClaims_HMACWithoutSecret_Rejected(t *testing.T) {
+	legacy := NewAuthService(&config.AuthConfig{SecretKey: "test"})
+	token, err := legacy.GenerateJWT("user@example.com", testSessionID, models.RoleUser)
+	require.NoError(t, err)
+
+	srv, err := newAuthService(&config.AuthConfig{
+		SigningKeys: map[string]string{"k1": writeTestKey(t, "k1", newTestRSAKey(t))},
+		ActiveKeyID: "k1",
+	})
+	require.NoError(t, err)
+
+	_, err = srv.GetClaims(token)
+	assert.Error(t, err)
+}
+
+func TestNewAuthService_PublicOnlyActiveKey_ReturnsError(t *testing.T) {
+	key := newTestRSAKey(t)
+
+	_, err := newAuthService(&config.AuthConfig{
+		SigningKeys: map[string]string{"k1": writeTestPublicKey(t, "k1", &key.PublicKey)},
+		ActiveKeyID: "k1",
+	})
+
+	assert.Error(t, err)
+}
+
+func TestJWKS_ReturnsPublicKeys(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	srv, err := newAuthService(&config.AuthConfig{
+		SigningKeys: map[string]string{
+			"a": writeTestKey(t, "a", newTestRSAKey(t)),
+			"b": writeTestKey(t, "b", edKey),
+		},
+		ActiveKeyID: "b",
+	})
+	require.NoError(t, err)
+
+	keys := srv.JWKS()
+
+	require.Len(t, keys, 2)
+	assert.Equal(t, "a", keys[0].KeyID)
+	assert.Equal(t, "RSA", keys[0].KeyType)
+	assert.Equal(t, "RS256", keys[0].Algorithm)
+	assert.Equal(t, "AQAB", keys[0].E)
+	assert.Equal(t, "b", keys[1].KeyID)
+	assert.Equal(t, "OKP", keys[1].KeyType)
+	assert.Equal(t, "Ed25519", keys[1].Curve)
+	assert.Equal(t, "EdDSA", keys[1].Algorithm)
+}
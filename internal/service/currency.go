@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/logger"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/repository"
+	"gw-currency-wallet/pkg"
+)
+
+type CurrencyService struct {
+	r repository.Currency
+}
+
+func (s *CurrencyService) List(ctx context.Context) ([]models.Currency, error) {
+	rows, err := s.r.List(ctx)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	currencies := make([]models.Currency, 0, len(rows))
+	for i := range rows {
+		currencies = append(currencies, *currencyFromRow(&rows[i]))
+	}
+
+	return currencies, nil
+}
+
+// Resolve возвращает валюту из справочника, если она существует и включена
+func (s *CurrencyService) Resolve(ctx context.Context, code pkg.Currency) (*models.Currency, error) {
+	row, err := s.r.Get(ctx, code)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	if row == nil {
+		logger.L(ctx).Warn(ErrNonExistentCurrency.Error())
+		return nil, ErrNonExistentCurrency
+	}
+
+	if !row.Enabled {
+		logger.L(ctx).Warn(ErrCurrencyDisabled.Error())
+		return nil, ErrCurrencyDisabled
+	}
+
+	return currencyFromRow(row), nil
+}
+
+// Validate проверяет валюту и сумму, в том числе число знаков после запятой
+// по правилам валюты
+func (s *CurrencyService) Validate(ctx context.Context, code pkg.Currency, amount pkg.Amount) (*models.Currency, error) {
+	currency, err := s.Resolve(ctx, code)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	if err = validateAmount(amount); err != nil {
+		logger.L(ctx).Warn(err.Error())
+		return nil, err
+	}
+
+	if !amount.Equal(amount.Truncate(currency.MinorUnits)) {
+		logger.L(ctx).Warn(ErrAmountPrecision.Error())
+		return nil, ErrAmountPrecision
+	}
+
+	return currency, nil
+}
+
+func NewCurrencyService(r repository.Currency) *CurrencyService {
+	return &CurrencyService{
+		r: r,
+	}
+}
+
+func currencyFromRow(row *db.AppCurrency) *models.Currency {
+	return &models.Currency{
+		Code:       row.Code,
+		Name:       row.Name,
+		MinorUnits: int32(row.MinorUnits),
+		Symbol:     row.Symbol,
+		Enabled:    row.Enabled,
+	}
+}
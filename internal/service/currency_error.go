@@ -0,0 +1,7 @@
+package service
+
+import "net/http"
+
+var (
+	ErrCurrencyDisabled = newError("currency_disabled", http.StatusBadRequest, "currency is disabled")
+)
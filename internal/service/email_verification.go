@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/config"
+	"gw-currency-wallet/internal/logger"
+	"gw-currency-wallet/internal/mailer"
+	"gw-currency-wallet/internal/repository"
+	"net/url"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultVerificationTTL            = time.Hour * 24
+	DefaultVerificationResendInterval = time.Minute
+
+	verificationTokenBytes = 32
+)
+
+// EmailVerificationService подтверждение email по ссылке из письма
+type EmailVerificationService struct {
+	r         repository.EmailVerification
+	m         mailer.Mailer
+	verifyURL string
+	s         *Service
+}
+
+// Send создаёт токен подтверждения и отправляет его на email аккаунта
+func (s *EmailVerificationService) Send(ctx context.Context, email string) error {
+	raw := make([]byte, verificationTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	if err := s.r.CreateToken(ctx, hashToken(token), email, time.Now().Add(DefaultVerificationTTL)); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	if err := s.m.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Confirm your email",
+		Body:    s.verifyBody(token),
+	}); err != nil {
+		logger.L(ctx).Error(err.Error(), zap.String("email", email))
+		return err
+	}
+
+	return nil
+}
+
+// Resend отправляет новое письмо не чаще одного раза в DefaultVerificationResendInterval.
+// Ранее отправленные токены продолжают действовать до истечения срока.
+func (s *EmailVerificationService) Resend(ctx context.Context, email string) error {
+	verified, err := s.IsVerified(ctx, email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	if verified {
+		logger.L(ctx).Warn(ErrEmailAlreadyVerified.Error())
+		return ErrEmailAlreadyVerified
+	}
+
+	sentAt, err := s.r.LastSentAt(ctx, email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	if sentAt != nil {
+		if wait := time.Until(sentAt.Add(DefaultVerificationResendInterval)); wait > 0 {
+			logger.L(ctx).Warn(ErrVerificationResendTooSoon.Error(), zap.String("email", email))
+			return &VerificationThrottledError{RetryAfter: wait}
+		}
+	}
+
+	return s.Send(ctx, email)
+}
+
+// Verify подтверждает email по токену из письма. Остальные токены аккаунта
+// после этого перестают действовать.
+func (s *EmailVerificationService) Verify(ctx context.Context, token string) error {
+	c, tx, err := s.r.WithTx(ctx)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			logger.L(ctx).Error(err.Error())
+		}
+	}()
+
+	verificationToken, err := s.r.GetTokenForUpdate(c, hashToken(token))
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	if verificationToken == nil || verificationToken.UsedAt.Valid || !time.Now().Before(verificationToken.ExpiresAt.Time) {
+		logger.L(ctx).Warn(ErrVerificationTokenInvalid.Error())
+		return ErrVerificationTokenInvalid
+	}
+
+	if err = s.r.MarkVerified(c, verificationToken.Email); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	if err = s.r.UseTokens(c, verificationToken.Email); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	if err = tx.Commit(c); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (s *EmailVerificationService) IsVerified(ctx context.Context, email string) (bool, error) {
+	account, err := s.s.Account.Find(ctx, email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return false, err
+	}
+
+	return account != nil && account.Verified, nil
+}
+
+func NewEmailVerificationService(r repository.EmailVerification, m mailer.Mailer, cfg *config.AuthConfig, s *Service) *EmailVerificationService {
+	return &EmailVerificationService{
+		r:         r,
+		m:         m,
+		verifyURL: cfg.EmailVerificationURL,
+		s:         s,
+	}
+}
+
+func (s *EmailVerificationService) verifyBody(token string) string {
+	ttl := fmt.Sprintf("%d hours", int(DefaultVerificationTTL/time.Hour))
+
+	if s.verifyURL == "" {
+		return fmt.Sprintf("Use this token to confirm your email: %s\n\nThe token expires in %s.", token, ttl)
+	}
+
+	link := s.verifyURL + "?token=" + url.QueryEscape(token)
+	return fmt.Sprintf("Follow the link to confirm your email: %s\n\nThe link expires in %s.", link, ttl)
+}
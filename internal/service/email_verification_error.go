@@ -0,0 +1,30 @@
+package service
+
+import (
+	"net/http"
+	"time"
+)
+
+var (
+	ErrVerificationTokenInvalid  = newError("verification_token_invalid", http.StatusBadRequest, "invalid or expired email verification token")
+	ErrEmailAlreadyVerified      = newError("email_already_verified", http.StatusConflict, "email is already verified")
+	ErrEmailNotVerified          = newError("email_not_verified", http.StatusForbidden, "email is not verified")
+	ErrVerificationResendTooSoon = newError("verification_resend_too_soon", http.StatusTooManyRequests, "verification email was sent recently, try again later")
+)
+
+// VerificationThrottledError сообщает, через сколько можно запросить письмо повторно
+type VerificationThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *VerificationThrottledError) Error() string {
+	return ErrVerificationResendTooSoon.Error()
+}
+
+func (e *VerificationThrottledError) Unwrap() error {
+	return ErrVerificationResendTooSoon
+}
+
+func (e *VerificationThrottledError) RetryAfterSeconds() int {
+	return retryAfterSeconds(e.RetryAfter)
+}
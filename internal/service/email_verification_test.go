@@ -0,0 +1,139 @@
+package service
+
+import (
+	"gw-currency-wallet/config"
+	"gw-currency-wallet/internal/db"
+	mock_repository "gw-currency-wallet/internal/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRegister_SendsVerificationEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockEmailVerification(ctrl)
+	mockAccount := mock_repository.NewMockAccount(ctrl)
+	m := &testMailer{}
+
+	s := newTestService()
+	s.Account = NewAccountService(mockAccount, s)
+	verification := NewEmailVerificationService(mockRepo, m, &config.AuthConfig{}, s)
+	srv := verification.s.Account.(*AccountService)
+	srv.s.EmailVerification = verification
+
+	mockAccount.EXPECT().Create(t.Context(), "alice@example.com", "alice", gomock.Any()).Return(&db.AppAccount{
+		Email:    "alice@example.com",
+		Username: "alice",
+	}, nil)
+	mockRepo.EXPECT().CreateToken(t.Context(), gomock.Any(), "alice@example.com", gomock.Any()).Return(nil)
+
+	account, err := srv.Register(t.Context(), "alice@example.com", "alice", "correct-horse-42")
+
+	assert.NoError(t, err)
+	assert.False(t, account.Verified)
+	assert.Len(t, m.sent, 1)
+	assert.Equal(t, "alice@example.com", m.sent[0].To)
+}
+
+func TestResend_SentRecently_ReturnsThrottled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockEmailVerification(ctrl)
+	mockAccount := mock_repository.NewMockAccount(ctrl)
+	m := &testMailer{}
+
+	s := newTestService()
+	s.Account = NewAccountService(mockAccount, s)
+	srv := NewEmailVerificationService(mockRepo, m, &config.AuthConfig{}, s)
+
+	sentAt := time.Now().Add(-time.Second * 10)
+	mockAccount.EXPECT().GetByEmail(t.Context(), "alice@example.com").Return(&db.AppAccount{Email: "alice@example.com"}, nil)
+	mockRepo.EXPECT().LastSentAt(t.Context(), "alice@example.com").Return(&sentAt, nil)
+
+	err := srv.Resend(t.Context(), "alice@example.com")
+
+	var throttled *VerificationThrottledError
+	assert.ErrorAs(t, err, &throttled)
+	assert.ErrorIs(t, err, ErrVerificationResendTooSoon)
+	assert.InDelta(t, 50, throttled.RetryAfter.Seconds(), 1)
+	assert.Empty(t, m.sent)
+}
+
+func TestResend_AlreadyVerified_ReturnsConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccount := mock_repository.NewMockAccount(ctrl)
+
+	s := newTestService()
+	s.Account = NewAccountService(mockAccount, s)
+	srv := NewEmailVerificationService(mock_repository.NewMockEmailVerification(ctrl), &testMailer{}, &config.AuthConfig{}, s)
+
+	mockAccount.EXPECT().GetByEmail(t.Context(), "alice@example.com").Return(&db.AppAccount{
+		Email:      "alice@example.com",
+		VerifiedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}, nil)
+
+	err := srv.Resend(t.Context(), "alice@example.com")
+
+	assert.ErrorIs(t, err, ErrEmailAlreadyVerified)
+}
+
+func TestVerifyEmail_ValidToken_MarksVerified(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockEmailVerification(ctrl)
+
+	s := newTestService()
+	s.Account = NewAccountService(mock_repository.NewMockAccount(ctrl), s)
+	srv := NewEmailVerificationService(mockRepo, &testMailer{}, &config.AuthConfig{}, s)
+
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+	mockTx.EXPECT().Commit(gomock.Any()).Return(nil)
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+	mockRepo.EXPECT().GetTokenForUpdate(gomock.Any(), hashToken("verify")).Return(&db.AppEmailVerificationToken{
+		TokenHash: hashToken("verify"),
+		Email:     "alice@example.com",
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true},
+	}, nil)
+	mockRepo.EXPECT().MarkVerified(gomock.Any(), "alice@example.com").Return(nil)
+	mockRepo.EXPECT().UseTokens(gomock.Any(), "alice@example.com").Return(nil)
+
+	err := srv.Verify(t.Context(), "verify")
+
+	assert.NoError(t, err)
+}
+
+func TestVerifyEmail_ExpiredToken_ReturnsInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockEmailVerification(ctrl)
+
+	s := newTestService()
+	s.Account = NewAccountService(mock_repository.NewMockAccount(ctrl), s)
+	srv := NewEmailVerificationService(mockRepo, &testMailer{}, &config.AuthConfig{}, s)
+
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+	mockRepo.EXPECT().GetTokenForUpdate(gomock.Any(), hashToken("verify")).Return(&db.AppEmailVerificationToken{
+		TokenHash: hashToken("verify"),
+		Email:     "alice@example.com",
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(-time.Second), Valid: true},
+	}, nil)
+
+	err := srv.Verify(t.Context(), "verify")
+
+	assert.ErrorIs(t, err, ErrVerificationTokenInvalid)
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"maps"
+	"math"
+	"net/http"
+	"time"
+)
+
+// Общие ошибки запроса. Ошибки, которых нет в каталоге, отдаются клиенту как ErrInternal.
+var (
+	ErrInvalidRequest             = newError("invalid_request", http.StatusBadRequest, "invalid request")
+	ErrInvalidAuthorizationHeader = newError("unauthenticated", http.StatusUnauthorized, "missing or invalid Authorization header")
+	ErrInternal                   = newError("internal_error", http.StatusInternalServerError, "server error")
+)
+
+// Error ошибка предметной области из каталога. Code стабилен и не меняется вместе
+// с текстом сообщения, поэтому клиенты должны ветвиться по нему. Status — HTTP-статус ответа.
+type Error struct {
+	Code    string
+	Status  int
+	Message string
+	// Details необязательные сведения для клиента, например поле с ошибкой
+	Details map[string]any
+}
+
+func newError(code string, status int, message string) *Error {
+	return &Error{
+		Code:    code,
+		Status:  status,
+		Message: message,
+	}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is сравнивает ошибки по коду, поэтому копия из WithDetails совпадает с исходной ошибкой каталога
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// WithDetails возвращает копию ошибки с дополнительными сведениями
+func (e *Error) WithDetails(details map[string]any) *Error {
+	merged := make(map[string]any, len(e.Details)+len(details))
+	maps.Copy(merged, e.Details)
+	maps.Copy(merged, details)
+
+	return &Error{
+		Code:    e.Code,
+		Status:  e.Status,
+		Message: e.Message,
+		Details: merged,
+	}
+}
+
+// Retryable ошибка, после которой запрос можно повторить не раньше чем через RetryAfterSeconds
+type Retryable interface {
+	error
+	RetryAfterSeconds() int
+}
+
+func retryAfterSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
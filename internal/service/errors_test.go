@@ -0,0 +1,50 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError_WithDetails_MatchesCatalog(t *testing.T) {
+	err := ErrInsufficientBalance.WithDetails(map[string]any{"currency": "USD"})
+
+	assert.ErrorIs(t, err, ErrInsufficientBalance)
+	assert.NotErrorIs(t, err, ErrNegativeAmount)
+	assert.Equal(t, "insufficient_balance", err.Code)
+	assert.Equal(t, http.StatusBadRequest, err.Status)
+	assert.Equal(t, map[string]any{"currency": "USD"}, err.Details)
+	assert.Nil(t, ErrInsufficientBalance.Details)
+}
+
+func TestExchangeErrors_AreUpstreamFailures(t *testing.T) {
+	assert.Equal(t, http.StatusServiceUnavailable, ErrGetRate.Status)
+	assert.Equal(t, "exchange_rate_unavailable", ErrGetRate.Code)
+	assert.Equal(t, http.StatusGatewayTimeout, ErrTimeout.Status)
+	assert.Equal(t, "exchange_rate_timeout", ErrTimeout.Code)
+}
+
+func TestError_As_Wrapped(t *testing.T) {
+	err := fmt.Errorf("withdraw: %w", ErrAccountFrozen)
+
+	var e *Error
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, "account_frozen", e.Code)
+	assert.Equal(t, http.StatusForbidden, e.Status)
+}
+
+func TestLoginThrottledError_Retryable(t *testing.T) {
+	var err error = &LoginThrottledError{RetryAfter: 1500 * time.Millisecond}
+
+	var e *Error
+	assert.True(t, errors.As(err, &e))
+	assert.Equal(t, http.StatusTooManyRequests, e.Status)
+
+	var retryable Retryable
+	assert.True(t, errors.As(err, &retryable))
+	assert.Equal(t, 2, retryable.RetryAfterSeconds())
+}
@@ -2,11 +2,12 @@ package service
 
 import (
 	"context"
-	"errors"
+	"gw-currency-wallet/internal/logger"
 	gw_grpc "gw-currency-wallet/internal/pb/exchange"
 	"gw-currency-wallet/pkg"
 	"time"
 
+	"github.com/shopspring/decimal"
 	"go.uber.org/zap"
 )
 
@@ -14,6 +15,9 @@ const (
 	maxAttempts    = 3
 	retryInterval  = 500 * time.Millisecond
 	requestTimeout = 5 * time.Second
+
+	// exchangeRatesCache имя кеша курсов в метриках
+	exchangeRatesCache = "exchange_rates"
 )
 
 type ExchangeService struct {
@@ -24,18 +28,18 @@ type ExchangeService struct {
 func (s *ExchangeService) GetRate(ctx context.Context, from, to pkg.Currency) (pkg.Rate, error) {
 	rates, err := s.rateCache.GetData(ctx)
 	if err != nil {
-		zap.L().Error(err.Error())
-		return -1, err
+		logger.L(ctx).Error(err.Error())
+		return decimal.Zero, ErrGetRate
 	}
 
-	rateCh := make(chan float32, 1)
+	rateCh := make(chan pkg.Rate, 1)
 	timeoutContext, cancel := context.WithTimeout(ctx, requestTimeout)
 	defer cancel()
 
 	go func() {
 		defer close(rateCh)
 
-		var fromRate, toRate float32
+		var fromRate, toRate pkg.Rate
 		var fromOK, toOK bool
 
 		for i := 0; i < maxAttempts; i++ {
@@ -43,7 +47,7 @@ func (s *ExchangeService) GetRate(ctx context.Context, from, to pkg.Currency) (p
 			toRate, toOK = rates[to]
 
 			if fromOK && toOK {
-				rateCh <- toRate / fromRate
+				rateCh <- toRate.Div(fromRate)
 				return
 			}
 
@@ -53,7 +57,7 @@ func (s *ExchangeService) GetRate(ctx context.Context, from, to pkg.Currency) (p
 			case <-time.After(retryInterval):
 				newRates, err := s.rateCache.ForceSync(timeoutContext)
 				if err != nil {
-					zap.L().Error(err.Error())
+					logger.L(ctx).Error(err.Error())
 				} else {
 					rates = newRates
 				}
@@ -63,18 +67,18 @@ func (s *ExchangeService) GetRate(ctx context.Context, from, to pkg.Currency) (p
 		fromRate, fromOK = rates[from]
 		toRate, toOK = rates[to]
 		if fromOK && toOK {
-			rateCh <- toRate / fromRate
+			rateCh <- toRate.Div(fromRate)
 			return
 		}
 	}()
 
 	select {
 	case <-timeoutContext.Done():
-		zap.L().Error(ErrTimeout)
-		return -1, errors.New(ErrTimeout)
+		logger.L(ctx).Error(ErrTimeout.Error())
+		return decimal.Zero, ErrTimeout
 	case rate, ok := <-rateCh:
 		if !ok {
-			return -1, errors.New(ErrGetRate)
+			return decimal.Zero, ErrGetRate
 		}
 		return rate, nil
 	}
@@ -84,8 +88,8 @@ func (s *ExchangeService) GetRate(ctx context.Context, from, to pkg.Currency) (p
 func (s *ExchangeService) GetRates(ctx context.Context) (pkg.ExchangeRates, error) {
 	rates, err := s.rateCache.GetData(ctx)
 	if err != nil {
-		zap.L().Error(err.Error())
-		return nil, err
+		logger.L(ctx).Error(err.Error())
+		return nil, ErrGetRate
 	}
 
 	return rates, nil
@@ -94,22 +98,22 @@ func (s *ExchangeService) GetRates(ctx context.Context) (pkg.ExchangeRates, erro
 func (s *ExchangeService) IsExistCurrency(ctx context.Context, currency pkg.Currency) (bool, error) {
 	rates, err := s.rateCache.GetData(ctx)
 	if err != nil {
-		zap.L().Error(err.Error())
-		return false, err
+		logger.L(ctx).Error(err.Error())
+		return false, ErrGetRate
 	}
 
 	_, ok := rates[currency]
 	return ok, nil
 }
 
-func NewExchangeService(ctx context.Context, client gw_grpc.ExchangeServiceClient) *ExchangeService {
+func NewExchangeService(ctx context.Context, client gw_grpc.ExchangeServiceClient, observer pkg.CacherObserver) *ExchangeService {
 	s := &ExchangeService{
 		c: client,
 	}
 
-	cache, err := s.getRateCacher(ctx)
+	cache, err := s.getRateCacher(ctx, observer)
 	if err != nil {
-		zap.L().Fatal(err.Error())
+		logger.L(ctx).Fatal(err.Error())
 	}
 
 	s.rateCache = cache
@@ -117,14 +121,32 @@ func NewExchangeService(ctx context.Context, client gw_grpc.ExchangeServiceClien
 	return s
 }
 
-func (s *ExchangeService) getRateCacher(ctx context.Context) (*pkg.Cacher[pkg.ExchangeRates], error) {
-	return pkg.NewCacher[pkg.ExchangeRates](ctx, func(c context.Context) (pkg.ExchangeRates, error) {
+func (s *ExchangeService) getRateCacher(ctx context.Context, observer pkg.CacherObserver) (*pkg.Cacher[pkg.ExchangeRates], error) {
+	var opts []pkg.CacherOption
+	if observer != nil {
+		opts = append(opts, pkg.WithCacherObserver(observer))
+	}
+
+	return pkg.NewCacher[pkg.ExchangeRates](ctx, func(c context.Context) (_ pkg.ExchangeRates, err error) {
+		c, span := startSpan(c, "ExchangeService.fetchRates")
+		defer func() { endSpan(span, err) }()
+
 		resp, err := s.c.GetExchangeRates(c, nil)
 		if err != nil {
-			zap.L().Error(err.Error())
+			logger.L(c).Error(err.Error())
 			return nil, err
 		}
 
-		return resp.Rates, nil
-	}, pkg.DefaultCacherTTL)
+		rates := make(pkg.ExchangeRates, len(resp.Rates))
+		for currency, rate := range resp.Rates {
+			// нулевой или отрицательный курс не может участвовать в делении
+			if rate <= 0 {
+				logger.L(c).Warn("invalid exchange rate", zap.String("currency", currency), zap.Float32("rate", rate))
+				continue
+			}
+			rates[currency] = decimal.NewFromFloat32(rate)
+		}
+
+		return rates, nil
+	}, pkg.DefaultCacherTTL, opts...)
 }
@@ -1,6 +1,8 @@
 package service
 
-const (
-	ErrTimeout = "request timed out"
-	ErrGetRate = "failed to get exchange rate"
+import "net/http"
+
+var (
+	ErrTimeout = newError("exchange_rate_timeout", http.StatusGatewayTimeout, "exchange rate request timed out")
+	ErrGetRate = newError("exchange_rate_unavailable", http.StatusServiceUnavailable, "failed to get exchange rate")
 )
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"gw-currency-wallet/internal/logger"
+	"gw-currency-wallet/internal/repository"
+	"gw-currency-wallet/pkg"
+
+	"github.com/shopspring/decimal"
+)
+
+var hundred = decimal.NewFromInt(100)
+
+type FeeService struct {
+	r repository.Fee
+}
+
+// Calculate рассчитывает комиссию за обмен в валюте получения: процент спреда
+// от суммы до вычета комиссии плюс фиксированная часть, но не меньше
+// минимальной. Комиссия округляется вверх до places знаков валюты получения.
+func (s *FeeService) Calculate(ctx context.Context, from, to pkg.Currency, gross pkg.Amount, places int32) (pkg.Amount, error) {
+	schedule, err := s.r.Get(ctx, from, to)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return decimal.Zero, err
+	}
+
+	if schedule == nil {
+		return decimal.Zero, nil
+	}
+
+	fee := gross.Mul(schedule.SpreadPercent).Div(hundred).Add(schedule.FixedFee)
+	if fee.LessThan(schedule.MinFee) {
+		fee = schedule.MinFee
+	}
+	fee = fee.RoundUp(places)
+
+	if fee.GreaterThanOrEqual(gross) {
+		logger.L(ctx).Warn(ErrAmountBelowFee.Error())
+		return decimal.Zero, ErrAmountBelowFee
+	}
+
+	return fee, nil
+}
+
+func NewFeeService(r repository.Fee) *FeeService {
+	return &FeeService{
+		r: r,
+	}
+}
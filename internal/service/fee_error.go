@@ -0,0 +1,7 @@
+package service
+
+import "net/http"
+
+var (
+	ErrAmountBelowFee = newError("amount_below_fee", http.StatusBadRequest, "exchange amount does not cover the fee")
+)
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"gw-currency-wallet/internal/db"
+	mock_repository "gw-currency-wallet/internal/repository/mocks"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCalculate_FeeSchedule(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule *db.AppExchangeFee
+		gross    string
+		want     string
+		wantErr  error
+	}{
+		{name: "no schedule", schedule: nil, gross: "100", want: "0"},
+		{name: "spread only", schedule: &db.AppExchangeFee{SpreadPercent: decimal.RequireFromString("0.5")}, gross: "100", want: "0.5"},
+		{name: "spread and fixed", schedule: &db.AppExchangeFee{SpreadPercent: decimal.NewFromInt(1), FixedFee: decimal.RequireFromString("0.25")}, gross: "100", want: "1.25"},
+		{name: "minimum fee", schedule: &db.AppExchangeFee{SpreadPercent: decimal.NewFromInt(1), MinFee: decimal.NewFromInt(2)}, gross: "100", want: "2"},
+		{name: "rounded up", schedule: &db.AppExchangeFee{SpreadPercent: decimal.NewFromInt(1)}, gross: "0.00000101", want: "0.00000002"},
+		{name: "fee exceeds amount", schedule: &db.AppExchangeFee{MinFee: decimal.NewFromInt(5)}, gross: "5", wantErr: ErrAmountBelowFee},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock_repository.NewMockFee(ctrl)
+			srv := NewFeeService(mockRepo)
+
+			mockRepo.EXPECT().Get(t.Context(), "USD", "EUR").Return(tt.schedule, nil)
+
+			fee, err := srv.Calculate(t.Context(), "USD", "EUR", decimal.RequireFromString(tt.gross), 8)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.True(t, decimal.RequireFromString(tt.want).Equal(fee), "got %s", fee)
+		})
+	}
+}
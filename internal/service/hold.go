@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/logger"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/pkg"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	DefaultHoldTTL = 24 * time.Hour
+	MaxHoldTTL     = 30 * 24 * time.Hour
+
+	// holdOperation операция резервирования для ключей идемпотентности
+	holdOperation = "hold"
+)
+
+type holdRequest struct {
+	Currency pkg.Currency  `json:"currency"`
+	Amount   pkg.Amount    `json:"amount"`
+	TTL      time.Duration `json:"ttl"`
+}
+
+type captureRequest struct {
+	HoldID string      `json:"hold_id"`
+	Amount *pkg.Amount `json:"amount"`
+}
+
+// Authorize резервирует сумму на кошельке. Холд уменьшает доступный баланс,
+// но не общий, и перестаёт действовать через ttl (DefaultHoldTTL, если ttl равен нулю).
+func (s *WalletService) Authorize(ctx context.Context, email, idempotencyKey string, currency pkg.Currency, amount pkg.Amount, ttl time.Duration) (*models.Hold, error) {
+	if ttl == 0 {
+		ttl = DefaultHoldTTL
+	}
+
+	if ttl < 0 || ttl > MaxHoldTTL {
+		logger.L(ctx).Warn(ErrInvalidHoldTTL.Error())
+		return nil, ErrInvalidHoldTTL
+	}
+
+	c, tx, err := s.r.WithTx(ctx)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			logger.L(ctx).Error(err.Error())
+		}
+	}()
+
+	request := holdRequest{Currency: currency, Amount: amount, TTL: ttl}
+	hold, err := idempotent(c, s.s.Idempotency, email, idempotencyKey, holdOperation, request, func() (models.Hold, error) {
+		if _, err := s.s.Currency.Validate(c, currency, amount); err != nil {
+			logger.L(ctx).Error(err.Error())
+			return models.Hold{}, err
+		}
+
+		wallet, err := s.lockWallet(c, email, currency)
+		if err != nil {
+			logger.L(ctx).Error(err.Error())
+			return models.Hold{}, err
+		}
+
+		available, err := s.available(c, wallet)
+		if err != nil {
+			logger.L(ctx).Error(err.Error())
+			return models.Hold{}, err
+		}
+
+		if available.LessThan(amount) {
+			logger.L(ctx).Warn(ErrInsufficientBalance.Error())
+			return models.Hold{}, ErrInsufficientBalance
+		}
+
+		row, err := s.r.CreateHold(c, email, currency, amount, time.Now().Add(ttl))
+		if err != nil {
+			logger.L(ctx).Error(err.Error())
+			return models.Hold{}, err
+		}
+
+		return *holdFromRow(row), nil
+	})
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	if err = tx.Commit(c); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return &hold, nil
+}
+
+// Capture списывает зарезервированные средства. Если amount не задан,
+// списывается вся сумма холда; при частичном списании остаток освобождается.
+func (s *WalletService) Capture(ctx context.Context, email, idempotencyKey, id string, amount *pkg.Amount) (*models.Hold, error) {
+	c, tx, err := s.r.WithTx(ctx)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			logger.L(ctx).Error(err.Error())
+		}
+	}()
+
+	request := captureRequest{HoldID: id, Amount: amount}
+	hold, err := idempotent(c, s.s.Idempotency, email, idempotencyKey, models.TransactionCapture, request, func() (models.Hold, error) {
+		row, err := s.activeHold(c, email, id)
+		if err != nil {
+			logger.L(ctx).Error(err.Error())
+			return models.Hold{}, err
+		}
+
+		captured := row.Amount
+		if amount != nil {
+			if _, err = s.s.Currency.Validate(c, row.Currency, *amount); err != nil {
+				logger.L(ctx).Error(err.Error())
+				return models.Hold{}, err
+			}
+
+			if amount.GreaterThan(row.Amount) {
+				logger.L(ctx).Warn(ErrCaptureExceedsHold.Error())
+				return models.Hold{}, ErrCaptureExceedsHold
+			}
+
+			captured = *amount
+		}
+
+		wallet, err := s.lockWallet(c, email, row.Currency)
+		if err != nil {
+			logger.L(ctx).Error(err.Error())
+			return models.Hold{}, err
+		}
+
+		if wallet.Balance.LessThan(captured) {
+			logger.L(ctx).Error(ErrInsufficientBalance.Error())
+			return models.Hold{}, ErrInsufficientBalance
+		}
+
+		if _, err = s.r.Update(c, email, row.Currency, wallet.Balance.Sub(captured)); err != nil {
+			logger.L(ctx).Error(err.Error())
+			return models.Hold{}, err
+		}
+
+		updated, err := s.r.UpdateHoldStatus(c, row.ID, models.HoldCaptured, captured)
+		if err != nil {
+			logger.L(ctx).Error(err.Error())
+			return models.Hold{}, err
+		}
+
+		if _, err = s.s.Ledger.Post(c, models.TransactionCapture,
+			models.Debit(email, row.Currency, captured),
+			models.Credit(models.SystemSettlementAccount, row.Currency, captured),
+		); err != nil {
+			logger.L(ctx).Error(err.Error())
+			return models.Hold{}, err
+		}
+
+		return *holdFromRow(updated), nil
+	})
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	if err = tx.Commit(c); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return &hold, nil
+}
+
+// Void отменяет холд и освобождает зарезервированную сумму
+func (s *WalletService) Void(ctx context.Context, email, id string) (*models.Hold, error) {
+	c, tx, err := s.r.WithTx(ctx)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			logger.L(ctx).Error(err.Error())
+		}
+	}()
+
+	row, err := s.activeHold(c, email, id)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	updated, err := s.r.UpdateHoldStatus(c, row.ID, models.HoldVoided, decimal.Zero)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	if err = tx.Commit(c); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return holdFromRow(updated), nil
+}
+
+// GetBalances возвращает доступный и зарезервированный баланс по каждой валюте
+func (s *WalletService) GetBalances(ctx context.Context, email string) (map[pkg.Currency]models.WalletBalance, error) {
+	wallets, err := s.r.GetAllByEmail(ctx, email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	held, err := s.r.GetHeldAmounts(ctx, email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	heldByCurrency := make(map[pkg.Currency]pkg.Amount, len(held))
+	for _, h := range held {
+		heldByCurrency[h.Currency] = h.Held
+	}
+
+	result := make(map[pkg.Currency]models.WalletBalance, len(wallets))
+	for _, wallet := range wallets {
+		h := heldByCurrency[wallet.Currency]
+		result[wallet.Currency] = models.WalletBalance{
+			Available: wallet.Balance.Sub(h),
+			Held:      h,
+		}
+	}
+
+	return result, nil
+}
+
+// activeHold блокирует холд и проверяет, что его ещё можно списать или отменить
+func (s *WalletService) activeHold(ctx context.Context, email, id string) (*db.AppHold, error) {
+	row, err := s.r.GetHoldForUpdate(ctx, id, email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	if row == nil {
+		logger.L(ctx).Warn(ErrHoldNotFound.Error())
+		return nil, ErrHoldNotFound
+	}
+
+	if row.Status != models.HoldActive {
+		logger.L(ctx).Warn(ErrHoldNotActive.Error())
+		return nil, ErrHoldNotActive
+	}
+
+	if !time.Now().Before(row.ExpiresAt.Time) {
+		logger.L(ctx).Warn(ErrHoldExpired.Error())
+		return nil, ErrHoldExpired
+	}
+
+	return row, nil
+}
+
+// available возвращает баланс заблокированного кошелька за вычетом действующих холдов
+func (s *WalletService) available(ctx context.Context, wallet *db.AppWallet) (pkg.Amount, error) {
+	held, err := s.r.GetHeldAmount(ctx, wallet.Email, wallet.Currency)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return decimal.Zero, err
+	}
+
+	return wallet.Balance.Sub(held), nil
+}
+
+func holdFromRow(row *db.AppHold) *models.Hold {
+	status := row.Status
+	if status == models.HoldActive && !time.Now().Before(row.ExpiresAt.Time) {
+		status = models.HoldExpired
+	}
+
+	return &models.Hold{
+		ID:             row.ID.String(),
+		Currency:       row.Currency,
+		Amount:         row.Amount,
+		CapturedAmount: row.CapturedAmount,
+		Status:         status,
+		ExpiresAt:      row.ExpiresAt.Time,
+		CreatedAt:      row.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,11 @@
+package service
+
+import "net/http"
+
+var (
+	ErrHoldNotFound       = newError("hold_not_found", http.StatusNotFound, "hold not found")
+	ErrHoldExpired        = newError("hold_expired", http.StatusGone, "hold has expired")
+	ErrHoldNotActive      = newError("hold_not_active", http.StatusConflict, "hold has already been captured or voided")
+	ErrCaptureExceedsHold = newError("capture_exceeds_hold", http.StatusBadRequest, "capture amount exceeds held amount")
+	ErrInvalidHoldTTL     = newError("invalid_hold_ttl", http.StatusBadRequest, "invalid hold ttl")
+)
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/models"
+	mock_repository "gw-currency-wallet/internal/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const testHoldID = "0b8f4c1e-2d3a-4f5b-8c6d-7e8f9a0b1c2d"
+
+func testHoldRow(t *testing.T, amount int64, expiresAt time.Time) *db.AppHold {
+	var id pgtype.UUID
+	assert.NoError(t, id.Scan(testHoldID))
+
+	return &db.AppHold{
+		ID:        id,
+		Email:     "user@example.com",
+		Currency:  "USD",
+		Amount:    decimal.NewFromInt(amount),
+		Status:    models.HoldActive,
+		ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	}
+}
+
+func TestAuthorize_InsufficientAvailable_ReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockWallet(ctrl)
+	srv := NewWalletService(mockRepo, &Service{Currency: newTestCurrencyService(ctrl)})
+
+	email := "user@example.com"
+
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+	mockRepo.EXPECT().IsExistCurrency(t.Context(), email, "USD").Return(true, nil)
+	mockRepo.EXPECT().GetForUpdate(t.Context(), email, "USD").Return(&db.AppWallet{
+		Email:    email,
+		Currency: "USD",
+		Balance:  decimal.NewFromInt(100),
+	}, nil)
+	mockRepo.EXPECT().GetHeldAmount(t.Context(), email, "USD").Return(decimal.NewFromInt(80), nil)
+
+	_, err := srv.Authorize(t.Context(), email, "", "USD", decimal.NewFromInt(30), 0)
+
+	assert.ErrorIs(t, err, ErrInsufficientBalance)
+}
+
+func TestAuthorize_InvalidTTL_ReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	srv := NewWalletService(mock_repository.NewMockWallet(ctrl), &Service{})
+
+	_, err := srv.Authorize(t.Context(), "user@example.com", "", "USD", decimal.NewFromInt(30), MaxHoldTTL+time.Hour)
+
+	assert.ErrorIs(t, err, ErrInvalidHoldTTL)
+}
+
+func TestCapture_PartialAmount_DebitsWalletAndReleasesRest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockWallet(ctrl)
+	mockLedger := mock_repository.NewMockLedger(ctrl)
+	srv := NewWalletService(mockRepo, &Service{
+		Currency: newTestCurrencyService(ctrl),
+		Ledger:   NewLedgerService(mockLedger),
+	})
+
+	email := "user@example.com"
+	captured := decimal.NewFromInt(30)
+	row := testHoldRow(t, 50, time.Now().Add(time.Hour))
+
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Commit(gomock.Any()).Return(nil)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+	mockRepo.EXPECT().GetHoldForUpdate(t.Context(), testHoldID, email).Return(row, nil)
+	mockRepo.EXPECT().IsExistCurrency(t.Context(), email, "USD").Return(true, nil)
+	mockRepo.EXPECT().GetForUpdate(t.Context(), email, "USD").Return(&db.AppWallet{
+		Email:    email,
+		Currency: "USD",
+		Balance:  decimal.NewFromInt(100),
+	}, nil)
+	mockRepo.EXPECT().Update(t.Context(), email, "USD", decimal.NewFromInt(70)).Return(nil, nil)
+
+	capturedRow := *row
+	capturedRow.Status = models.HoldCaptured
+	capturedRow.CapturedAmount = captured
+	mockRepo.EXPECT().UpdateHoldStatus(t.Context(), row.ID, models.HoldCaptured, captured).Return(&capturedRow, nil)
+
+	mockLedger.EXPECT().CreateTransaction(t.Context(), models.TransactionCapture).Return(&db.AppTransaction{ID: 1}, nil)
+	mockLedger.EXPECT().CreateEntry(t.Context(), int64(1), email, "USD", models.EntryDebit, captured).Return(nil)
+	mockLedger.EXPECT().CreateEntry(t.Context(), int64(1), models.SystemSettlementAccount, "USD", models.EntryCredit, captured).Return(nil)
+
+	hold, err := srv.Capture(t.Context(), email, "", testHoldID, &captured)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.HoldCaptured, hold.Status)
+	assert.True(t, captured.Equal(hold.CapturedAmount))
+}
+
+func TestCapture_AmountAboveHold_ReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockWallet(ctrl)
+	srv := NewWalletService(mockRepo, &Service{Currency: newTestCurrencyService(ctrl)})
+
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+	mockRepo.EXPECT().GetHoldForUpdate(t.Context(), testHoldID, "user@example.com").Return(testHoldRow(t, 50, time.Now().Add(time.Hour)), nil)
+
+	amount := decimal.NewFromInt(51)
+	_, err := srv.Capture(t.Context(), "user@example.com", "", testHoldID, &amount)
+
+	assert.ErrorIs(t, err, ErrCaptureExceedsHold)
+}
+
+func TestVoid_ExpiredHold_ReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockWallet(ctrl)
+	srv := NewWalletService(mockRepo, &Service{})
+
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+	mockRepo.EXPECT().GetHoldForUpdate(t.Context(), testHoldID, "user@example.com").Return(testHoldRow(t, 50, time.Now().Add(-time.Minute)), nil)
+
+	_, err := srv.Void(t.Context(), "user@example.com", testHoldID)
+
+	assert.ErrorIs(t, err, ErrHoldExpired)
+}
+
+func TestGetBalances_SplitsAvailableAndHeld(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockWallet(ctrl)
+	srv := NewWalletService(mockRepo, &Service{})
+
+	email := "user@example.com"
+
+	mockRepo.EXPECT().GetAllByEmail(t.Context(), email).Return([]db.AppWallet{
+		{Email: email, Currency: "USD", Balance: decimal.NewFromInt(100)},
+		{Email: email, Currency: "EUR", Balance: decimal.NewFromInt(5)},
+	}, nil)
+	mockRepo.EXPECT().GetHeldAmounts(t.Context(), email).Return([]db.GetHeldAmountsByEmailRow{
+		{Currency: "USD", Held: decimal.NewFromInt(40)},
+	}, nil)
+
+	balances, err := srv.GetBalances(t.Context(), email)
+
+	assert.NoError(t, err)
+	assert.True(t, decimal.NewFromInt(60).Equal(balances["USD"].Available))
+	assert.True(t, decimal.NewFromInt(40).Equal(balances["USD"].Held))
+	assert.True(t, decimal.NewFromInt(5).Equal(balances["EUR"].Available))
+	assert.True(t, balances["EUR"].Held.IsZero())
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"gw-currency-wallet/internal/logger"
+	"gw-currency-wallet/internal/repository"
+	"strings"
+)
+
+const maxIdempotencyKeyLength = 255
+
+type IdempotencyService struct {
+	r repository.Idempotency
+}
+
+// Begin закрепляет ключ за запросом в текущей транзакции. Если ключ уже
+// использовался с тем же запросом, возвращается сохранённый ответ.
+// Конкурентный запрос с тем же ключом ждёт фиксации первой транзакции
+// на уникальном индексе и после этого получает её ответ.
+func (s *IdempotencyService) Begin(ctx context.Context, email, key, operation string, request any) ([]byte, error) {
+	if strings.TrimSpace(key) == "" || len(key) > maxIdempotencyKeyLength {
+		logger.L(ctx).Warn(ErrInvalidIdempotencyKey.Error())
+		return nil, ErrInvalidIdempotencyKey
+	}
+
+	requestHash, err := hashRequest(operation, request)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	claimed, err := s.r.Claim(ctx, email, key, operation, requestHash)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	if claimed {
+		return nil, nil
+	}
+
+	stored, err := s.r.Get(ctx, email, key)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	if stored == nil || stored.Response == nil {
+		logger.L(ctx).Warn(ErrIdempotencyKeyInProgress.Error())
+		return nil, ErrIdempotencyKeyInProgress
+	}
+
+	if stored.Operation != operation || stored.RequestHash != requestHash {
+		logger.L(ctx).Warn(ErrIdempotencyKeyMismatch.Error())
+		return nil, ErrIdempotencyKeyMismatch
+	}
+
+	return stored.Response, nil
+}
+
+func (s *IdempotencyService) Complete(ctx context.Context, email, key string, response any) error {
+	data, err := json.Marshal(response)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	if err = s.r.SaveResponse(ctx, email, key, data); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func NewIdempotencyService(r repository.Idempotency) *IdempotencyService {
+	return &IdempotencyService{
+		r: r,
+	}
+}
+
+// idempotent выполняет op не более одного раза для пары (email, key).
+// Должна вызываться внутри транзакции операции: ключ и ответ сохраняются
+// атомарно с изменением балансов, а при ошибке op ключ освобождается.
+func idempotent[T any](ctx context.Context, i Idempotency, email, key, operation string, request any, op func() (T, error)) (T, error) {
+	var result T
+
+	if key == "" {
+		return op()
+	}
+
+	stored, err := i.Begin(ctx, email, key, operation, request)
+	if err != nil {
+		return result, err
+	}
+
+	if stored != nil {
+		err = json.Unmarshal(stored, &result)
+		return result, err
+	}
+
+	result, err = op()
+	if err != nil {
+		return result, err
+	}
+
+	if err = i.Complete(ctx, email, key, result); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+func hashRequest(operation string, request any) (string, error) {
+	var buf bytes.Buffer
+	buf.WriteString(operation)
+	buf.WriteByte(0)
+
+	if err := json.NewEncoder(&buf).Encode(request); err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,9 @@
+package service
+
+import "net/http"
+
+var (
+	ErrInvalidIdempotencyKey    = newError("invalid_idempotency_key", http.StatusBadRequest, "idempotency key must be between 1 and 255 characters")
+	ErrIdempotencyKeyMismatch   = newError("idempotency_key_mismatch", http.StatusConflict, "idempotency key was already used with a different request")
+	ErrIdempotencyKeyInProgress = newError("idempotency_key_in_progress", http.StatusConflict, "request with this idempotency key is still in progress")
+)
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/models"
+	mock_repository "gw-currency-wallet/internal/repository/mocks"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBegin_NewKey_ClaimsKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockIdempotency(ctrl)
+	srv := NewIdempotencyService(mockRepo)
+
+	request := balanceRequest{Currency: "USD", Amount: decimal.NewFromInt(100)}
+	hash, err := hashRequest(models.TransactionDeposit, request)
+	assert.NoError(t, err)
+
+	mockRepo.EXPECT().Claim(t.Context(), "user@example.com", "key-1", models.TransactionDeposit, hash).Return(true, nil)
+
+	stored, err := srv.Begin(t.Context(), "user@example.com", "key-1", models.TransactionDeposit, request)
+
+	assert.NoError(t, err)
+	assert.Nil(t, stored)
+}
+
+func TestBegin_SameRequest_ReturnsStoredResponse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockIdempotency(ctrl)
+	srv := NewIdempotencyService(mockRepo)
+
+	request := balanceRequest{Currency: "USD", Amount: decimal.NewFromInt(100)}
+	hash, err := hashRequest(models.TransactionDeposit, request)
+	assert.NoError(t, err)
+
+	mockRepo.EXPECT().Claim(t.Context(), "user@example.com", "key-1", models.TransactionDeposit, hash).Return(false, nil)
+	mockRepo.EXPECT().Get(t.Context(), "user@example.com", "key-1").Return(&db.AppIdempotencyKey{
+		Operation:   models.TransactionDeposit,
+		RequestHash: hash,
+		Response:    []byte(`{"USD":"100"}`),
+	}, nil)
+
+	stored, err := srv.Begin(t.Context(), "user@example.com", "key-1", models.TransactionDeposit, request)
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"USD":"100"}`, string(stored))
+}
+
+func TestBegin_DifferentRequest_ReturnsMismatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockIdempotency(ctrl)
+	srv := NewIdempotencyService(mockRepo)
+
+	// Тот же ключ, но другая сумма
+	request := balanceRequest{Currency: "USD", Amount: decimal.NewFromInt(200)}
+	original, err := hashRequest(models.TransactionDeposit, balanceRequest{Currency: "USD", Amount: decimal.NewFromInt(100)})
+	assert.NoError(t, err)
+
+	mockRepo.EXPECT().Claim(t.Context(), "user@example.com", "key-1", models.TransactionDeposit, gomock.Any()).Return(false, nil)
+	mockRepo.EXPECT().Get(t.Context(), "user@example.com", "key-1").Return(&db.AppIdempotencyKey{
+		Operation:   models.TransactionDeposit,
+		RequestHash: original,
+		Response:    []byte(`{"USD":"100"}`),
+	}, nil)
+
+	_, err = srv.Begin(t.Context(), "user@example.com", "key-1", models.TransactionDeposit, request)
+
+	assert.ErrorIs(t, err, ErrIdempotencyKeyMismatch)
+}
+
+func TestBegin_TooLongKey_ReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockIdempotency(ctrl)
+	srv := NewIdempotencyService(mockRepo)
+
+	key := make([]byte, maxIdempotencyKeyLength+1)
+	for i := range key {
+		key[i] = 'k'
+	}
+
+	_, err := srv.Begin(t.Context(), "user@example.com", string(key), models.TransactionDeposit, nil)
+
+	assert.ErrorIs(t, err, ErrInvalidIdempotencyKey)
+}
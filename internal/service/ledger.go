@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"gw-currency-wallet/internal/logger"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/repository"
+	"gw-currency-wallet/pkg"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	DefaultHistoryPageSize = 20
+	MaxHistoryPageSize     = 100
+)
+
+type LedgerService struct {
+	r repository.Ledger
+}
+
+// Post записывает сбалансированную проводку. Вызывается внутри транзакции,
+// открытой вызывающей стороной, чтобы записи журнала и изменение балансов
+// фиксировались атомарно.
+func (s *LedgerService) Post(ctx context.Context, txType models.TransactionType, entries ...models.LedgerEntry) (int64, error) {
+	if err := validateEntries(entries); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return 0, err
+	}
+
+	transaction, err := s.r.CreateTransaction(ctx, txType)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return 0, err
+	}
+
+	for _, e := range entries {
+		if err = s.r.CreateEntry(ctx, transaction.ID, e.Account, e.Currency, e.Direction, e.Amount); err != nil {
+			logger.L(ctx).Error(err.Error())
+			return 0, err
+		}
+	}
+
+	return transaction.ID, nil
+}
+
+func (s *LedgerService) History(ctx context.Context, account string, filter models.TransactionFilter, cursor string, limit int) (*models.TransactionPage, error) {
+	switch filter.Type {
+	case "", models.TransactionDeposit, models.TransactionWithdraw, models.TransactionExchange, models.TransactionTransfer, models.TransactionCapture, models.TransactionAdjustment, models.TransactionOpeningBalance:
+	default:
+		logger.L(ctx).Warn(ErrInvalidTransactionType.Error())
+		return nil, ErrInvalidTransactionType
+	}
+
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		logger.L(ctx).Warn(ErrInvalidTimeRange.Error())
+		return nil, ErrInvalidTimeRange
+	}
+
+	var position *models.TransactionCursor
+	if cursor != "" {
+		decoded, err := decodeCursor(cursor)
+		if err != nil {
+			logger.L(ctx).Warn(err.Error())
+			return nil, ErrInvalidCursor
+		}
+		position = decoded
+	}
+
+	if limit <= 0 {
+		limit = DefaultHistoryPageSize
+	}
+	if limit > MaxHistoryPageSize {
+		limit = MaxHistoryPageSize
+	}
+
+	// запрашиваем на одну запись больше, чтобы понять, есть ли следующая страница
+	rows, err := s.r.ListByAccount(ctx, account, filter, position, int32(limit+1))
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	page := &models.TransactionPage{
+		Transactions: make([]models.Transaction, 0, limit),
+	}
+
+	if len(rows) > limit {
+		rows = rows[:limit]
+		last := rows[len(rows)-1]
+		page.NextCursor = encodeCursor(models.TransactionCursor{
+			CreatedAt: last.CreatedAt.Time,
+			ID:        last.ID,
+		})
+	}
+
+	if len(rows) == 0 {
+		return page, nil
+	}
+
+	ids := make([]int64, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+
+	entries, err := s.r.GetEntriesByTransactions(ctx, account, ids)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	byTransaction := make(map[int64][]models.TransactionEntry, len(rows))
+	for _, e := range entries {
+		amount := e.Amount
+		if e.Direction == models.EntryDebit {
+			amount = amount.Neg()
+		}
+		byTransaction[e.TransactionID] = append(byTransaction[e.TransactionID], models.TransactionEntry{
+			Currency: e.Currency,
+			Amount:   amount,
+		})
+	}
+
+	for _, row := range rows {
+		page.Transactions = append(page.Transactions, models.Transaction{
+			ID:        row.ID,
+			Type:      row.Type,
+			CreatedAt: row.CreatedAt.Time,
+			Entries:   byTransaction[row.ID],
+		})
+	}
+
+	return page, nil
+}
+
+func NewLedgerService(r repository.Ledger) *LedgerService {
+	return &LedgerService{
+		r: r,
+	}
+}
+
+func validateEntries(entries []models.LedgerEntry) error {
+	if len(entries) < 2 {
+		return ErrUnbalancedTransaction
+	}
+
+	totals := make(map[pkg.Currency]decimal.Decimal)
+	for _, e := range entries {
+		if !e.Amount.IsPositive() {
+			return ErrInvalidLedgerEntry
+		}
+
+		switch e.Direction {
+		case models.EntryDebit:
+			totals[e.Currency] = totals[e.Currency].Add(e.Amount)
+		case models.EntryCredit:
+			totals[e.Currency] = totals[e.Currency].Sub(e.Amount)
+		default:
+			return ErrInvalidLedgerEntry
+		}
+	}
+
+	for _, total := range totals {
+		if !total.IsZero() {
+			return ErrUnbalancedTransaction
+		}
+	}
+
+	return nil
+}
+
+func encodeCursor(cursor models.TransactionCursor) string {
+	raw := fmt.Sprintf("%d:%d", cursor.CreatedAt.UnixNano(), cursor.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (*models.TransactionCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	nanos, id, found := strings.Cut(string(raw), ":")
+	if !found {
+		return nil, ErrInvalidCursor
+	}
+
+	createdAt, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	transactionID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TransactionCursor{
+		CreatedAt: time.Unix(0, createdAt),
+		ID:        transactionID,
+	}, nil
+}
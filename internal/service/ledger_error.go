@@ -0,0 +1,15 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+)
+
+var (
+	// Нарушения инвариантов журнала не показываются клиенту и отвечают 500
+	ErrUnbalancedTransaction  = errors.New("ledger transaction is not balanced")
+	ErrInvalidLedgerEntry     = errors.New("invalid ledger entry")
+	ErrInvalidCursor          = newError("invalid_cursor", http.StatusBadRequest, "invalid pagination cursor")
+	ErrInvalidTransactionType = newError("invalid_transaction_type", http.StatusBadRequest, "unknown transaction type")
+	ErrInvalidTimeRange       = newError("invalid_time_range", http.StatusBadRequest, "time range start must be before its end")
+)
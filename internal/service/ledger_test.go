@@ -0,0 +1,136 @@
+package service
+
+import (
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/models"
+	mock_repository "gw-currency-wallet/internal/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPost_BalancedEntries_WritesTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockLedger(ctrl)
+	srv := NewLedgerService(mockRepo)
+
+	amount := decimal.NewFromInt(10)
+	exchanged := decimal.RequireFromString("9.2")
+
+	mockRepo.EXPECT().CreateTransaction(t.Context(), models.TransactionExchange).Return(&db.AppTransaction{ID: 7}, nil)
+	mockRepo.EXPECT().CreateEntry(t.Context(), int64(7), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).Times(4)
+
+	id, err := srv.Post(t.Context(), models.TransactionExchange,
+		models.Debit("user@example.com", "USD", amount),
+		models.Credit(models.SystemExchangeAccount, "USD", amount),
+		models.Debit(models.SystemExchangeAccount, "EUR", exchanged),
+		models.Credit("user@example.com", "EUR", exchanged),
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), id)
+}
+
+func TestPost_UnbalancedEntries_ReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockLedger(ctrl)
+	srv := NewLedgerService(mockRepo)
+
+	// Суммы сбалансированы в целом, но не в разрезе валют
+	_, err := srv.Post(t.Context(), models.TransactionExchange,
+		models.Debit("user@example.com", "USD", decimal.NewFromInt(10)),
+		models.Credit("user@example.com", "EUR", decimal.NewFromInt(10)),
+	)
+
+	assert.ErrorIs(t, err, ErrUnbalancedTransaction)
+}
+
+func TestPost_NonPositiveAmount_ReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockLedger(ctrl)
+	srv := NewLedgerService(mockRepo)
+
+	_, err := srv.Post(t.Context(), models.TransactionDeposit,
+		models.Debit(models.SystemCashAccount, "USD", decimal.Zero),
+		models.Credit("user@example.com", "USD", decimal.Zero),
+	)
+
+	assert.ErrorIs(t, err, ErrInvalidLedgerEntry)
+}
+
+func TestHistory_MoreRowsThanLimit_ReturnsNextCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockLedger(ctrl)
+	srv := NewLedgerService(mockRepo)
+
+	email := "user@example.com"
+	now := time.Now()
+
+	mockRepo.EXPECT().ListByAccount(t.Context(), email, models.TransactionFilter{}, nil, int32(3)).Return([]db.AppTransaction{
+		{ID: 3, Type: models.TransactionExchange, CreatedAt: pgtype.Timestamptz{Time: now, Valid: true}},
+		{ID: 2, Type: models.TransactionDeposit, CreatedAt: pgtype.Timestamptz{Time: now.Add(-time.Minute), Valid: true}},
+		{ID: 1, Type: models.TransactionDeposit, CreatedAt: pgtype.Timestamptz{Time: now.Add(-time.Hour), Valid: true}},
+	}, nil)
+
+	mockRepo.EXPECT().GetEntriesByTransactions(t.Context(), email, []int64{3, 2}).Return([]db.AppLedgerEntry{
+		{TransactionID: 2, Account: email, Currency: "USD", Direction: models.EntryCredit, Amount: decimal.NewFromInt(100)},
+		{TransactionID: 3, Account: email, Currency: "USD", Direction: models.EntryDebit, Amount: decimal.NewFromInt(10)},
+		{TransactionID: 3, Account: email, Currency: "EUR", Direction: models.EntryCredit, Amount: decimal.RequireFromString("9.2")},
+	}, nil)
+
+	page, err := srv.History(t.Context(), email, models.TransactionFilter{}, "", 2)
+
+	assert.NoError(t, err)
+	assert.Len(t, page.Transactions, 2)
+	assert.Equal(t, int64(3), page.Transactions[0].ID)
+	assert.Equal(t, "-10", page.Transactions[0].Entries[0].Amount.String())
+	assert.Equal(t, "9.2", page.Transactions[0].Entries[1].Amount.String())
+	assert.NotEmpty(t, page.NextCursor)
+
+	cursor, err := decodeCursor(page.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), cursor.ID)
+	assert.True(t, cursor.CreatedAt.Equal(now.Add(-time.Minute)))
+}
+
+func TestHistory_InvalidCursor_ReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockLedger(ctrl)
+	srv := NewLedgerService(mockRepo)
+
+	_, err := srv.History(t.Context(), "user@example.com", models.TransactionFilter{}, "not-a-cursor", 0)
+
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestHistory_OpeningBalanceFilter_IsAccepted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockLedger(ctrl)
+	srv := NewLedgerService(mockRepo)
+
+	email := "user@example.com"
+	filter := models.TransactionFilter{Type: models.TransactionOpeningBalance}
+
+	mockRepo.EXPECT().ListByAccount(t.Context(), email, filter, nil, int32(DefaultHistoryPageSize+1)).Return(nil, nil)
+
+	page, err := srv.History(t.Context(), email, filter, "", 0)
+
+	assert.NoError(t, err)
+	assert.Empty(t, page.Transactions)
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gw-currency-wallet/config"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/logger"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/repository"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultLoginMaxAttempts      = 5
+	DefaultLoginMaxAttemptsPerIP = 20
+	DefaultLoginLockoutDuration  = time.Minute * 15
+
+	loginBackoffBase = time.Second
+	loginBackoffMax  = time.Minute
+
+	lockoutEventsLimit = 50
+)
+
+// LoginGuardService ограничивает подбор пароля. Неудачные попытки считаются отдельно
+// по аккаунту и по IP-адресу: после каждой неудачи следующая попытка откладывается
+// с экспоненциально растущей задержкой, а по достижении порога вход блокируется
+// на LoginLockoutDuration. Состояние хранится в базе, поэтому общее для всех экземпляров.
+type LoginGuardService struct {
+	r                repository.LoginAttempt
+	maxAttempts      int
+	maxAttemptsPerIP int
+	lockoutDuration  time.Duration
+}
+
+// Check возвращает *LoginThrottledError, если вход для аккаунта или IP-адреса временно запрещён
+func (s *LoginGuardService) Check(ctx context.Context, account, ip string) error {
+	var retryAfter time.Duration
+
+	for _, key := range loginKeys(account, ip) {
+		attempt, err := s.r.Get(ctx, key.scope, key.subject)
+		if err != nil {
+			logger.L(ctx).Error(err.Error())
+			return err
+		}
+
+		if attempt == nil || !attempt.LockedUntil.Valid {
+			continue
+		}
+
+		if wait := time.Until(attempt.LockedUntil.Time); wait > retryAfter {
+			retryAfter = wait
+		}
+	}
+
+	if retryAfter > 0 {
+		logger.L(ctx).Warn(ErrTooManyLoginAttempts.Error(), zap.String("account", account), zap.String("ip", ip))
+		return &LoginThrottledError{RetryAfter: retryAfter}
+	}
+
+	return nil
+}
+
+// Fail учитывает неудачную попытку входа
+func (s *LoginGuardService) Fail(ctx context.Context, account, ip string) error {
+	c, tx, err := s.r.WithTx(ctx)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			logger.L(ctx).Error(err.Error())
+		}
+	}()
+
+	now := time.Now()
+
+	for _, key := range loginKeys(account, ip) {
+		attempt, err := s.r.RecordFailure(c, key.scope, key.subject, now.Add(-s.lockoutDuration))
+		if err != nil {
+			logger.L(ctx).Error(err.Error())
+			return err
+		}
+
+		if err = s.throttle(c, attempt, now); err != nil {
+			logger.L(ctx).Error(err.Error())
+			return err
+		}
+	}
+
+	if err = tx.Commit(c); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Succeed сбрасывает счётчик аккаунта. Счётчик IP-адреса не сбрасывается, чтобы
+// вход в свой аккаунт не обнулял подбор паролей к чужим.
+func (s *LoginGuardService) Succeed(ctx context.Context, account string) error {
+	if err := s.r.Delete(ctx, models.LoginScopeAccount, account); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Unlock снимает блокировку и отмечает в журнале, кто её снял. Собственной транзакции
+// не открывает и должна вызываться внутри транзакции, в которой снятие записывается
+// в журнал действий администраторов.
+func (s *LoginGuardService) Unlock(ctx context.Context, scope models.LoginScope, subject, unlockedBy string) error {
+	if err := s.r.Delete(ctx, scope, subject); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	if err := s.r.MarkUnlocked(ctx, scope, subject, unlockedBy); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Lockouts возвращает последние блокировки, от новых к старым
+func (s *LoginGuardService) Lockouts(ctx context.Context, scope models.LoginScope, subject string) ([]models.LockoutEvent, error) {
+	rows, err := s.r.ListLockoutEvents(ctx, scope, subject, lockoutEventsLimit)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	events := make([]models.LockoutEvent, 0, len(rows))
+	for _, row := range rows {
+		event := models.LockoutEvent{
+			ID:          row.ID,
+			Scope:       row.Scope,
+			Subject:     row.Subject,
+			Failures:    int(row.Failures),
+			LockedUntil: row.LockedUntil.Time,
+			CreatedAt:   row.CreatedAt.Time,
+			UnlockedBy:  row.UnlockedBy.String,
+		}
+		if row.UnlockedAt.Valid {
+			event.UnlockedAt = &row.UnlockedAt.Time
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+func NewLoginGuardService(r repository.LoginAttempt, cfg *config.AuthConfig) *LoginGuardService {
+	s := &LoginGuardService{
+		r:                r,
+		maxAttempts:      cfg.LoginMaxAttempts,
+		maxAttemptsPerIP: cfg.LoginMaxAttemptsPerIP,
+		lockoutDuration:  cfg.LoginLockoutDuration,
+	}
+
+	if s.maxAttempts <= 0 {
+		s.maxAttempts = DefaultLoginMaxAttempts
+	}
+	if s.maxAttemptsPerIP <= 0 {
+		s.maxAttemptsPerIP = DefaultLoginMaxAttemptsPerIP
+	}
+	if s.lockoutDuration <= 0 {
+		s.lockoutDuration = DefaultLoginLockoutDuration
+	}
+
+	return s
+}
+
+// throttle назначает задержку до следующей попытки, а по достижении порога блокирует вход
+// и сбрасывает счётчик
+func (s *LoginGuardService) throttle(ctx context.Context, attempt *db.AppLoginAttempt, now time.Time) error {
+	threshold := s.maxAttempts
+	if attempt.Scope == models.LoginScopeIP {
+		threshold = s.maxAttemptsPerIP
+	}
+
+	if int(attempt.Failures) < threshold {
+		lockedUntil := now.Add(loginBackoff(int(attempt.Failures)))
+		return s.r.SetLockedUntil(ctx, attempt.Scope, attempt.Subject, lockedUntil, attempt.Failures)
+	}
+
+	lockedUntil := now.Add(s.lockoutDuration)
+	if err := s.r.SetLockedUntil(ctx, attempt.Scope, attempt.Subject, lockedUntil, 0); err != nil {
+		return err
+	}
+
+	if err := s.r.CreateLockoutEvent(ctx, attempt.Scope, attempt.Subject, attempt.Failures, lockedUntil); err != nil {
+		return err
+	}
+
+	logger.L(ctx).Warn("login locked out",
+		zap.String("scope", attempt.Scope),
+		zap.String("subject", attempt.Subject),
+		zap.Time("locked_until", lockedUntil),
+	)
+
+	return nil
+}
+
+// loginBackoff задержка после failures неудачных попыток подряд: 1s, 2s, 4s, ... но не больше минуты
+func loginBackoff(failures int) time.Duration {
+	if failures <= 0 {
+		return 0
+	}
+
+	delay := loginBackoffBase
+	for i := 1; i < failures && delay < loginBackoffMax; i++ {
+		delay *= 2
+	}
+
+	return min(delay, loginBackoffMax)
+}
+
+type loginKey struct {
+	scope   models.LoginScope
+	subject string
+}
+
+// loginKeys всегда возвращает ключи в одном порядке, чтобы параллельные транзакции
+// блокировали строки одинаково
+func loginKeys(account, ip string) []loginKey {
+	keys := []loginKey{{scope: models.LoginScopeAccount, subject: account}}
+	if ip != "" {
+		keys = append(keys, loginKey{scope: models.LoginScopeIP, subject: ip})
+	}
+	return keys
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"net/http"
+	"time"
+)
+
+var ErrTooManyLoginAttempts = newError("too_many_login_attempts", http.StatusTooManyRequests, "too many login attempts, try again later")
+
+// LoginThrottledError сообщает, через сколько можно повторить попытку входа
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return ErrTooManyLoginAttempts.Error()
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	return ErrTooManyLoginAttempts
+}
+
+func (e *LoginThrottledError) RetryAfterSeconds() int {
+	return retryAfterSeconds(e.RetryAfter)
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"gw-currency-wallet/config"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/models"
+	mock_repository "gw-currency-wallet/internal/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestLoginBackoff_DoublesUpToLimit(t *testing.T) {
+	assert.Equal(t, time.Duration(0), loginBackoff(0))
+	assert.Equal(t, time.Second, loginBackoff(1))
+	assert.Equal(t, 2*time.Second, loginBackoff(2))
+	assert.Equal(t, 8*time.Second, loginBackoff(4))
+	assert.Equal(t, time.Minute, loginBackoff(30))
+}
+
+func TestCheck_LockedIP_ReturnsRetryAfter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockLoginAttempt(ctrl)
+	srv := NewLoginGuardService(mockRepo, &config.AuthConfig{})
+
+	mockRepo.EXPECT().Get(t.Context(), models.LoginScopeAccount, "alice@example.com").Return(nil, nil)
+	mockRepo.EXPECT().Get(t.Context(), models.LoginScopeIP, "10.0.0.1").Return(&db.AppLoginAttempt{
+		Scope:       models.LoginScopeIP,
+		Subject:     "10.0.0.1",
+		LockedUntil: pgtype.Timestamptz{Time: time.Now().Add(10 * time.Minute), Valid: true},
+	}, nil)
+
+	err := srv.Check(t.Context(), "alice@example.com", "10.0.0.1")
+
+	var throttled *LoginThrottledError
+	assert.ErrorIs(t, err, ErrTooManyLoginAttempts)
+	assert.ErrorAs(t, err, &throttled)
+	assert.Greater(t, throttled.RetryAfter, 9*time.Minute)
+}
+
+func TestCheck_ExpiredBackoff_Allows(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockLoginAttempt(ctrl)
+	srv := NewLoginGuardService(mockRepo, &config.AuthConfig{})
+
+	mockRepo.EXPECT().Get(t.Context(), models.LoginScopeAccount, "alice@example.com").Return(&db.AppLoginAttempt{
+		Scope:       models.LoginScopeAccount,
+		Subject:     "alice@example.com",
+		Failures:    2,
+		LockedUntil: pgtype.Timestamptz{Time: time.Now().Add(-time.Second), Valid: true},
+	}, nil)
+
+	assert.NoError(t, srv.Check(t.Context(), "alice@example.com", ""))
+}
+
+func TestFail_ThresholdReached_LocksAndRecordsEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockLoginAttempt(ctrl)
+	srv := NewLoginGuardService(mockRepo, &config.AuthConfig{LoginMaxAttempts: 3, LoginLockoutDuration: time.Hour})
+
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+	mockTx.EXPECT().Commit(gomock.Any()).Return(nil)
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+	mockRepo.EXPECT().RecordFailure(gomock.Any(), models.LoginScopeAccount, "alice@example.com", gomock.Any()).
+		Return(&db.AppLoginAttempt{Scope: models.LoginScopeAccount, Subject: "alice@example.com", Failures: 3}, nil)
+
+	var lockedUntil time.Time
+	mockRepo.EXPECT().SetLockedUntil(gomock.Any(), models.LoginScopeAccount, "alice@example.com", gomock.Any(), int32(0)).
+		DoAndReturn(func(_ any, _, _ string, until time.Time, _ int32) error {
+			lockedUntil = until
+			return nil
+		})
+	mockRepo.EXPECT().CreateLockoutEvent(gomock.Any(), models.LoginScopeAccount, "alice@example.com", int32(3), gomock.Any()).Return(nil)
+
+	err := srv.Fail(t.Context(), "alice@example.com", "")
+
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), lockedUntil, time.Minute)
+}
+
+func TestUnlock_ClearsAttemptsAndMarksEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockLoginAttempt(ctrl)
+	srv := NewLoginGuardService(mockRepo, &config.AuthConfig{})
+
+	mockRepo.EXPECT().Delete(gomock.Any(), models.LoginScopeAccount, "alice@example.com").Return(nil)
+	mockRepo.EXPECT().MarkUnlocked(gomock.Any(), models.LoginScopeAccount, "alice@example.com", "admin@example.com").Return(nil)
+
+	err := srv.Unlock(t.Context(), models.LoginScopeAccount, "alice@example.com", "admin@example.com")
+
+	assert.NoError(t, err)
+}
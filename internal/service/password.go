@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"gw-currency-wallet/config"
+	"gw-currency-wallet/internal/logger"
+	"gw-currency-wallet/internal/mailer"
+	"gw-currency-wallet/internal/repository"
+	"net/url"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultPasswordResetTTL = time.Hour
+
+	resetTokenBytes = 32
+)
+
+// PasswordService сброс забытого пароля по одноразовой ссылке из письма
+type PasswordService struct {
+	r        repository.PasswordReset
+	m        mailer.Mailer
+	resetURL string
+	s        *Service
+}
+
+// Forgot отправляет письмо со ссылкой для сброса пароля. Для неизвестного email
+// ничего не происходит, а ошибка отправки письма только логируется, чтобы по ответу
+// нельзя было узнать, зарегистрирован ли адрес.
+func (s *PasswordService) Forgot(ctx context.Context, email string) error {
+	account, err := s.s.Account.Find(ctx, email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	if account == nil {
+		logger.L(ctx).Warn("password reset requested for unknown account", zap.String("login", email))
+		return nil
+	}
+
+	raw := make([]byte, resetTokenBytes)
+	if _, err = rand.Read(raw); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	if err = s.r.CreateToken(ctx, hashToken(token), account.Email, time.Now().Add(DefaultPasswordResetTTL)); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	if err = s.m.Send(ctx, mailer.Message{
+		To:      account.Email,
+		Subject: "Password reset",
+		Body:    s.resetBody(token),
+	}); err != nil {
+		logger.L(ctx).Error(err.Error(), zap.String("email", account.Email))
+	}
+
+	return nil
+}
+
+// Reset устанавливает новый пароль по токену из письма, пароль проверяется по политике паролей. Все токены сброса и все сессии
+// аккаунта после этого перестают действовать.
+func (s *PasswordService) Reset(ctx context.Context, token, password string) error {
+	c, tx, err := s.r.WithTx(ctx)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			logger.L(ctx).Error(err.Error())
+		}
+	}()
+
+	resetToken, err := s.r.GetTokenForUpdate(c, hashToken(token))
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	if resetToken == nil || resetToken.UsedAt.Valid || !time.Now().Before(resetToken.ExpiresAt.Time) {
+		logger.L(ctx).Warn(ErrResetTokenInvalid.Error())
+		return ErrResetTokenInvalid
+	}
+
+	account, err := s.s.Account.Find(c, resetToken.Email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	var username string
+	if account != nil {
+		username = account.Username
+	}
+
+	if err = s.s.Auth.ValidatePassword(password, username); err != nil {
+		logger.L(ctx).Warn(err.Error())
+		return err
+	}
+
+	passwordHash, err := s.s.Auth.HashPassword(password)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	if err = s.r.UpdatePassword(c, resetToken.Email, passwordHash); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	if err = s.r.UseTokens(c, resetToken.Email); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	// Выполняется в той же транзакции, что и смена пароля
+	if err = s.s.Session.RevokeAll(c, resetToken.Email); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	if err = tx.Commit(c); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func NewPasswordService(r repository.PasswordReset, m mailer.Mailer, cfg *config.AuthConfig, s *Service) *PasswordService {
+	return &PasswordService{
+		r:        r,
+		m:        m,
+		resetURL: cfg.PasswordResetURL,
+		s:        s,
+	}
+}
+
+func (s *PasswordService) resetBody(token string) string {
+	ttl := fmt.Sprintf("%d minutes", int(DefaultPasswordResetTTL/time.Minute))
+
+	if s.resetURL == "" {
+		return fmt.Sprintf("Use this token to reset your password: %s\n\nThe token expires in %s. If you did not request a password reset, ignore this email.", token, ttl)
+	}
+
+	link := s.resetURL + "?token=" + url.QueryEscape(token)
+	return fmt.Sprintf("Follow the link to reset your password: %s\n\nThe link expires in %s. If you did not request a password reset, ignore this email.", link, ttl)
+}
@@ -0,0 +1,10 @@
+package service
+
+import "net/http"
+
+var (
+	ErrResetTokenInvalid        = newError("reset_token_invalid", http.StatusBadRequest, "invalid or expired password reset token")
+	ErrPasswordTooShort         = newError("password_too_short", http.StatusBadRequest, "password is too short")
+	ErrPasswordBreached         = newError("password_breached", http.StatusBadRequest, "password has appeared in a data breach, choose another one")
+	ErrPasswordContainsUsername = newError("password_contains_username", http.StatusBadRequest, "password must not contain the username")
+)
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"bufio"
+	_ "embed"
+	"gw-currency-wallet/config"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	DefaultPasswordMinLength = 8
+
+	// minUsernameCheckLength короткие имена встречаются в паролях случайно, поэтому не проверяются
+	minUsernameCheckLength = 3
+)
+
+//go:embed common_passwords.txt
+var commonPasswords string
+
+// passwordPolicy требования к новому паролю: минимальная длина, отсутствие в списке
+// утёкших паролей и отсутствие имени пользователя внутри пароля
+type passwordPolicy struct {
+	minLength int
+	breached  map[string]struct{}
+}
+
+func newPasswordPolicy(cfg *config.PasswordPolicyConfig) (*passwordPolicy, error) {
+	p := &passwordPolicy{
+		minLength: cfg.MinLength,
+		breached:  make(map[string]struct{}),
+	}
+	if p.minLength == 0 {
+		p.minLength = DefaultPasswordMinLength
+	}
+
+	if err := p.load(strings.NewReader(commonPasswords)); err != nil {
+		return nil, err
+	}
+
+	if cfg.BreachedListPath != "" {
+		f, err := os.Open(cfg.BreachedListPath)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		if err = p.load(f); err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+// load читает пароли по одному в строке, пустые строки и строки с "#" пропускаются
+func (p *passwordPolicy) load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p.breached[strings.ToLower(line)] = struct{}{}
+	}
+
+	return scanner.Err()
+}
+
+func (p *passwordPolicy) validate(password, username string) error {
+	if utf8.RuneCountInString(password) < p.minLength {
+		return ErrPasswordTooShort
+	}
+
+	lowered := strings.ToLower(password)
+
+	if _, ok := p.breached[lowered]; ok {
+		return ErrPasswordBreached
+	}
+
+	username = strings.ToLower(strings.TrimSpace(username))
+	if utf8.RuneCountInString(username) >= minUsernameCheckLength && strings.Contains(lowered, username) {
+		return ErrPasswordContainsUsername
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"gw-currency-wallet/config"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/mailer"
+	mock_repository "gw-currency-wallet/internal/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+type testMailer struct {
+	sent []mailer.Message
+}
+
+func (m *testMailer) Send(_ context.Context, msg mailer.Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+func TestForgot_KnownEmail_SendsLink(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockPasswordReset(ctrl)
+	mockAccount := mock_repository.NewMockAccount(ctrl)
+	m := &testMailer{}
+
+	s := newTestService()
+	s.Account = NewAccountService(mockAccount, s)
+	srv := NewPasswordService(mockRepo, m, &config.AuthConfig{PasswordResetURL: "https://wallet.example.com/reset"}, s)
+
+	mockAccount.EXPECT().GetByEmail(t.Context(), "alice@example.com").Return(&db.AppAccount{
+		Email:    "Alice@example.com",
+		Username: "alice",
+	}, nil)
+	mockRepo.EXPECT().CreateToken(t.Context(), gomock.Any(), "Alice@example.com", gomock.Any()).Return(nil)
+
+	err := srv.Forgot(t.Context(), "alice@example.com")
+
+	assert.NoError(t, err)
+	assert.Len(t, m.sent, 1)
+	assert.Equal(t, "Alice@example.com", m.sent[0].To)
+	assert.Contains(t, m.sent[0].Body, "https://wallet.example.com/reset?token=")
+}
+
+func TestForgot_UnknownEmail_SendsNothing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAccount := mock_repository.NewMockAccount(ctrl)
+	m := &testMailer{}
+
+	s := newTestService()
+	s.Account = NewAccountService(mockAccount, s)
+	srv := NewPasswordService(mock_repository.NewMockPasswordReset(ctrl), m, &config.AuthConfig{PasswordResetURL: "https://wallet.example.com/reset"}, s)
+
+	mockAccount.EXPECT().GetByEmail(t.Context(), "nobody@example.com").Return(nil, nil)
+	mockAccount.EXPECT().GetByUsername(t.Context(), "nobody@example.com").Return(nil, nil)
+
+	err := srv.Forgot(t.Context(), "nobody@example.com")
+
+	assert.NoError(t, err)
+	assert.Empty(t, m.sent)
+}
+
+func TestReset_ValidToken_UpdatesPasswordAndRevokesSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockPasswordReset(ctrl)
+	mockAccount := mock_repository.NewMockAccount(ctrl)
+
+	s := newTestService()
+	s.Account = NewAccountService(mockAccount, s)
+	srv := NewPasswordService(mockRepo, &testMailer{}, &config.AuthConfig{PasswordResetURL: "https://wallet.example.com/reset"}, s)
+	mockSession := mock_repository.NewMockSession(ctrl)
+	srv.s.Session = NewSessionService(mockSession, srv.s)
+
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+	mockTx.EXPECT().Commit(gomock.Any()).Return(nil)
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+	mockRepo.EXPECT().GetTokenForUpdate(gomock.Any(), hashToken("reset")).Return(&db.AppPasswordResetToken{
+		TokenHash: hashToken("reset"),
+		Email:     "alice@example.com",
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(time.Minute), Valid: true},
+	}, nil)
+	mockAccount.EXPECT().GetByEmail(gomock.Any(), "alice@example.com").Return(&db.AppAccount{Email: "alice@example.com", Username: "alice"}, nil)
+	mockRepo.EXPECT().UpdatePassword(gomock.Any(), "alice@example.com", gomock.Any()).DoAndReturn(
+		func(_ context.Context, _, passwordHash string) error {
+			assert.NoError(t, srv.s.Auth.ComparePassword(passwordHash, "new-password"))
+			return nil
+		})
+	mockRepo.EXPECT().UseTokens(gomock.Any(), "alice@example.com").Return(nil)
+	mockSession.EXPECT().RevokeAll(gomock.Any(), "alice@example.com").Return(nil)
+
+	err := srv.Reset(t.Context(), "reset", "new-password")
+
+	assert.NoError(t, err)
+}
+
+func TestReset_UsedToken_ReturnsInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockPasswordReset(ctrl)
+
+	s := newTestService()
+	s.Account = NewAccountService(mock_repository.NewMockAccount(ctrl), s)
+	srv := NewPasswordService(mockRepo, &testMailer{}, &config.AuthConfig{PasswordResetURL: "https://wallet.example.com/reset"}, s)
+
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+	mockRepo.EXPECT().GetTokenForUpdate(gomock.Any(), hashToken("reset")).Return(&db.AppPasswordResetToken{
+		TokenHash: hashToken("reset"),
+		Email:     "alice@example.com",
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(time.Minute), Valid: true},
+		UsedAt:    pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}, nil)
+
+	err := srv.Reset(t.Context(), "reset", "new-password")
+
+	assert.ErrorIs(t, err, ErrResetTokenInvalid)
+}
+
+func TestReset_PasswordWithUsername_ReturnsPolicyError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockPasswordReset(ctrl)
+	mockAccount := mock_repository.NewMockAccount(ctrl)
+
+	s := newTestService()
+	s.Account = NewAccountService(mockAccount, s)
+	srv := NewPasswordService(mockRepo, &testMailer{}, &config.AuthConfig{PasswordResetURL: "https://wallet.example.com/reset"}, s)
+
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+	mockRepo.EXPECT().GetTokenForUpdate(gomock.Any(), hashToken("reset")).Return(&db.AppPasswordResetToken{
+		TokenHash: hashToken("reset"),
+		Email:     "alice@example.com",
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(time.Minute), Valid: true},
+	}, nil)
+	mockAccount.EXPECT().GetByEmail(gomock.Any(), "alice@example.com").Return(&db.AppAccount{Email: "alice@example.com", Username: "alice"}, nil)
+
+	err := srv.Reset(t.Context(), "reset", "alice-2025-wallet")
+
+	assert.ErrorIs(t, err, ErrPasswordContainsUsername)
+}
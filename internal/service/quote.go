@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/logger"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/repository"
+	"gw-currency-wallet/pkg"
+	"time"
+)
+
+const (
+	DefaultQuoteTTL = 30 * time.Second
+)
+
+type QuoteService struct {
+	r repository.Quote
+	s *Service
+}
+
+func (s *QuoteService) Create(ctx context.Context, email string, from, to pkg.Currency, amount pkg.Amount) (*models.ExchangeQuote, error) {
+	if _, err := s.s.Currency.Validate(ctx, from, amount); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	target, err := s.s.Currency.Resolve(ctx, to)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	rate, err := s.s.Exchange.GetRate(ctx, from, to)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	gross := amount.Mul(rate).RoundDown(target.MinorUnits)
+
+	fee, err := s.s.Fee.Calculate(ctx, from, to, gross, target.MinorUnits)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	row, err := s.r.Create(ctx, email, from, to, amount, rate, gross.Sub(fee), fee, time.Now().Add(DefaultQuoteTTL))
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return quoteFromRow(row), nil
+}
+
+// Redeem погашает котировку. Вызывается внутри транзакции обмена: строка
+// котировки блокируется, а отметка об использовании откатывается вместе
+// с обменом, если он не удался.
+func (s *QuoteService) Redeem(ctx context.Context, email, id string) (*models.ExchangeQuote, error) {
+	row, err := s.r.GetForUpdate(ctx, id, email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	if row == nil {
+		logger.L(ctx).Warn(ErrQuoteNotFound.Error())
+		return nil, ErrQuoteNotFound
+	}
+
+	if row.UsedAt.Valid {
+		logger.L(ctx).Warn(ErrQuoteAlreadyUsed.Error())
+		return nil, ErrQuoteAlreadyUsed
+	}
+
+	if !time.Now().Before(row.ExpiresAt.Time) {
+		logger.L(ctx).Warn(ErrQuoteExpired.Error())
+		return nil, ErrQuoteExpired
+	}
+
+	if err = s.r.MarkUsed(ctx, row.ID); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return quoteFromRow(row), nil
+}
+
+func NewQuoteService(r repository.Quote, s *Service) *QuoteService {
+	return &QuoteService{
+		r: r,
+		s: s,
+	}
+}
+
+func quoteFromRow(row *db.AppExchangeQuote) *models.ExchangeQuote {
+	return &models.ExchangeQuote{
+		ID:            row.ID.String(),
+		From:          row.FromCurrency,
+		To:            row.ToCurrency,
+		Amount:        row.Amount,
+		Rate:          row.Rate,
+		ReceiveAmount: row.ReceiveAmount,
+		Fee:           row.Fee,
+		ExpiresAt:     row.ExpiresAt.Time,
+	}
+}
@@ -0,0 +1,10 @@
+package service
+
+import "net/http"
+
+var (
+	ErrQuoteNotFound    = newError("quote_not_found", http.StatusNotFound, "exchange quote not found")
+	ErrQuoteExpired     = newError("quote_expired", http.StatusGone, "exchange quote has expired")
+	ErrQuoteAlreadyUsed = newError("quote_already_used", http.StatusConflict, "exchange quote has already been used")
+	ErrQuoteMismatch    = newError("quote_mismatch", http.StatusBadRequest, "exchange request does not match the quote")
+)
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"gw-currency-wallet/internal/db"
+	mock_repository "gw-currency-wallet/internal/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const testQuoteID = "6f1c2a7e-4b1d-4c3e-9a51-0d2f3e4a5b6c"
+
+func testQuoteRow(t *testing.T, expiresAt time.Time) *db.AppExchangeQuote {
+	var id pgtype.UUID
+	assert.NoError(t, id.Scan(testQuoteID))
+
+	return &db.AppExchangeQuote{
+		ID:            id,
+		Email:         "user@example.com",
+		FromCurrency:  "USD",
+		ToCurrency:    "EUR",
+		Amount:        decimal.NewFromInt(10),
+		Rate:          decimal.RequireFromString("0.9"),
+		ReceiveAmount: decimal.NewFromInt(9),
+		ExpiresAt:     pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	}
+}
+
+func TestRedeem_ValidQuote_MarksUsed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockQuote(ctrl)
+	srv := NewQuoteService(mockRepo, &Service{})
+
+	row := testQuoteRow(t, time.Now().Add(time.Minute))
+	mockRepo.EXPECT().GetForUpdate(t.Context(), testQuoteID, "user@example.com").Return(row, nil)
+	mockRepo.EXPECT().MarkUsed(t.Context(), row.ID).Return(nil)
+
+	quote, err := srv.Redeem(t.Context(), "user@example.com", testQuoteID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, testQuoteID, quote.ID)
+	assert.True(t, decimal.NewFromInt(9).Equal(quote.ReceiveAmount))
+}
+
+func TestRedeem_ExpiredQuote_ReturnsExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockQuote(ctrl)
+	srv := NewQuoteService(mockRepo, &Service{})
+
+	row := testQuoteRow(t, time.Now().Add(-time.Second))
+	mockRepo.EXPECT().GetForUpdate(t.Context(), testQuoteID, "user@example.com").Return(row, nil)
+
+	_, err := srv.Redeem(t.Context(), "user@example.com", testQuoteID)
+
+	assert.ErrorIs(t, err, ErrQuoteExpired)
+}
+
+func TestRedeem_UsedQuote_ReturnsAlreadyUsed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockQuote(ctrl)
+	srv := NewQuoteService(mockRepo, &Service{})
+
+	row := testQuoteRow(t, time.Now().Add(time.Minute))
+	row.UsedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	mockRepo.EXPECT().GetForUpdate(t.Context(), testQuoteID, "user@example.com").Return(row, nil)
+
+	_, err := srv.Redeem(t.Context(), "user@example.com", testQuoteID)
+
+	assert.ErrorIs(t, err, ErrQuoteAlreadyUsed)
+}
+
+func TestRedeem_UnknownQuote_ReturnsNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockQuote(ctrl)
+	srv := NewQuoteService(mockRepo, &Service{})
+
+	mockRepo.EXPECT().GetForUpdate(t.Context(), "missing", "user@example.com").Return(nil, nil)
+
+	_, err := srv.Redeem(t.Context(), "user@example.com", "missing")
+
+	assert.ErrorIs(t, err, ErrQuoteNotFound)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"gw-currency-wallet/config"
+	"gw-currency-wallet/internal/logger"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/repository"
+	"math"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const rateLimitCleanupInterval = time.Minute * 10
+
+// DefaultRateLimits лимиты групп, не заданные в конфигурации
+var DefaultRateLimits = map[models.RateLimitGroup]config.RateLimit{
+	models.RateLimitGroupAuth:     {Requests: 20, Period: time.Minute},
+	models.RateLimitGroupExchange: {Requests: 30, Period: time.Minute},
+	models.RateLimitGroupWallet:   {Requests: 60, Period: time.Minute},
+	models.RateLimitGroupDefault:  {Requests: 120, Period: time.Minute},
+}
+
+// RateLimitService ограничивает частоту запросов по алгоритму token bucket: у каждой пары
+// группа маршрутов и клиент есть корзина на Requests запросов, которая равномерно
+// наполняется за Period.
+type RateLimitService struct {
+	r      repository.RateLimit
+	limits map[models.RateLimitGroup]config.RateLimit
+}
+
+// Take расходует один запрос subject в группе group. Если запас исчерпан, вместе с результатом
+// возвращается *RateLimitExceededError. Ошибка хранилища не блокирует запросы: она пишется
+// в лог, и запрос пропускается без результата.
+func (s *RateLimitService) Take(ctx context.Context, group models.RateLimitGroup, subject string) (*models.RateLimitResult, error) {
+	limit := s.limit(group)
+	if limit.Requests <= 0 {
+		return nil, nil
+	}
+
+	capacity := float64(limit.Requests)
+	refillRate := capacity / limit.Period.Seconds()
+
+	tokens, allowed, err := s.r.Take(ctx, group+":"+subject, capacity, refillRate)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, nil
+	}
+
+	result := &models.RateLimitResult{
+		Allowed:   allowed,
+		Limit:     limit.Requests,
+		Remaining: int(math.Floor(max(tokens, 0))),
+		Period:    limit.Period,
+		Reset:     refillDuration(capacity-tokens, refillRate),
+	}
+
+	if !allowed {
+		result.RetryAfter = refillDuration(1-tokens, refillRate)
+		logger.L(ctx).Warn(ErrRateLimitExceeded.Error(), zap.String("group", group), zap.String("subject", subject))
+		return result, &RateLimitExceededError{RetryAfter: result.RetryAfter}
+	}
+
+	return result, nil
+}
+
+func (s *RateLimitService) limit(group models.RateLimitGroup) config.RateLimit {
+	if limit, ok := s.limits[group]; ok {
+		return limit
+	}
+
+	return s.limits[models.RateLimitGroupDefault]
+}
+
+// cleanup удаляет корзины, к которым не обращались дольше самого длинного периода:
+// такие корзины уже полны и ничем не отличаются от отсутствующих
+func (s *RateLimitService) cleanup(ctx context.Context) {
+	var idle time.Duration
+	for _, limit := range s.limits {
+		idle = max(idle, limit.Period)
+	}
+
+	ticker := time.NewTicker(rateLimitCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.r.DeleteIdle(ctx, time.Now().Add(-idle)); err != nil {
+				logger.L(ctx).Error(err.Error())
+			}
+		}
+	}
+}
+
+func NewRateLimitService(ctx context.Context, r repository.RateLimit, cfg *config.RateLimitConfig) *RateLimitService {
+	s := &RateLimitService{
+		r:      r,
+		limits: make(map[models.RateLimitGroup]config.RateLimit, len(DefaultRateLimits)),
+	}
+
+	for group, limit := range DefaultRateLimits {
+		s.limits[group] = limit
+	}
+	for group, limit := range cfg.Limits {
+		s.limits[group] = limit
+	}
+
+	go s.cleanup(ctx)
+
+	return s
+}
+
+// refillDuration время, за которое в корзину поступит tokens запросов
+func refillDuration(tokens, refillRate float64) time.Duration {
+	if tokens <= 0 {
+		return 0
+	}
+
+	return time.Duration(tokens / refillRate * float64(time.Second))
+}
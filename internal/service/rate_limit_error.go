@@ -0,0 +1,25 @@
+package service
+
+import (
+	"net/http"
+	"time"
+)
+
+var ErrRateLimitExceeded = newError("rate_limit_exceeded", http.StatusTooManyRequests, "rate limit exceeded, try again later")
+
+// RateLimitExceededError сообщает, через сколько можно повторить запрос
+type RateLimitExceededError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitExceededError) Error() string {
+	return ErrRateLimitExceeded.Error()
+}
+
+func (e *RateLimitExceededError) Unwrap() error {
+	return ErrRateLimitExceeded
+}
+
+func (e *RateLimitExceededError) RetryAfterSeconds() int {
+	return retryAfterSeconds(e.RetryAfter)
+}
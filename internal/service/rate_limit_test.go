@@ -0,0 +1,85 @@
+package service
+
+import (
+	"errors"
+	"gw-currency-wallet/config"
+	"gw-currency-wallet/internal/models"
+	mock_repository "gw-currency-wallet/internal/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRateLimitTake_Allowed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRateLimit(ctrl)
+	srv := NewRateLimitService(t.Context(), mockRepo, &config.RateLimitConfig{
+		Limits: map[string]config.RateLimit{models.RateLimitGroupExchange: {Requests: 10, Period: 10 * time.Second}},
+	})
+
+	mockRepo.EXPECT().Take(t.Context(), "exchange:account:alice@example.com", float64(10), float64(1)).Return(7.5, true, nil)
+
+	result, err := srv.Take(t.Context(), models.RateLimitGroupExchange, "account:alice@example.com")
+
+	assert.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 10, result.Limit)
+	assert.Equal(t, 7, result.Remaining)
+	assert.Equal(t, 2500*time.Millisecond, result.Reset)
+	assert.Equal(t, time.Duration(0), result.RetryAfter)
+}
+
+func TestRateLimitTake_Exhausted_ReturnsRetryAfter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRateLimit(ctrl)
+	srv := NewRateLimitService(t.Context(), mockRepo, &config.RateLimitConfig{})
+
+	// Группа без своего лимита получает лимит default: 120 запросов в минуту
+	mockRepo.EXPECT().Take(t.Context(), "unknown:ip:10.0.0.1", float64(120), float64(2)).Return(0.5, false, nil)
+
+	result, err := srv.Take(t.Context(), "unknown", "ip:10.0.0.1")
+
+	var exceeded *RateLimitExceededError
+	assert.ErrorIs(t, err, ErrRateLimitExceeded)
+	assert.ErrorAs(t, err, &exceeded)
+	assert.Equal(t, 250*time.Millisecond, exceeded.RetryAfter)
+	assert.Equal(t, 1, exceeded.RetryAfterSeconds())
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+}
+
+func TestRateLimitTake_Disabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRateLimit(ctrl)
+	srv := NewRateLimitService(t.Context(), mockRepo, &config.RateLimitConfig{
+		Limits: map[string]config.RateLimit{models.RateLimitGroupAuth: {Requests: 0, Period: time.Minute}},
+	})
+
+	result, err := srv.Take(t.Context(), models.RateLimitGroupAuth, "ip:10.0.0.1")
+
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
+
+func TestRateLimitTake_StoreError_FailsOpen(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockRateLimit(ctrl)
+	srv := NewRateLimitService(t.Context(), mockRepo, &config.RateLimitConfig{})
+
+	mockRepo.EXPECT().Take(t.Context(), "wallet:account:alice@example.com", float64(60), float64(1)).Return(0.0, false, errors.New("connection refused"))
+
+	result, err := srv.Take(t.Context(), models.RateLimitGroupWallet, "account:alice@example.com")
+
+	assert.NoError(t, err)
+	assert.Nil(t, result)
+}
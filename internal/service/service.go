@@ -3,10 +3,12 @@ package service
 import (
 	"context"
 	"gw-currency-wallet/config"
+	"gw-currency-wallet/internal/mailer"
 	"gw-currency-wallet/internal/models"
 	gw_grpc "gw-currency-wallet/internal/pb/exchange"
 	"gw-currency-wallet/internal/repository"
 	"gw-currency-wallet/pkg"
+	"time"
 )
 
 type Exchange interface {
@@ -17,22 +19,125 @@ type Exchange interface {
 
 type Wallet interface {
 	GetAllByEmail(ctx context.Context, email string) (pkg.AccountWallets, error)
-	Deposit(ctx context.Context, email string, currency pkg.Currency, amount float32) (pkg.AccountWallets, error)
-	Withdraw(ctx context.Context, email string, currency pkg.Currency, amount float32) (pkg.AccountWallets, error)
+	Deposit(ctx context.Context, email, idempotencyKey string, currency pkg.Currency, amount pkg.Amount) (pkg.AccountWallets, error)
+	Withdraw(ctx context.Context, email, idempotencyKey string, currency pkg.Currency, amount pkg.Amount) (pkg.AccountWallets, error)
 	GetRates(ctx context.Context) (pkg.ExchangeRates, error)
-	Exchange(ctx context.Context, email string, from, to pkg.Currency, amount float32) (exchangedAmount float32, wallets pkg.AccountWallets, err error)
+	Exchange(ctx context.Context, email, idempotencyKey string, order models.ExchangeOrder) (amounts models.ExchangeAmounts, wallets pkg.AccountWallets, err error)
+	Transfer(ctx context.Context, email, idempotencyKey, recipient string, currency pkg.Currency, amount pkg.Amount) (pkg.AccountWallets, error)
+	GetBalances(ctx context.Context, email string) (map[pkg.Currency]models.WalletBalance, error)
+	Authorize(ctx context.Context, email, idempotencyKey string, currency pkg.Currency, amount pkg.Amount, ttl time.Duration) (*models.Hold, error)
+	Capture(ctx context.Context, email, idempotencyKey, id string, amount *pkg.Amount) (*models.Hold, error)
+	Void(ctx context.Context, email, id string) (*models.Hold, error)
+	Adjust(ctx context.Context, email string, currency pkg.Currency, amount pkg.Amount) (transactionID int64, wallets pkg.AccountWallets, err error)
 }
 
 type Auth interface {
 	HashPassword(password string) (string, error)
 	ComparePassword(hashedPassword, password string) error
-	GenerateJWT(email string) (string, error)
+	NeedsRehash(hashedPassword string) bool
+	ValidatePassword(password, username string) error
+	GenerateJWT(email, sessionID string, role models.Role) (string, error)
 	GetClaims(tokenString string) (*models.AuthClaims, error)
+	JWKS() []models.JWK
+	GenerateTOTP(account string) (*models.TOTPEnrollment, error)
+	ValidateTOTP(secret, code string, afterStep int64) (int64, bool)
 }
 
 type Account interface {
 	Register(ctx context.Context, email, username, password string) (*models.Account, error)
-	Login(ctx context.Context, username, password string) (token string, err error)
+	Login(ctx context.Context, login, password, clientIP string) (*models.LoginResult, error)
+	Find(ctx context.Context, usernameOrEmail string) (*models.Account, error)
+}
+
+type Ledger interface {
+	Post(ctx context.Context, txType models.TransactionType, entries ...models.LedgerEntry) (transactionID int64, err error)
+	History(ctx context.Context, account string, filter models.TransactionFilter, cursor string, limit int) (*models.TransactionPage, error)
+}
+
+type Idempotency interface {
+	Begin(ctx context.Context, email, key, operation string, request any) (storedResponse []byte, err error)
+	Complete(ctx context.Context, email, key string, response any) error
+}
+
+type Fee interface {
+	Calculate(ctx context.Context, from, to pkg.Currency, gross pkg.Amount, places int32) (pkg.Amount, error)
+}
+
+type Currency interface {
+	List(ctx context.Context) ([]models.Currency, error)
+	Resolve(ctx context.Context, code pkg.Currency) (*models.Currency, error)
+	Validate(ctx context.Context, code pkg.Currency, amount pkg.Amount) (*models.Currency, error)
+}
+
+type Quote interface {
+	Create(ctx context.Context, email string, from, to pkg.Currency, amount pkg.Amount) (*models.ExchangeQuote, error)
+	Redeem(ctx context.Context, email, id string) (*models.ExchangeQuote, error)
+}
+
+type Session interface {
+	Start(ctx context.Context, email string) (*models.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (*models.TokenPair, error)
+	Revoke(ctx context.Context, sessionID string) error
+	RevokeAll(ctx context.Context, email string) error
+	IsActive(ctx context.Context, sessionID string) (bool, error)
+}
+
+type LoginGuard interface {
+	Check(ctx context.Context, account, ip string) error
+	Fail(ctx context.Context, account, ip string) error
+	Succeed(ctx context.Context, account string) error
+	Unlock(ctx context.Context, scope models.LoginScope, subject, unlockedBy string) error
+	Lockouts(ctx context.Context, scope models.LoginScope, subject string) ([]models.LockoutEvent, error)
+}
+
+type TwoFactor interface {
+	IsEnabled(ctx context.Context, email string) (bool, error)
+	Enroll(ctx context.Context, email string) (*models.TOTPEnrollment, error)
+	Confirm(ctx context.Context, email, code string) (recoveryCodes []string, err error)
+	Challenge(ctx context.Context, email string) (*models.TwoFactorChallenge, error)
+	Verify(ctx context.Context, challengeToken, code, clientIP string) (*models.TokenPair, error)
+}
+
+type Password interface {
+	Forgot(ctx context.Context, email string) error
+	Reset(ctx context.Context, token, password string) error
+}
+
+type EmailVerification interface {
+	Send(ctx context.Context, email string) error
+	Resend(ctx context.Context, email string) error
+	Verify(ctx context.Context, token string) error
+	IsVerified(ctx context.Context, email string) (bool, error)
+}
+
+type Admin interface {
+	SearchAccounts(ctx context.Context, query string, limit int) ([]models.Account, error)
+	GetAccount(ctx context.Context, email string) (*models.Account, error)
+	Balances(ctx context.Context, email string) (map[pkg.Currency]models.WalletBalance, error)
+	Freeze(ctx context.Context, actor, email, reason string) error
+	Unfreeze(ctx context.Context, actor, email, reason string) error
+	SetRole(ctx context.Context, actor, email string, role models.Role, reason string) error
+	AdjustBalance(ctx context.Context, actor, email, idempotencyKey string, currency pkg.Currency, amount pkg.Amount, reason string) (transactionID int64, wallets pkg.AccountWallets, err error)
+	UnlockLogin(ctx context.Context, actor string, scope models.LoginScope, subject, reason string) error
+	LoginLockouts(ctx context.Context, scope models.LoginScope, subject string) ([]models.LockoutEvent, error)
+	AuditLog(ctx context.Context, subject string, limit int) ([]models.AuditEntry, error)
+}
+
+type APIKey interface {
+	Create(ctx context.Context, email, name string, scopes []models.Scope, allowedIPs []string, expiresAt *time.Time) (*models.NewAPIKey, error)
+	List(ctx context.Context, email string) ([]models.APIKey, error)
+	Revoke(ctx context.Context, email, id string) error
+	Authenticate(ctx context.Context, key, clientIP string) (*models.APIKey, error)
+}
+
+type RateLimit interface {
+	Take(ctx context.Context, group models.RateLimitGroup, subject string) (*models.RateLimitResult, error)
+}
+
+// Metrics учитывает проведённые операции со средствами и события кеша курсов
+type Metrics interface {
+	ObserveOperation(operation string, currency pkg.Currency, amount pkg.Amount)
+	CacherObserver(name string) pkg.CacherObserver
 }
 
 type Service struct {
@@ -40,15 +145,57 @@ type Service struct {
 	Account
 	Wallet
 	Exchange
+	Ledger
+	Idempotency
+	Quote
+	Fee
+	Currency
+	Session
+	LoginGuard
+	TwoFactor
+	Password
+	EmailVerification
+	Admin
+	APIKey
+	RateLimit
+
+	metrics Metrics
 }
 
-func NewService(ctx context.Context, repo *repository.Repository, authConfig *config.AuthConfig, exchangeClient gw_grpc.ExchangeServiceClient) *Service {
-	s := &Service{}
+func NewService(ctx context.Context, repo *repository.Repository, authConfig *config.AuthConfig, rateLimitConfig *config.RateLimitConfig, exchangeClient gw_grpc.ExchangeServiceClient, m mailer.Mailer, metrics Metrics) *Service {
+	s := &Service{metrics: metrics}
 
 	s.Account = NewAccountService(repo.Account, s)
 	s.Auth = NewAuthService(authConfig)
-	s.Wallet = NewWalletService(repo.Wallet, s)
-	s.Exchange = NewExchangeService(ctx, exchangeClient)
+	s.Wallet = tracedWallet{NewWalletService(repo.Wallet, s)}
+	s.Exchange = tracedExchange{NewExchangeService(ctx, exchangeClient, s.cacherObserver(exchangeRatesCache))}
+	s.Ledger = NewLedgerService(repo.Ledger)
+	s.Idempotency = NewIdempotencyService(repo.Idempotency)
+	s.Quote = NewQuoteService(repo.Quote, s)
+	s.Fee = NewFeeService(repo.Fee)
+	s.Currency = NewCurrencyService(repo.Currency)
+	s.Session = NewSessionService(repo.Session, s)
+	s.LoginGuard = NewLoginGuardService(repo.LoginAttempt, authConfig)
+	s.TwoFactor = NewTwoFactorService(repo.TwoFactor, s)
+	s.Password = NewPasswordService(repo.PasswordReset, m, authConfig, s)
+	s.EmailVerification = NewEmailVerificationService(repo.EmailVerification, m, authConfig, s)
+	s.Admin = NewAdminService(repo.Admin, s)
+	s.APIKey = NewAPIKeyService(repo.APIKey, s)
+	s.RateLimit = NewRateLimitService(ctx, repo.RateLimit, rateLimitConfig)
 
 	return s
 }
+
+// observeOperation учитывает операцию в метриках, если они подключены
+func (s *Service) observeOperation(operation string, currency pkg.Currency, amount pkg.Amount) {
+	if s.metrics != nil {
+		s.metrics.ObserveOperation(operation, currency, amount)
+	}
+}
+
+func (s *Service) cacherObserver(name string) pkg.CacherObserver {
+	if s.metrics == nil {
+		return nil
+	}
+	return s.metrics.CacherObserver(name)
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"gw-currency-wallet/internal/logger"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/repository"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+const refreshTokenBytes = 32
+
+type SessionService struct {
+	r repository.Session
+	s *Service
+}
+
+// Start открывает новую сессию и выдаёт первую пару токенов
+func (s *SessionService) Start(ctx context.Context, email string) (*models.TokenPair, error) {
+	c, tx, err := s.r.WithTx(ctx)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			logger.L(ctx).Error(err.Error())
+		}
+	}()
+
+	session, err := s.r.Create(c, email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	tokens, err := s.issue(c, email, session.ID)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	if err = tx.Commit(c); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// Refresh обменивает refresh-токен на новую пару токенов. Каждый refresh-токен
+// одноразовый: повторное предъявление уже использованного токена означает его
+// утечку, поэтому вся сессия отзывается.
+func (s *SessionService) Refresh(ctx context.Context, refreshToken string) (*models.TokenPair, error) {
+	if refreshToken == "" {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	c, tx, err := s.r.WithTx(ctx)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			logger.L(ctx).Error(err.Error())
+		}
+	}()
+
+	tokenHash := hashToken(refreshToken)
+
+	token, err := s.r.GetRefreshTokenForUpdate(c, tokenHash)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	if token == nil {
+		logger.L(ctx).Warn(ErrRefreshTokenInvalid.Error())
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	session, err := s.r.Get(c, token.SessionID.String())
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	if session == nil || session.RevokedAt.Valid {
+		logger.L(ctx).Warn(ErrSessionRevoked.Error())
+		return nil, ErrSessionRevoked
+	}
+
+	if token.UsedAt.Valid {
+		if err = s.r.Revoke(c, session.ID); err != nil {
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+
+		// Отзыв фиксируется, несмотря на ошибку для клиента
+		if err = tx.Commit(c); err != nil {
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+
+		logger.L(ctx).Warn(ErrRefreshTokenReused.Error(), zap.String("email", session.Email))
+		return nil, ErrRefreshTokenReused
+	}
+
+	if !time.Now().Before(token.ExpiresAt.Time) {
+		logger.L(ctx).Warn(ErrRefreshTokenInvalid.Error())
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	if err = s.r.MarkRefreshTokenUsed(c, tokenHash); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	tokens, err := s.issue(c, session.Email, session.ID)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	if err = tx.Commit(c); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// Revoke завершает сессию. Отзыв неизвестной или уже отозванной сессии не считается ошибкой.
+func (s *SessionService) Revoke(ctx context.Context, sessionID string) error {
+	session, err := s.r.Get(ctx, sessionID)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	if session == nil {
+		return nil
+	}
+
+	if err = s.r.Revoke(ctx, session.ID); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// RevokeAll завершает все сессии пользователя
+func (s *SessionService) RevokeAll(ctx context.Context, email string) error {
+	if err := s.r.RevokeAll(ctx, email); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (s *SessionService) IsActive(ctx context.Context, sessionID string) (bool, error) {
+	session, err := s.r.Get(ctx, sessionID)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return false, err
+	}
+
+	return session != nil && !session.RevokedAt.Valid, nil
+}
+
+func NewSessionService(r repository.Session, s *Service) *SessionService {
+	return &SessionService{
+		r: r,
+		s: s,
+	}
+}
+
+func (s *SessionService) issue(ctx context.Context, email string, sessionID pgtype.UUID) (*models.TokenPair, error) {
+	raw := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+	refreshToken := base64.RawURLEncoding.EncodeToString(raw)
+
+	if err := s.r.CreateRefreshToken(ctx, hashToken(refreshToken), sessionID, time.Now().Add(DefaultRefreshTokenDuration)); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	// Роль читается при каждой выдаче токена, поэтому её изменение вступает в силу
+	// не позже следующего обновления токена
+	account, err := s.s.Account.Find(ctx, email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	if account == nil {
+		logger.L(ctx).Warn(ErrSessionRevoked.Error(), zap.String("email", email))
+		return nil, ErrSessionRevoked
+	}
+
+	expiresAt := time.Now().Add(DefaultJWTExpireDuration)
+	accessToken, err := s.s.Auth.GenerateJWT(email, sessionID.String(), account.Role)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return &models.TokenPair{
+		AccessToken:     accessToken,
+		RefreshToken:    refreshToken,
+		AccessExpiresAt: expiresAt,
+	}, nil
+}
+
+// hashToken возвращает SHA-256 токена в hex. В базе хранятся только хеши.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"gw-currency-wallet/config"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/models"
+	mock_repository "gw-currency-wallet/internal/repository/mocks"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const testSessionID = "0b8e6f3a-2c4d-4e5f-8a9b-1c2d3e4f5a6b"
+
+func testSession(t *testing.T) *db.AppSession {
+	var id pgtype.UUID
+	assert.NoError(t, id.Scan(testSessionID))
+
+	return &db.AppSession{
+		ID:    id,
+		Email: "user@example.com",
+	}
+}
+
+// newTestService собирает Service с AuthService на тестовом ключе; остальные
+// зависимости тесты подставляют сами
+func newTestService() *Service {
+	return &Service{Auth: NewAuthService(&config.AuthConfig{SecretKey: "test"})}
+}
+
+// withTestAccount отвечает на поиск аккаунта при выдаче токенов
+func withTestAccount(ctrl *gomock.Controller, s *Service, role models.Role) {
+	mockAccount := mock_repository.NewMockAccount(ctrl)
+	mockAccount.EXPECT().GetByEmail(gomock.Any(), "user@example.com").Return(&db.AppAccount{
+		Email:    "user@example.com",
+		Username: "user",
+		Role:     role,
+	}, nil).AnyTimes()
+	s.Account = NewAccountService(mockAccount, s)
+}
+
+func TestRefresh_ValidToken_RotatesToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockSession(ctrl)
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+	s := newTestService()
+	withTestAccount(ctrl, s, models.RoleUser)
+	srv := NewSessionService(mockRepo, s)
+	session := testSession(t)
+
+	mockRepo.EXPECT().GetRefreshTokenForUpdate(gomock.Any(), hashToken("old")).Return(&db.AppRefreshToken{
+		TokenHash: hashToken("old"),
+		SessionID: session.ID,
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true},
+	}, nil)
+	mockRepo.EXPECT().Get(gomock.Any(), testSessionID).Return(session, nil)
+	mockRepo.EXPECT().MarkRefreshTokenUsed(gomock.Any(), hashToken("old")).Return(nil)
+	mockRepo.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any(), session.ID, gomock.Any()).Return(nil)
+	mockTx.EXPECT().Commit(gomock.Any()).Return(nil)
+
+	tokens, err := srv.Refresh(t.Context(), "old")
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+	assert.NotEqual(t, "old", tokens.RefreshToken)
+
+	claims, err := srv.s.Auth.GetClaims(tokens.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, testSessionID, claims.SessionID)
+}
+
+func TestRefresh_EmbedsCurrentRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockSession(ctrl)
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+	s := newTestService()
+	withTestAccount(ctrl, s, models.RoleUser)
+	srv := NewSessionService(mockRepo, s)
+	// Роль сменили после входа: новый токен должен её отражать
+	withTestAccount(ctrl, srv.s, models.RoleSupport)
+	session := testSession(t)
+
+	mockRepo.EXPECT().GetRefreshTokenForUpdate(gomock.Any(), hashToken("old")).Return(&db.AppRefreshToken{
+		TokenHash: hashToken("old"),
+		SessionID: session.ID,
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true},
+	}, nil)
+	mockRepo.EXPECT().Get(gomock.Any(), testSessionID).Return(session, nil)
+	mockRepo.EXPECT().MarkRefreshTokenUsed(gomock.Any(), hashToken("old")).Return(nil)
+	mockRepo.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any(), session.ID, gomock.Any()).Return(nil)
+	mockTx.EXPECT().Commit(gomock.Any()).Return(nil)
+
+	tokens, err := srv.Refresh(t.Context(), "old")
+	assert.NoError(t, err)
+
+	claims, err := srv.s.Auth.GetClaims(tokens.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, models.RoleSupport, claims.Role)
+}
+
+func TestRefresh_ReusedToken_RevokesSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockSession(ctrl)
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+	s := newTestService()
+	withTestAccount(ctrl, s, models.RoleUser)
+	srv := NewSessionService(mockRepo, s)
+	session := testSession(t)
+
+	mockRepo.EXPECT().GetRefreshTokenForUpdate(gomock.Any(), hashToken("old")).Return(&db.AppRefreshToken{
+		TokenHash: hashToken("old"),
+		SessionID: session.ID,
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true},
+		UsedAt:    pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}, nil)
+	mockRepo.EXPECT().Get(gomock.Any(), testSessionID).Return(session, nil)
+	mockRepo.EXPECT().Revoke(gomock.Any(), session.ID).Return(nil)
+	mockTx.EXPECT().Commit(gomock.Any()).Return(nil)
+
+	_, err := srv.Refresh(t.Context(), "old")
+
+	assert.ErrorIs(t, err, ErrRefreshTokenReused)
+}
+
+func TestRefresh_RevokedSession_ReturnsRevoked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockSession(ctrl)
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+	s := newTestService()
+	withTestAccount(ctrl, s, models.RoleUser)
+	srv := NewSessionService(mockRepo, s)
+	session := testSession(t)
+	session.RevokedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+	mockRepo.EXPECT().GetRefreshTokenForUpdate(gomock.Any(), hashToken("old")).Return(&db.AppRefreshToken{
+		TokenHash: hashToken("old"),
+		SessionID: session.ID,
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true},
+	}, nil)
+	mockRepo.EXPECT().Get(gomock.Any(), testSessionID).Return(session, nil)
+
+	_, err := srv.Refresh(t.Context(), "old")
+
+	assert.ErrorIs(t, err, ErrSessionRevoked)
+}
+
+func TestRefresh_UnknownToken_ReturnsInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockSession(ctrl)
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+	s := newTestService()
+	withTestAccount(ctrl, s, models.RoleUser)
+	srv := NewSessionService(mockRepo, s)
+
+	mockRepo.EXPECT().GetRefreshTokenForUpdate(gomock.Any(), hashToken("missing")).Return(nil, nil)
+
+	_, err := srv.Refresh(t.Context(), "missing")
+
+	assert.ErrorIs(t, err, ErrRefreshTokenInvalid)
+}
+
+func TestIsActive_RevokedSession_ReturnsFalse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockSession(ctrl)
+	srv := NewSessionService(mockRepo, &Service{})
+
+	session := testSession(t)
+	session.RevokedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	mockRepo.EXPECT().Get(t.Context(), testSessionID).Return(session, nil)
+
+	active, err := srv.IsActive(t.Context(), testSessionID)
+
+	assert.NoError(t, err)
+	assert.False(t, active)
+}
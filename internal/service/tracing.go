@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/tracing"
+	"gw-currency-wallet/pkg"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startSpan открывает спан метода сервиса
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan закрывает спан. Ошибки клиента из каталога попадают в атрибут error.code,
+// а ошибкой спан помечается только при внутренних сбоях.
+func endSpan(span trace.Span, err error) {
+	defer span.End()
+	if err == nil {
+		return
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		span.SetAttributes(attribute.String("error.code", e.Code))
+		if e.Status < http.StatusInternalServerError {
+			return
+		}
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+func currencyAttr(currency pkg.Currency) attribute.KeyValue {
+	return attribute.String("currency", currency)
+}
+
+// tracedWallet открывает спан на каждый метод WalletService
+type tracedWallet struct {
+	Wallet
+}
+
+func (w tracedWallet) GetAllByEmail(ctx context.Context, email string) (_ pkg.AccountWallets, err error) {
+	ctx, span := startSpan(ctx, "WalletService.GetAllByEmail")
+	defer func() { endSpan(span, err) }()
+	return w.Wallet.GetAllByEmail(ctx, email)
+}
+
+func (w tracedWallet) Deposit(ctx context.Context, email, idempotencyKey string, currency pkg.Currency, amount pkg.Amount) (_ pkg.AccountWallets, err error) {
+	ctx, span := startSpan(ctx, "WalletService.Deposit", currencyAttr(currency))
+	defer func() { endSpan(span, err) }()
+	return w.Wallet.Deposit(ctx, email, idempotencyKey, currency, amount)
+}
+
+func (w tracedWallet) Withdraw(ctx context.Context, email, idempotencyKey string, currency pkg.Currency, amount pkg.Amount) (_ pkg.AccountWallets, err error) {
+	ctx, span := startSpan(ctx, "WalletService.Withdraw", currencyAttr(currency))
+	defer func() { endSpan(span, err) }()
+	return w.Wallet.Withdraw(ctx, email, idempotencyKey, currency, amount)
+}
+
+func (w tracedWallet) GetRates(ctx context.Context) (_ pkg.ExchangeRates, err error) {
+	ctx, span := startSpan(ctx, "WalletService.GetRates")
+	defer func() { endSpan(span, err) }()
+	return w.Wallet.GetRates(ctx)
+}
+
+func (w tracedWallet) Exchange(ctx context.Context, email, idempotencyKey string, order models.ExchangeOrder) (_ models.ExchangeAmounts, _ pkg.AccountWallets, err error) {
+	ctx, span := startSpan(ctx, "WalletService.Exchange",
+		attribute.String("currency.from", order.From),
+		attribute.String("currency.to", order.To),
+	)
+	defer func() { endSpan(span, err) }()
+	return w.Wallet.Exchange(ctx, email, idempotencyKey, order)
+}
+
+func (w tracedWallet) Transfer(ctx context.Context, email, idempotencyKey, recipient string, currency pkg.Currency, amount pkg.Amount) (_ pkg.AccountWallets, err error) {
+	ctx, span := startSpan(ctx, "WalletService.Transfer", currencyAttr(currency))
+	defer func() { endSpan(span, err) }()
+	return w.Wallet.Transfer(ctx, email, idempotencyKey, recipient, currency, amount)
+}
+
+func (w tracedWallet) GetBalances(ctx context.Context, email string) (_ map[pkg.Currency]models.WalletBalance, err error) {
+	ctx, span := startSpan(ctx, "WalletService.GetBalances")
+	defer func() { endSpan(span, err) }()
+	return w.Wallet.GetBalances(ctx, email)
+}
+
+func (w tracedWallet) Authorize(ctx context.Context, email, idempotencyKey string, currency pkg.Currency, amount pkg.Amount, ttl time.Duration) (_ *models.Hold, err error) {
+	ctx, span := startSpan(ctx, "WalletService.Authorize", currencyAttr(currency))
+	defer func() { endSpan(span, err) }()
+	return w.Wallet.Authorize(ctx, email, idempotencyKey, currency, amount, ttl)
+}
+
+func (w tracedWallet) Capture(ctx context.Context, email, idempotencyKey, id string, amount *pkg.Amount) (_ *models.Hold, err error) {
+	ctx, span := startSpan(ctx, "WalletService.Capture")
+	defer func() { endSpan(span, err) }()
+	return w.Wallet.Capture(ctx, email, idempotencyKey, id, amount)
+}
+
+func (w tracedWallet) Void(ctx context.Context, email, id string) (_ *models.Hold, err error) {
+	ctx, span := startSpan(ctx, "WalletService.Void")
+	defer func() { endSpan(span, err) }()
+	return w.Wallet.Void(ctx, email, id)
+}
+
+func (w tracedWallet) Adjust(ctx context.Context, email string, currency pkg.Currency, amount pkg.Amount) (_ int64, _ pkg.AccountWallets, err error) {
+	ctx, span := startSpan(ctx, "WalletService.Adjust", currencyAttr(currency))
+	defer func() { endSpan(span, err) }()
+	return w.Wallet.Adjust(ctx, email, currency, amount)
+}
+
+// tracedExchange открывает спан на каждый метод ExchangeService
+type tracedExchange struct {
+	Exchange
+}
+
+func (e tracedExchange) IsExistCurrency(ctx context.Context, currency pkg.Currency) (_ bool, err error) {
+	ctx, span := startSpan(ctx, "ExchangeService.IsExistCurrency", currencyAttr(currency))
+	defer func() { endSpan(span, err) }()
+	return e.Exchange.IsExistCurrency(ctx, currency)
+}
+
+func (e tracedExchange) GetRates(ctx context.Context) (_ pkg.ExchangeRates, err error) {
+	ctx, span := startSpan(ctx, "ExchangeService.GetRates")
+	defer func() { endSpan(span, err) }()
+	return e.Exchange.GetRates(ctx)
+}
+
+func (e tracedExchange) GetRate(ctx context.Context, from, to pkg.Currency) (_ pkg.Rate, err error) {
+	ctx, span := startSpan(ctx, "ExchangeService.GetRate",
+		attribute.String("currency.from", from),
+		attribute.String("currency.to", to),
+	)
+	defer func() { endSpan(span, err) }()
+	return e.Exchange.GetRate(ctx, from, to)
+}
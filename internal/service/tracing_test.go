@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"gw-currency-wallet/pkg"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type stubExchange struct {
+	err error
+}
+
+func (e stubExchange) IsExistCurrency(context.Context, pkg.Currency) (bool, error) {
+	return e.err == nil, e.err
+}
+
+func (e stubExchange) GetRates(context.Context) (pkg.ExchangeRates, error) {
+	return nil, e.err
+}
+
+func (e stubExchange) GetRate(context.Context, pkg.Currency, pkg.Currency) (pkg.Rate, error) {
+	return decimal.Zero, e.err
+}
+
+func TestTracedExchange_SpanStatus(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	cases := []struct {
+		name   string
+		err    error
+		status codes.Code
+	}{
+		{name: "ok", err: nil, status: codes.Unset},
+		{name: "client error", err: ErrInsufficientBalance, status: codes.Unset},
+		{name: "internal error", err: errors.New("connection refused"), status: codes.Error},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tracedExchange{stubExchange{err: tc.err}}.GetRate(context.Background(), "USD", "EUR")
+			assert.ErrorIs(t, err, tc.err)
+
+			spans := recorder.Ended()
+			require.NotEmpty(t, spans)
+			span := spans[len(spans)-1]
+			assert.Equal(t, "ExchangeService.GetRate", span.Name())
+			assert.Equal(t, tc.status, span.Status().Code)
+		})
+	}
+}
@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/logger"
+	"gw-currency-wallet/internal/models"
+	"gw-currency-wallet/internal/repository"
+	"gw-currency-wallet/pkg"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultChallengeTTL  = time.Minute * 5
+	MaxChallengeAttempts = 5
+
+	recoveryCodeCount = 10
+	recoveryCodeBytes = 6
+	challengeBytes    = 32
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TwoFactorService двухфакторная аутентификация по TOTP (RFC 6238) с одноразовыми кодами восстановления
+type TwoFactorService struct {
+	r repository.TwoFactor
+	s *Service
+}
+
+func (s *TwoFactorService) IsEnabled(ctx context.Context, email string) (bool, error) {
+	totp, err := s.r.GetTOTP(ctx, email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return false, err
+	}
+
+	return totp != nil && totp.EnabledAt.Valid, nil
+}
+
+// Enroll создаёт новый секрет. До подтверждения первым кодом 2FA не действует,
+// а повторный вызов заменяет секрет.
+func (s *TwoFactorService) Enroll(ctx context.Context, email string) (*models.TOTPEnrollment, error) {
+	enabled, err := s.IsEnabled(ctx, email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	if enabled {
+		logger.L(ctx).Warn(ErrTwoFactorAlreadyEnabled.Error())
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	enrollment, err := s.s.Auth.GenerateTOTP(email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	if err = s.r.SaveTOTPSecret(ctx, email, enrollment.Secret); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return enrollment, nil
+}
+
+// Confirm включает 2FA, если код подходит к секрету из Enroll, и возвращает коды
+// восстановления. Коды показываются только один раз: в базе хранятся их хеши.
+func (s *TwoFactorService) Confirm(ctx context.Context, email, code string) ([]string, error) {
+	c, tx, err := s.r.WithTx(ctx)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			logger.L(ctx).Error(err.Error())
+		}
+	}()
+
+	totp, err := s.r.GetTOTPForUpdate(c, email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	if totp == nil {
+		logger.L(ctx).Warn(ErrTwoFactorNotEnrolled.Error())
+		return nil, ErrTwoFactorNotEnrolled
+	}
+
+	if totp.EnabledAt.Valid {
+		logger.L(ctx).Warn(ErrTwoFactorAlreadyEnabled.Error())
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok := s.s.Auth.ValidateTOTP(totp.Secret, normalizeTwoFactorCode(code), totp.LastUsedStep)
+	if !ok {
+		logger.L(ctx).Warn(ErrInvalidTwoFactorCode.Error())
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	if err = s.r.EnableTOTP(c, email, step); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for range recoveryCodeCount {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+		codes = append(codes, code)
+		hashes = append(hashes, hashToken(normalizeTwoFactorCode(code)))
+	}
+
+	if err = s.r.ReplaceRecoveryCodes(c, email, hashes); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	if err = tx.Commit(c); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Challenge выдаёт одноразовый токен второго шага входа
+func (s *TwoFactorService) Challenge(ctx context.Context, email string) (*models.TwoFactorChallenge, error) {
+	raw := make([]byte, challengeBytes)
+	if _, err := rand.Read(raw); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	expiresAt := time.Now().Add(DefaultChallengeTTL)
+
+	if err := s.r.CreateChallenge(ctx, hashToken(token), email, expiresAt); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return &models.TwoFactorChallenge{
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// Verify обменивает токен второго шага и код TOTP или код восстановления на пару токенов.
+// После MaxChallengeAttempts неверных кодов токен перестаёт действовать и вход нужно начинать заново.
+// Неверный код учитывается в LoginGuard как неудачный вход, верный — сбрасывает счётчик аккаунта.
+func (s *TwoFactorService) Verify(ctx context.Context, challengeToken, code, clientIP string) (*models.TokenPair, error) {
+	c, tx, err := s.r.WithTx(ctx)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			logger.L(ctx).Error(err.Error())
+		}
+	}()
+
+	tokenHash := hashToken(challengeToken)
+
+	challenge, err := s.r.GetChallengeForUpdate(c, tokenHash)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	if challenge == nil || challenge.UsedAt.Valid || !time.Now().Before(challenge.ExpiresAt.Time) {
+		logger.L(ctx).Warn(ErrChallengeInvalid.Error())
+		return nil, ErrChallengeInvalid
+	}
+
+	totp, err := s.r.GetTOTPForUpdate(c, challenge.Email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	if totp == nil || !totp.EnabledAt.Valid {
+		logger.L(ctx).Warn(ErrChallengeInvalid.Error())
+		return nil, ErrChallengeInvalid
+	}
+
+	ok, err := s.checkCode(c, totp, normalizeTwoFactorCode(code))
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	if !ok {
+		if err = s.r.IncrementChallengeAttempts(c, tokenHash); err != nil {
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+
+		if challenge.Attempts+1 >= MaxChallengeAttempts {
+			if err = s.r.MarkChallengeUsed(c, tokenHash); err != nil {
+				logger.L(ctx).Error(err.Error())
+				return nil, err
+			}
+		}
+
+		// Счётчик попыток фиксируется, несмотря на ошибку для клиента
+		if err = tx.Commit(c); err != nil {
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+
+		logger.L(ctx).Warn(ErrInvalidTwoFactorCode.Error(), zap.String("email", challenge.Email))
+
+		if err = s.s.LoginGuard.Fail(ctx, strings.ToLower(challenge.Email), clientIP); err != nil {
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	if err = s.r.MarkChallengeUsed(c, tokenHash); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	if err = tx.Commit(c); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	if err = s.s.LoginGuard.Succeed(ctx, strings.ToLower(challenge.Email)); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	tokens, err := s.s.Session.Start(ctx, challenge.Email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func NewTwoFactorService(r repository.TwoFactor, s *Service) *TwoFactorService {
+	return &TwoFactorService{
+		r: r,
+		s: s,
+	}
+}
+
+// checkCode принимает код TOTP из цифр либо код восстановления, который после этого
+// больше не действует
+func (s *TwoFactorService) checkCode(ctx context.Context, totp *db.AppAccountTotp, code string) (bool, error) {
+	if len(code) == pkg.TOTPDigits && strings.Trim(code, "0123456789") == "" {
+		step, ok := s.s.Auth.ValidateTOTP(totp.Secret, code, totp.LastUsedStep)
+		if !ok {
+			return false, nil
+		}
+
+		if err := s.r.UpdateTOTPLastUsedStep(ctx, totp.Email, step); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+
+	return s.r.UseRecoveryCode(ctx, totp.Email, hashToken(code))
+}
+
+// generateRecoveryCode возвращает код вида "abcde-fghij"
+func generateRecoveryCode() (string, error) {
+	raw := make([]byte, recoveryCodeBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	code := strings.ToLower(recoveryCodeEncoding.EncodeToString(raw))
+	half := len(code) / 2
+
+	return code[:half] + "-" + code[half:], nil
+}
+
+// normalizeTwoFactorCode убирает пробелы и дефисы, чтобы код можно было вводить в любом виде
+func normalizeTwoFactorCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
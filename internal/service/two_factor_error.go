@@ -0,0 +1,10 @@
+package service
+
+import "net/http"
+
+var (
+	ErrTwoFactorAlreadyEnabled = newError("two_factor_already_enabled", http.StatusConflict, "two-factor authentication is already enabled")
+	ErrTwoFactorNotEnrolled    = newError("two_factor_not_enrolled", http.StatusBadRequest, "two-factor authentication enrollment not started")
+	ErrInvalidTwoFactorCode    = newError("invalid_two_factor_code", http.StatusBadRequest, "invalid two-factor authentication code")
+	ErrChallengeInvalid        = newError("challenge_invalid", http.StatusUnauthorized, "invalid or expired login challenge")
+)
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"gw-currency-wallet/config"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/models"
+	mock_repository "gw-currency-wallet/internal/repository/mocks"
+	"gw-currency-wallet/pkg"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+const testTOTPSecret = "JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"
+
+func currentTestCode(t *testing.T) (string, int64) {
+	step := pkg.TOTPStep(time.Now())
+	code, err := pkg.TOTPCode(testTOTPSecret, step)
+	assert.NoError(t, err)
+	return code, step
+}
+
+func testChallenge(attempts int32) *db.AppLoginChallenge {
+	return &db.AppLoginChallenge{
+		TokenHash: hashToken("challenge"),
+		Email:     "user@example.com",
+		Attempts:  attempts,
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(time.Minute), Valid: true},
+	}
+}
+
+func testEnabledTOTP() *db.AppAccountTotp {
+	return &db.AppAccountTotp{
+		Email:     "user@example.com",
+		Secret:    testTOTPSecret,
+		EnabledAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}
+}
+
+func TestConfirm_ValidCode_EnablesAndReturnsRecoveryCodes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockTwoFactor(ctrl)
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+	s := newTestService()
+	withTestAccount(ctrl, s, models.RoleUser)
+	srv := NewTwoFactorService(mockRepo, s)
+	code, step := currentTestCode(t)
+
+	mockRepo.EXPECT().GetTOTPForUpdate(gomock.Any(), "user@example.com").Return(&db.AppAccountTotp{
+		Email:  "user@example.com",
+		Secret: testTOTPSecret,
+	}, nil)
+	mockRepo.EXPECT().EnableTOTP(gomock.Any(), "user@example.com", step).Return(nil)
+	mockRepo.EXPECT().ReplaceRecoveryCodes(gomock.Any(), "user@example.com", gomock.Len(recoveryCodeCount)).Return(nil)
+	mockTx.EXPECT().Commit(gomock.Any()).Return(nil)
+
+	codes, err := srv.Confirm(t.Context(), "user@example.com", code)
+
+	assert.NoError(t, err)
+	assert.Len(t, codes, recoveryCodeCount)
+	assert.Regexp(t, `^[a-z2-7]{5}-[a-z2-7]{5}$`, codes[0])
+}
+
+func TestConfirm_WrongCode_ReturnsInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockTwoFactor(ctrl)
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+	s := newTestService()
+	withTestAccount(ctrl, s, models.RoleUser)
+	srv := NewTwoFactorService(mockRepo, s)
+
+	mockRepo.EXPECT().GetTOTPForUpdate(gomock.Any(), "user@example.com").Return(&db.AppAccountTotp{
+		Email:  "user@example.com",
+		Secret: testTOTPSecret,
+	}, nil)
+
+	_, err := srv.Confirm(t.Context(), "user@example.com", "000000x")
+
+	assert.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+}
+
+func TestVerify_ValidTOTP_StartsSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockTwoFactor(ctrl)
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+	s := newTestService()
+	withTestAccount(ctrl, s, models.RoleUser)
+	srv := NewTwoFactorService(mockRepo, s)
+	mockAttempts := mock_repository.NewMockLoginAttempt(ctrl)
+	s.LoginGuard = NewLoginGuardService(mockAttempts, &config.AuthConfig{})
+	// Счётчик аккаунта сбрасывается только после второго шага
+	mockAttempts.EXPECT().Delete(gomock.Any(), models.LoginScopeAccount, "user@example.com").Return(nil)
+	code, step := currentTestCode(t)
+
+	mockSession := mock_repository.NewMockSession(ctrl)
+	sessionTx := mock_repository.NewMockTx(ctrl)
+	sessionTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+	sessionTx.EXPECT().Commit(gomock.Any()).Return(nil)
+	srv.s.Session = NewSessionService(mockSession, srv.s)
+
+	mockRepo.EXPECT().GetChallengeForUpdate(gomock.Any(), hashToken("challenge")).Return(testChallenge(0), nil)
+	mockRepo.EXPECT().GetTOTPForUpdate(gomock.Any(), "user@example.com").Return(testEnabledTOTP(), nil)
+	mockRepo.EXPECT().UpdateTOTPLastUsedStep(gomock.Any(), "user@example.com", step).Return(nil)
+	mockRepo.EXPECT().MarkChallengeUsed(gomock.Any(), hashToken("challenge")).Return(nil)
+	mockTx.EXPECT().Commit(gomock.Any()).Return(nil)
+
+	mockSession.EXPECT().WithTx(gomock.Any()).Return(t.Context(), sessionTx, nil)
+	mockSession.EXPECT().Create(gomock.Any(), "user@example.com").Return(testSession(t), nil)
+	mockSession.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	tokens, err := srv.Verify(t.Context(), "challenge", code, "10.0.0.1")
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+}
+
+func TestVerify_RecoveryCode_Accepted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockTwoFactor(ctrl)
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+	s := newTestService()
+	withTestAccount(ctrl, s, models.RoleUser)
+	srv := NewTwoFactorService(mockRepo, s)
+	mockAttempts := mock_repository.NewMockLoginAttempt(ctrl)
+	s.LoginGuard = NewLoginGuardService(mockAttempts, &config.AuthConfig{})
+	// Счётчик аккаунта сбрасывается только после второго шага
+	mockAttempts.EXPECT().Delete(gomock.Any(), models.LoginScopeAccount, "user@example.com").Return(nil)
+
+	mockRepo.EXPECT().GetChallengeForUpdate(gomock.Any(), hashToken("challenge")).Return(testChallenge(0), nil)
+	mockRepo.EXPECT().GetTOTPForUpdate(gomock.Any(), "user@example.com").Return(testEnabledTOTP(), nil)
+	// Код вводится в любом регистре и с дефисом, а хешируется в нормализованном виде
+	mockRepo.EXPECT().UseRecoveryCode(gomock.Any(), "user@example.com", hashToken("abcdefghij")).Return(true, nil)
+	mockRepo.EXPECT().MarkChallengeUsed(gomock.Any(), hashToken("challenge")).Return(nil)
+	mockTx.EXPECT().Commit(gomock.Any()).Return(nil)
+
+	mockSession := mock_repository.NewMockSession(ctrl)
+	sessionTx := mock_repository.NewMockTx(ctrl)
+	sessionTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+	sessionTx.EXPECT().Commit(gomock.Any()).Return(nil)
+	srv.s.Session = NewSessionService(mockSession, srv.s)
+	mockSession.EXPECT().WithTx(gomock.Any()).Return(t.Context(), sessionTx, nil)
+	mockSession.EXPECT().Create(gomock.Any(), "user@example.com").Return(testSession(t), nil)
+	mockSession.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	_, err := srv.Verify(t.Context(), "challenge", "ABCDE-FGHIJ", "10.0.0.1")
+
+	assert.NoError(t, err)
+}
+
+func TestVerify_LastWrongAttempt_ConsumesChallenge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockTwoFactor(ctrl)
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+	s := newTestService()
+	withTestAccount(ctrl, s, models.RoleUser)
+	srv := NewTwoFactorService(mockRepo, s)
+	mockAttempts := mock_repository.NewMockLoginAttempt(ctrl)
+	s.LoginGuard = NewLoginGuardService(mockAttempts, &config.AuthConfig{})
+	// Неверный код считается неудачным входом по аккаунту и IP-адресу
+	attemptsTx := mock_repository.NewMockTx(ctrl)
+	attemptsTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+	attemptsTx.EXPECT().Commit(gomock.Any()).Return(nil)
+	mockAttempts.EXPECT().WithTx(gomock.Any()).Return(t.Context(), attemptsTx, nil)
+	mockAttempts.EXPECT().RecordFailure(gomock.Any(), models.LoginScopeAccount, "user@example.com", gomock.Any()).
+		Return(&db.AppLoginAttempt{Scope: models.LoginScopeAccount, Subject: "user@example.com", Failures: 1}, nil)
+	mockAttempts.EXPECT().SetLockedUntil(gomock.Any(), models.LoginScopeAccount, "user@example.com", gomock.Any(), int32(1)).Return(nil)
+	mockAttempts.EXPECT().RecordFailure(gomock.Any(), models.LoginScopeIP, "10.0.0.1", gomock.Any()).
+		Return(&db.AppLoginAttempt{Scope: models.LoginScopeIP, Subject: "10.0.0.1", Failures: 1}, nil)
+	mockAttempts.EXPECT().SetLockedUntil(gomock.Any(), models.LoginScopeIP, "10.0.0.1", gomock.Any(), int32(1)).Return(nil)
+
+	mockRepo.EXPECT().GetChallengeForUpdate(gomock.Any(), hashToken("challenge")).Return(testChallenge(MaxChallengeAttempts-1), nil)
+	mockRepo.EXPECT().GetTOTPForUpdate(gomock.Any(), "user@example.com").Return(testEnabledTOTP(), nil)
+	mockRepo.EXPECT().UseRecoveryCode(gomock.Any(), "user@example.com", gomock.Any()).Return(false, nil)
+	mockRepo.EXPECT().IncrementChallengeAttempts(gomock.Any(), hashToken("challenge")).Return(nil)
+	mockRepo.EXPECT().MarkChallengeUsed(gomock.Any(), hashToken("challenge")).Return(nil)
+	mockTx.EXPECT().Commit(gomock.Any()).Return(nil)
+
+	_, err := srv.Verify(t.Context(), "challenge", "wrong-code", "10.0.0.1")
+
+	assert.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+}
+
+func TestVerify_ExpiredChallenge_ReturnsInvalid(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockTwoFactor(ctrl)
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+	s := newTestService()
+	withTestAccount(ctrl, s, models.RoleUser)
+	srv := NewTwoFactorService(mockRepo, s)
+
+	challenge := testChallenge(0)
+	challenge.ExpiresAt = pgtype.Timestamptz{Time: time.Now().Add(-time.Second), Valid: true}
+	mockRepo.EXPECT().GetChallengeForUpdate(gomock.Any(), hashToken("challenge")).Return(challenge, nil)
+
+	_, err := srv.Verify(t.Context(), "challenge", "123456", "10.0.0.1")
+
+	assert.ErrorIs(t, err, ErrChallengeInvalid)
+}
@@ -3,11 +3,14 @@ package service
 import (
 	"context"
 	"errors"
+	"gw-currency-wallet/internal/db"
+	"gw-currency-wallet/internal/logger"
+	"gw-currency-wallet/internal/models"
 	"gw-currency-wallet/internal/repository"
 	"gw-currency-wallet/pkg"
 
 	"github.com/jackc/pgx/v5"
-	"go.uber.org/zap"
+	"github.com/shopspring/decimal"
 )
 
 type WalletService struct {
@@ -15,113 +18,416 @@ type WalletService struct {
 	s *Service
 }
 
-func (s *WalletService) Exchange(ctx context.Context, email string, from, to pkg.Currency, amount float32) (exchangedAmount float32, wallets pkg.AccountWallets, err error) {
+// balanceRequest и exchangeRequest — параметры операций, по которым
+// вычисляется хэш запроса для ключа идемпотентности
+type balanceRequest struct {
+	Currency pkg.Currency `json:"currency"`
+	Amount   pkg.Amount   `json:"amount"`
+}
+
+type exchangeRequest struct {
+	From             pkg.Currency `json:"from"`
+	To               pkg.Currency `json:"to"`
+	Amount           pkg.Amount   `json:"amount"`
+	QuoteID          string       `json:"quote_id"`
+	MinReceiveAmount *pkg.Amount  `json:"min_receive_amount"`
+	MaxRate          *pkg.Rate    `json:"max_rate"`
+}
+
+type transferRequest struct {
+	Recipient string       `json:"recipient"`
+	Currency  pkg.Currency `json:"currency"`
+	Amount    pkg.Amount   `json:"amount"`
+}
+
+type exchangeResult struct {
+	Rate    pkg.Rate           `json:"rate"`
+	Gross   pkg.Amount         `json:"gross"`
+	Fee     pkg.Amount         `json:"fee"`
+	Net     pkg.Amount         `json:"net"`
+	Wallets pkg.AccountWallets `json:"wallets"`
+}
+
+func (s *WalletService) Exchange(ctx context.Context, email, idempotencyKey string, order models.ExchangeOrder) (amounts models.ExchangeAmounts, wallets pkg.AccountWallets, err error) {
 	c, tx, err := s.r.WithTx(ctx)
 	if err != nil {
-		zap.L().Error(err.Error())
-		return 0, nil, err
+		logger.L(ctx).Error(err.Error())
+		return models.ExchangeAmounts{}, nil, err
 	}
 
 	defer func() {
 		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
-			zap.L().Error(err.Error())
+			logger.L(ctx).Error(err.Error())
 		}
 	}()
 
-	rate, err := s.s.Exchange.GetRate(c, from, to)
-	if err != nil {
-		zap.L().Error(err.Error())
-		return 0, nil, err
-	}
+	request := exchangeRequest{
+		From:             order.From,
+		To:               order.To,
+		Amount:           order.Amount,
+		QuoteID:          order.QuoteID,
+		MinReceiveAmount: order.MinReceiveAmount,
+		MaxRate:          order.MaxRate,
+	}
+	// executed и проданная сумма нужны для метрик: повтор по ключу идемпотентности не учитывается
+	var executed bool
+	var sold pkg.Amount
+	var soldCurrency pkg.Currency
+	result, err := idempotent(c, s.s.Idempotency, email, idempotencyKey, models.TransactionExchange, request, func() (exchangeResult, error) {
+		from, to, amount := order.From, order.To, order.Amount
+
+		var rate pkg.Rate
+		var gross, fee pkg.Amount
+		if order.QuoteID != "" {
+			quote, err := s.s.Quote.Redeem(c, email, order.QuoteID)
+			if err != nil {
+				logger.L(ctx).Error(err.Error())
+				return exchangeResult{}, err
+			}
+
+			// Параметры в теле запроса необязательны, но если указаны, должны совпадать с котировкой
+			if (from != "" && from != quote.From) || (to != "" && to != quote.To) || (!amount.IsZero() && !amount.Equal(quote.Amount)) {
+				logger.L(ctx).Warn(ErrQuoteMismatch.Error())
+				return exchangeResult{}, ErrQuoteMismatch
+			}
+
+			from, to, amount = quote.From, quote.To, quote.Amount
+			rate, gross, fee = quote.Rate, quote.ReceiveAmount.Add(quote.Fee), quote.Fee
+		} else {
+			if _, err := s.s.Currency.Validate(c, from, amount); err != nil {
+				logger.L(ctx).Error(err.Error())
+				return exchangeResult{}, err
+			}
+
+			target, err := s.s.Currency.Resolve(c, to)
+			if err != nil {
+				logger.L(ctx).Error(err.Error())
+				return exchangeResult{}, err
+			}
+
+			rate, err = s.s.Exchange.GetRate(c, from, to)
+			if err != nil {
+				logger.L(ctx).Error(err.Error())
+				return exchangeResult{}, err
+			}
+
+			// Сумма к получению округляется вниз до точности валюты получения
+			gross = amount.Mul(rate).RoundDown(target.MinorUnits)
+
+			fee, err = s.s.Fee.Calculate(c, from, to, gross, target.MinorUnits)
+			if err != nil {
+				logger.L(ctx).Error(err.Error())
+				return exchangeResult{}, err
+			}
+		}
+		net := gross.Sub(fee)
 
-	exchangedAmount = amount * rate
+		if err := checkSlippage(order, rate, net); err != nil {
+			logger.L(ctx).Warn(err.Error())
+			return exchangeResult{}, err
+		}
 
-	if err = s.withdraw(c, email, from, amount); err != nil {
-		zap.L().Error(err.Error())
-		return 0, nil, err
-	}
+		if err := s.withdraw(c, email, from, amount); err != nil {
+			logger.L(ctx).Error(err.Error())
+			return exchangeResult{}, err
+		}
 
-	if err = s.deposit(c, email, to, exchangedAmount); err != nil {
-		zap.L().Error(err.Error())
-		return 0, nil, err
+		if err := s.deposit(c, email, to, net); err != nil {
+			logger.L(ctx).Error(err.Error())
+			return exchangeResult{}, err
+		}
+
+		entries := []models.LedgerEntry{
+			models.Debit(email, from, amount),
+			models.Credit(models.SystemExchangeAccount, from, amount),
+			models.Debit(models.SystemExchangeAccount, to, gross),
+			models.Credit(email, to, net),
+		}
+		if fee.IsPositive() {
+			entries = append(entries, models.Credit(models.SystemRevenueAccount, to, fee))
+		}
+
+		if _, err := s.s.Ledger.Post(c, models.TransactionExchange, entries...); err != nil {
+			logger.L(ctx).Error(err.Error())
+			return exchangeResult{}, err
+		}
+
+		wallets, err := s.accountWallets(c, email)
+		if err != nil {
+			logger.L(ctx).Error(err.Error())
+			return exchangeResult{}, err
+		}
+
+		executed, sold, soldCurrency = true, amount, from
+
+		return exchangeResult{Rate: rate, Gross: gross, Fee: fee, Net: net, Wallets: wallets}, nil
+	})
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return models.ExchangeAmounts{}, nil, err
 	}
 
 	if err = tx.Commit(c); err != nil {
-		zap.L().Error(err.Error())
-		return 0, nil, err
+		logger.L(ctx).Error(err.Error())
+		return models.ExchangeAmounts{}, nil, err
 	}
 
-	wallets, err = s.accountWallets(ctx, email)
+	if executed {
+		s.s.observeOperation(models.TransactionExchange, soldCurrency, sold)
+	}
 
-	return exchangedAmount, wallets, err
+	return models.ExchangeAmounts{
+		Rate:  result.Rate,
+		Gross: result.Gross,
+		Fee:   result.Fee,
+		Net:   result.Net,
+	}, result.Wallets, nil
 }
 
 func (s *WalletService) GetRates(ctx context.Context) (pkg.ExchangeRates, error) {
 	rates, err := s.s.Exchange.GetRates(ctx)
 	if err != nil {
-		zap.L().Error(err.Error())
+		logger.L(ctx).Error(err.Error())
 		return nil, err
 	}
 
 	return rates, err
 }
 
-func (s *WalletService) Withdraw(ctx context.Context, email string, currency pkg.Currency, amount float32) (pkg.AccountWallets, error) {
+func (s *WalletService) Withdraw(ctx context.Context, email, idempotencyKey string, currency pkg.Currency, amount pkg.Amount) (pkg.AccountWallets, error) {
 	c, tx, err := s.r.WithTx(ctx)
 	if err != nil {
-		zap.L().Error(err.Error())
+		logger.L(ctx).Error(err.Error())
 		return nil, err
 	}
 
 	defer func() {
 		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
-			zap.L().Error(err.Error())
+			logger.L(ctx).Error(err.Error())
 		}
 	}()
 
-	if err = s.withdraw(c, email, currency, amount); err != nil {
-		zap.L().Error(err.Error())
+	request := balanceRequest{Currency: currency, Amount: amount}
+	var executed bool
+	wallets, err := idempotent(c, s.s.Idempotency, email, idempotencyKey, models.TransactionWithdraw, request, func() (pkg.AccountWallets, error) {
+		executed = true
+
+		if err := s.withdraw(c, email, currency, amount); err != nil {
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+
+		if _, err := s.s.Ledger.Post(c, models.TransactionWithdraw,
+			models.Debit(email, currency, amount),
+			models.Credit(models.SystemCashAccount, currency, amount),
+		); err != nil {
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+
+		return s.accountWallets(c, email)
+	})
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
 		return nil, err
 	}
 
 	if err = tx.Commit(c); err != nil {
-		zap.L().Error(err.Error())
+		logger.L(ctx).Error(err.Error())
 		return nil, err
 	}
 
-	return s.accountWallets(ctx, email)
+	if executed {
+		s.s.observeOperation(models.TransactionWithdraw, currency, amount)
+	}
+
+	return wallets, nil
 }
 
-func (s *WalletService) Deposit(ctx context.Context, email string, currency pkg.Currency, amount float32) (pkg.AccountWallets, error) {
+func (s *WalletService) Deposit(ctx context.Context, email, idempotencyKey string, currency pkg.Currency, amount pkg.Amount) (pkg.AccountWallets, error) {
 	c, tx, err := s.r.WithTx(ctx)
 	if err != nil {
-		zap.L().Error(err.Error())
+		logger.L(ctx).Error(err.Error())
 		return nil, err
 	}
 
 	defer func() {
 		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
-			zap.L().Error(err.Error())
+			logger.L(ctx).Error(err.Error())
 		}
 	}()
 
-	if err = s.deposit(c, email, currency, amount); err != nil {
-		zap.L().Error(err.Error())
+	request := balanceRequest{Currency: currency, Amount: amount}
+	var executed bool
+	wallets, err := idempotent(c, s.s.Idempotency, email, idempotencyKey, models.TransactionDeposit, request, func() (pkg.AccountWallets, error) {
+		executed = true
+
+		if err := s.deposit(c, email, currency, amount); err != nil {
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+
+		if _, err := s.s.Ledger.Post(c, models.TransactionDeposit,
+			models.Debit(models.SystemCashAccount, currency, amount),
+			models.Credit(email, currency, amount),
+		); err != nil {
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+
+		return s.accountWallets(c, email)
+	})
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
 		return nil, err
 	}
 
 	if err = tx.Commit(c); err != nil {
-		zap.L().Error(err.Error())
+		logger.L(ctx).Error(err.Error())
 		return nil, err
 	}
 
-	return s.accountWallets(ctx, email)
+	if executed {
+		s.s.observeOperation(models.TransactionDeposit, currency, amount)
+	}
+
+	return wallets, nil
+}
+
+func (s *WalletService) Transfer(ctx context.Context, email, idempotencyKey, recipient string, currency pkg.Currency, amount pkg.Amount) (pkg.AccountWallets, error) {
+	c, tx, err := s.r.WithTx(ctx)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			logger.L(ctx).Error(err.Error())
+		}
+	}()
+
+	request := transferRequest{Recipient: recipient, Currency: currency, Amount: amount}
+	wallets, err := idempotent(c, s.s.Idempotency, email, idempotencyKey, models.TransactionTransfer, request, func() (pkg.AccountWallets, error) {
+		account, err := s.s.Account.Find(c, recipient)
+		if err != nil {
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+
+		if account == nil {
+			logger.L(ctx).Warn(ErrRecipientNotFound.Error())
+			return nil, ErrRecipientNotFound
+		}
+
+		if account.Email == email {
+			logger.L(ctx).Warn(ErrSelfTransfer.Error())
+			return nil, ErrSelfTransfer
+		}
+
+		if _, err = s.s.Currency.Validate(c, currency, amount); err != nil {
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+
+		// Кошельки блокируются в порядке email, чтобы встречные переводы
+		// между одними и теми же пользователями не приводили к взаимоблокировке
+		first, second := email, account.Email
+		if second < first {
+			first, second = second, first
+		}
+		for _, owner := range []string{first, second} {
+			if _, err = s.lockWallet(c, owner, currency); err != nil {
+				logger.L(ctx).Error(err.Error())
+				return nil, err
+			}
+		}
+
+		if err = s.withdraw(c, email, currency, amount); err != nil {
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+
+		if err = s.deposit(c, account.Email, currency, amount); err != nil {
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+
+		if _, err = s.s.Ledger.Post(c, models.TransactionTransfer,
+			models.Debit(email, currency, amount),
+			models.Credit(account.Email, currency, amount),
+		); err != nil {
+			logger.L(ctx).Error(err.Error())
+			return nil, err
+		}
+
+		return s.accountWallets(c, email)
+	})
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	if err = tx.Commit(c); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return nil, err
+	}
+
+	return wallets, nil
+}
+
+// Adjust вносит ручную корректировку: положительная сумма зачисляется на кошелёк,
+// отрицательная списывается. Собственной транзакции не открывает и должна вызываться
+// внутри транзакции, в которой корректировка записывается в журнал действий администраторов.
+func (s *WalletService) Adjust(ctx context.Context, email string, currency pkg.Currency, amount pkg.Amount) (int64, pkg.AccountWallets, error) {
+	var entries []models.LedgerEntry
+
+	switch {
+	case amount.IsZero():
+		logger.L(ctx).Warn(ErrZeroAmount.Error())
+		return 0, nil, ErrZeroAmount
+	case amount.IsPositive():
+		if err := s.deposit(ctx, email, currency, amount); err != nil {
+			logger.L(ctx).Error(err.Error())
+			return 0, nil, err
+		}
+
+		entries = []models.LedgerEntry{
+			models.Debit(models.SystemAdjustmentAccount, currency, amount),
+			models.Credit(email, currency, amount),
+		}
+	default:
+		amount = amount.Neg()
+		if err := s.withdraw(ctx, email, currency, amount); err != nil {
+			logger.L(ctx).Error(err.Error())
+			return 0, nil, err
+		}
+
+		entries = []models.LedgerEntry{
+			models.Debit(email, currency, amount),
+			models.Credit(models.SystemAdjustmentAccount, currency, amount),
+		}
+	}
+
+	transactionID, err := s.s.Ledger.Post(ctx, models.TransactionAdjustment, entries...)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return 0, nil, err
+	}
+
+	wallets, err := s.accountWallets(ctx, email)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return 0, nil, err
+	}
+
+	return transactionID, wallets, nil
 }
 
 func (s *WalletService) GetAllByEmail(ctx context.Context, email string) (pkg.AccountWallets, error) {
 	wallets, err := s.r.GetAllByEmail(ctx, email)
 	if err != nil {
-		zap.L().Error(err.Error())
+		logger.L(ctx).Error(err.Error())
 		return nil, err
 	}
 
@@ -144,116 +450,92 @@ func NewWalletService(r repository.Wallet, s *Service) *WalletService {
 	}
 }
 
-func (s *WalletService) deposit(ctx context.Context, email string, currency pkg.Currency, amount float32) error {
-	isExistsCurrency, err := s.s.Exchange.IsExistCurrency(ctx, currency)
-	if err != nil {
-		zap.L().Error(err.Error())
+func (s *WalletService) deposit(ctx context.Context, email string, currency pkg.Currency, amount pkg.Amount) error {
+	if _, err := s.s.Currency.Validate(ctx, currency, amount); err != nil {
+		logger.L(ctx).Error(err.Error())
 		return err
 	}
 
-	if !isExistsCurrency {
-		zap.L().Error(ErrNonExistentCurrency.Error())
-		return ErrNonExistentCurrency
+	wallet, err := s.lockWallet(ctx, email, currency)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
 	}
 
-	isExistWallet, err := s.r.IsExistCurrency(ctx, email, currency)
+	newBalance := wallet.Balance.Add(amount)
+
+	_, err = s.r.Update(ctx, email, currency, newBalance)
 	if err != nil {
-		zap.L().Error(err.Error())
+		logger.L(ctx).Error(err.Error())
 		return err
 	}
 
-	if !isExistWallet {
-		if err = s.r.Create(ctx, email, currency); err != nil {
-			zap.L().Error(err.Error())
-			return err
-		}
+	return nil
+}
+
+func (s *WalletService) withdraw(ctx context.Context, email string, currency pkg.Currency, amount pkg.Amount) error {
+	if _, err := s.s.Currency.Validate(ctx, currency, amount); err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
 	}
 
-	wallet, err := s.r.GetForUpdate(ctx, email, currency)
+	wallet, err := s.lockWallet(ctx, email, currency)
 	if err != nil {
-		zap.L().Error(err.Error())
+		logger.L(ctx).Error(err.Error())
 		return err
 	}
-
-	if amount == 0 {
-		zap.L().Error(ErrZeroAmount.Error())
-		return ErrZeroAmount
+	// Зарезервированные холдами средства списывать нельзя
+	available, err := s.available(ctx, wallet)
+	if err != nil {
+		logger.L(ctx).Error(err.Error())
+		return err
 	}
-	if amount < 0 {
-		zap.L().Error(ErrNegativeAmount.Error())
-		return ErrNegativeAmount
+
+	if available.LessThan(amount) {
+		logger.L(ctx).Error(ErrInsufficientBalance.Error())
+		return ErrInsufficientBalance
 	}
 
-	newBalance := wallet.Balance + amount
+	newBalance := wallet.Balance.Sub(amount)
 
 	_, err = s.r.Update(ctx, email, currency, newBalance)
 	if err != nil {
-		zap.L().Error(err.Error())
+		logger.L(ctx).Error(err.Error())
 		return err
 	}
 
 	return nil
 }
 
-func (s *WalletService) withdraw(ctx context.Context, email string, currency pkg.Currency, amount float32) error {
-	isExistsCurrency, err := s.s.Exchange.IsExistCurrency(ctx, currency)
-	if err != nil {
-		zap.L().Error(err.Error())
-		return err
-	}
-
-	if !isExistsCurrency {
-		zap.L().Error(ErrNonExistentCurrency.Error())
-		return ErrNonExistentCurrency
-	}
-
+// lockWallet блокирует кошелёк до конца транзакции, создавая его при необходимости.
+// Валюта должна быть проверена вызывающей стороной.
+func (s *WalletService) lockWallet(ctx context.Context, email string, currency pkg.Currency) (*db.AppWallet, error) {
 	isExistWallet, err := s.r.IsExistCurrency(ctx, email, currency)
 	if err != nil {
-		zap.L().Error(err.Error())
-		return err
+		logger.L(ctx).Error(err.Error())
+		return nil, err
 	}
 
 	if !isExistWallet {
 		if err = s.r.Create(ctx, email, currency); err != nil {
-			zap.L().Error(err.Error())
-			return err
+			logger.L(ctx).Error(err.Error())
+			return nil, err
 		}
 	}
 
 	wallet, err := s.r.GetForUpdate(ctx, email, currency)
 	if err != nil {
-		zap.L().Error(err.Error())
-		return err
-	}
-
-	if amount == 0 {
-		zap.L().Error(ErrZeroAmount.Error())
-		return ErrZeroAmount
-	}
-	if amount < 0 {
-		zap.L().Error(ErrNegativeAmount.Error())
-		return ErrNegativeAmount
-	}
-	if wallet.Balance < amount {
-		zap.L().Error(ErrInsufficientBalance.Error())
-		return ErrInsufficientBalance
-	}
-
-	newBalance := wallet.Balance - amount
-
-	_, err = s.r.Update(ctx, email, currency, newBalance)
-	if err != nil {
-		zap.L().Error(err.Error())
-		return err
+		logger.L(ctx).Error(err.Error())
+		return nil, err
 	}
 
-	return nil
+	return wallet, nil
 }
 
 func (s *WalletService) accountWallets(ctx context.Context, email string) (pkg.AccountWallets, error) {
 	wallets, err := s.r.GetAllByEmail(ctx, email)
 	if err != nil {
-		zap.L().Error(err.Error())
+		logger.L(ctx).Error(err.Error())
 		return nil, err
 	}
 
@@ -264,3 +546,34 @@ func (s *WalletService) accountWallets(ctx context.Context, email string) (pkg.A
 
 	return result, nil
 }
+
+// checkSlippage проверяет необязательные ограничения клиента на курс и
+// сумму к получению. rate — единиц to за единицу from, а MaxRate — цена единицы to
+// в единицах from, поэтому предел нарушен, когда 1/rate > MaxRate, то есть rate*MaxRate < 1.
+func checkSlippage(order models.ExchangeOrder, rate pkg.Rate, exchangedAmount pkg.Amount) error {
+	if order.MaxRate != nil && rate.Mul(*order.MaxRate).LessThan(decimal.NewFromInt(1)) {
+		return ErrSlippageExceeded
+	}
+
+	if order.MinReceiveAmount != nil && exchangedAmount.LessThan(*order.MinReceiveAmount) {
+		return ErrSlippageExceeded
+	}
+
+	return nil
+}
+
+func validateAmount(amount pkg.Amount) error {
+	if amount.IsZero() {
+		return ErrZeroAmount
+	}
+
+	if amount.IsNegative() {
+		return ErrNegativeAmount
+	}
+
+	if !amount.Equal(amount.Truncate(pkg.AmountScale)) {
+		return ErrAmountPrecision
+	}
+
+	return nil
+}
@@ -1,10 +1,14 @@
 package service
 
-import "errors"
+import "net/http"
 
 var (
-	ErrNegativeAmount      = errors.New("amount cannot be negative")
-	ErrZeroAmount          = errors.New("amount cannot be zero")
-	ErrNonExistentCurrency = errors.New("currency does not exist")
-	ErrInsufficientBalance = errors.New("insufficient balance")
+	ErrNegativeAmount      = newError("negative_amount", http.StatusBadRequest, "amount cannot be negative")
+	ErrZeroAmount          = newError("zero_amount", http.StatusBadRequest, "amount cannot be zero")
+	ErrNonExistentCurrency = newError("unknown_currency", http.StatusBadRequest, "currency does not exist")
+	ErrInsufficientBalance = newError("insufficient_balance", http.StatusBadRequest, "insufficient balance")
+	ErrAmountPrecision     = newError("amount_precision", http.StatusBadRequest, "amount has too many decimal places")
+	ErrRecipientNotFound   = newError("recipient_not_found", http.StatusNotFound, "recipient not found")
+	ErrSelfTransfer        = newError("self_transfer", http.StatusBadRequest, "cannot transfer to yourself")
+	ErrSlippageExceeded    = newError("slippage_exceeded", http.StatusConflict, "exchange rate moved beyond the requested limits")
 )
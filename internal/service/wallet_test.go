@@ -1,34 +1,49 @@
 package service
 
 import (
+	"context"
 	"gw-currency-wallet/internal/db"
-	gw_grpc "gw-currency-wallet/internal/pb/exchange"
-	"gw-currency-wallet/internal/pb/exchange/mocks"
+	"gw-currency-wallet/internal/models"
 	mock_repository "gw-currency-wallet/internal/repository/mocks"
 	"gw-currency-wallet/pkg"
 	"testing"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
 
+// newTestCurrencyService возвращает справочник валют, в котором есть только USD
+func newTestCurrencyService(ctrl *gomock.Controller) *CurrencyService {
+	mockCurrency := mock_repository.NewMockCurrency(ctrl)
+	mockCurrency.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, code string) (*db.AppCurrency, error) {
+		if code != "USD" {
+			return nil, nil
+		}
+		return &db.AppCurrency{Code: "USD", Name: "US Dollar", MinorUnits: 2, Symbol: "$", Enabled: true}, nil
+	}).AnyTimes()
+
+	return NewCurrencyService(mockCurrency)
+}
+
 func TestDeposit_PositiveAmount_IncreasesBalance(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockGrpcExchange := mocks.NewMockExchangeServiceClient(ctrl)
-	mockGrpcExchange.EXPECT().GetExchangeRates(t.Context(), nil).Return(&gw_grpc.ExchangeRatesResponse{Rates: pkg.ExchangeRates{"USD": 1}}, nil)
-
 	mockRepo := mock_repository.NewMockWallet(ctrl)
+	mockLedger := mock_repository.NewMockLedger(ctrl)
 	s := &Service{
-		Exchange: NewExchangeService(t.Context(), mockGrpcExchange),
+		Currency: newTestCurrencyService(ctrl),
+		Ledger:   NewLedgerService(mockLedger),
 	}
 	srv := NewWalletService(mockRepo, s)
 
 	email := "user@example.com"
 	currency := "USD"
-	amount := float32(100)
-	initialBalance := float32(0)
+	amount := decimal.NewFromInt(100)
+	initialBalance := decimal.NewFromInt(0)
 
 	mockTx := mock_repository.NewMockTx(ctrl)
 	mockTx.EXPECT().Commit(gomock.Any()).Return(nil).Times(1)
@@ -44,17 +59,21 @@ func TestDeposit_PositiveAmount_IncreasesBalance(t *testing.T) {
 		Balance:  initialBalance,
 	}, nil)
 
-	mockRepo.EXPECT().Update(t.Context(), email, currency, initialBalance+amount).Return(nil, nil)
+	mockRepo.EXPECT().Update(t.Context(), email, currency, initialBalance.Add(amount)).Return(nil, nil)
+
+	mockLedger.EXPECT().CreateTransaction(t.Context(), models.TransactionDeposit).Return(&db.AppTransaction{ID: 1}, nil)
+	mockLedger.EXPECT().CreateEntry(t.Context(), int64(1), models.SystemCashAccount, currency, models.EntryDebit, amount).Return(nil)
+	mockLedger.EXPECT().CreateEntry(t.Context(), int64(1), email, currency, models.EntryCredit, amount).Return(nil)
 
 	mockRepo.EXPECT().GetAllByEmail(gomock.Any(), email).Return([]db.AppWallet{
 		{
 			Email:    email,
 			Currency: currency,
-			Balance:  initialBalance + amount,
+			Balance:  initialBalance.Add(amount),
 		},
 	}, nil)
 
-	result, err := srv.Deposit(t.Context(), email, currency, amount)
+	result, err := srv.Deposit(t.Context(), email, "", currency, amount)
 
 	assert.NoError(t, err)
 	assert.Equal(t, amount, result[currency])
@@ -64,33 +83,22 @@ func TestDeposit_ZeroAmount_ReturnsError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockGrpcExchange := mocks.NewMockExchangeServiceClient(ctrl)
-	mockGrpcExchange.EXPECT().GetExchangeRates(t.Context(), nil).Return(&gw_grpc.ExchangeRatesResponse{Rates: pkg.ExchangeRates{"USD": 1}}, nil)
-
 	mockRepo := mock_repository.NewMockWallet(ctrl)
 	s := &Service{
-		Exchange: NewExchangeService(t.Context(), mockGrpcExchange),
+		Currency: newTestCurrencyService(ctrl),
 	}
 	srv := NewWalletService(mockRepo, s)
 
 	email := "user@example.com"
 	currency := "USD"
-	amount := float32(0)
+	amount := decimal.NewFromInt(0)
 
 	mockTx := mock_repository.NewMockTx(ctrl)
 	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
 
 	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
 
-	mockRepo.EXPECT().IsExistCurrency(t.Context(), email, currency).Return(true, nil)
-
-	mockRepo.EXPECT().GetForUpdate(t.Context(), email, currency).Return(&db.AppWallet{
-		Email:    email,
-		Currency: currency,
-		Balance:  0,
-	}, nil)
-
-	_, err := srv.Deposit(t.Context(), email, currency, amount)
+	_, err := srv.Deposit(t.Context(), email, "", currency, amount)
 
 	assert.ErrorIs(t, err, ErrZeroAmount)
 }
@@ -99,20 +107,45 @@ func TestDeposit_NegativeAmount_ReturnsError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockGrpcExchange := mocks.NewMockExchangeServiceClient(ctrl)
-	mockGrpcExchange.EXPECT().GetExchangeRates(t.Context(), nil).Return(&gw_grpc.ExchangeRatesResponse{Rates: pkg.ExchangeRates{"USD": 1}}, nil)
+	mockRepo := mock_repository.NewMockWallet(ctrl)
+	s := &Service{
+		Currency: newTestCurrencyService(ctrl),
+	}
+	srv := NewWalletService(mockRepo, s)
+
+	email := "user@example.com"
+	currency := "USD"
+	amount := decimal.NewFromInt(-1)
+
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+	_, err := srv.Deposit(t.Context(), email, "", currency, amount)
+
+	assert.ErrorIs(t, err, ErrNegativeAmount)
+}
+
+func TestDeposit_FractionalAmount_KeepsExactBalance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
 	mockRepo := mock_repository.NewMockWallet(ctrl)
+	mockLedger := mock_repository.NewMockLedger(ctrl)
 	s := &Service{
-		Exchange: NewExchangeService(t.Context(), mockGrpcExchange),
+		Currency: newTestCurrencyService(ctrl),
+		Ledger:   NewLedgerService(mockLedger),
 	}
 	srv := NewWalletService(mockRepo, s)
 
 	email := "user@example.com"
 	currency := "USD"
-	amount := float32(-1)
+	amount := decimal.RequireFromString("0.1")
+	initialBalance := decimal.RequireFromString("0.2")
 
 	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Commit(gomock.Any()).Return(nil).Times(1)
 	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
 
 	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
@@ -122,31 +155,110 @@ func TestDeposit_NegativeAmount_ReturnsError(t *testing.T) {
 	mockRepo.EXPECT().GetForUpdate(t.Context(), email, currency).Return(&db.AppWallet{
 		Email:    email,
 		Currency: currency,
-		Balance:  0,
+		Balance:  initialBalance,
 	}, nil)
 
-	_, err := srv.Deposit(t.Context(), email, currency, amount)
+	// 0.2 + 0.1 должно быть ровно 0.3, без двоичной погрешности
+	mockRepo.EXPECT().Update(t.Context(), email, currency, gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ string, _ pkg.Currency, newValue pkg.Amount) (*db.AppWallet, error) {
+			assert.Equal(t, "0.3", newValue.String())
+			return nil, nil
+		})
 
-	assert.ErrorIs(t, err, ErrNegativeAmount)
+	mockLedger.EXPECT().CreateTransaction(t.Context(), models.TransactionDeposit).Return(&db.AppTransaction{ID: 1}, nil)
+	mockLedger.EXPECT().CreateEntry(t.Context(), int64(1), models.SystemCashAccount, currency, models.EntryDebit, amount).Return(nil)
+	mockLedger.EXPECT().CreateEntry(t.Context(), int64(1), email, currency, models.EntryCredit, amount).Return(nil)
+
+	mockRepo.EXPECT().GetAllByEmail(gomock.Any(), email).Return(nil, nil)
+
+	_, err := srv.Deposit(t.Context(), email, "", currency, amount)
+
+	assert.NoError(t, err)
 }
 
-func TestWithdraw_PositiveAmount_DecreasesBalance(t *testing.T) {
+func TestDeposit_TooPreciseAmount_ReturnsError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockGrpcExchange := mocks.NewMockExchangeServiceClient(ctrl)
-	mockGrpcExchange.EXPECT().GetExchangeRates(t.Context(), nil).Return(&gw_grpc.ExchangeRatesResponse{Rates: pkg.ExchangeRates{"USD": 1}}, nil)
+	mockRepo := mock_repository.NewMockWallet(ctrl)
+	s := &Service{
+		Currency: newTestCurrencyService(ctrl),
+	}
+	srv := NewWalletService(mockRepo, s)
+
+	email := "user@example.com"
+	currency := "USD"
+	amount := decimal.RequireFromString("0.000000001")
+
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+	_, err := srv.Deposit(t.Context(), email, "", currency, amount)
+
+	assert.ErrorIs(t, err, ErrAmountPrecision)
+}
+
+func TestDeposit_AmountBeyondCurrencyPrecision_ReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockWallet(ctrl)
+	s := &Service{
+		Currency: newTestCurrencyService(ctrl),
+	}
+	srv := NewWalletService(mockRepo, s)
+
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+	// У USD два знака после запятой
+	_, err := srv.Deposit(t.Context(), "user@example.com", "", "USD", decimal.RequireFromString("1.001"))
+
+	assert.ErrorIs(t, err, ErrAmountPrecision)
+}
+
+func TestDeposit_DisabledCurrency_ReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
 	mockRepo := mock_repository.NewMockWallet(ctrl)
+	mockCurrency := mock_repository.NewMockCurrency(ctrl)
 	s := &Service{
-		Exchange: NewExchangeService(t.Context(), mockGrpcExchange),
+		Currency: NewCurrencyService(mockCurrency),
+	}
+	srv := NewWalletService(mockRepo, s)
+
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+	mockCurrency.EXPECT().Get(t.Context(), "ZWG").Return(&db.AppCurrency{Code: "ZWG", MinorUnits: 2, Enabled: false}, nil)
+
+	_, err := srv.Deposit(t.Context(), "user@example.com", "", "ZWG", decimal.NewFromInt(10))
+
+	assert.ErrorIs(t, err, ErrCurrencyDisabled)
+}
+
+func TestWithdraw_PositiveAmount_DecreasesBalance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockWallet(ctrl)
+	mockLedger := mock_repository.NewMockLedger(ctrl)
+	s := &Service{
+		Currency: newTestCurrencyService(ctrl),
+		Ledger:   NewLedgerService(mockLedger),
 	}
 	srv := NewWalletService(mockRepo, s)
 
 	email := "user@example.com"
 	currency := "USD"
-	amount := float32(20)
-	initialBalance := float32(100)
+	amount := decimal.NewFromInt(20)
+	initialBalance := decimal.NewFromInt(100)
 
 	mockTx := mock_repository.NewMockTx(ctrl)
 	mockTx.EXPECT().Commit(gomock.Any()).Return(nil).Times(1)
@@ -162,38 +274,90 @@ func TestWithdraw_PositiveAmount_DecreasesBalance(t *testing.T) {
 		Balance:  initialBalance,
 	}, nil)
 
-	mockRepo.EXPECT().Update(t.Context(), email, currency, initialBalance-amount).Return(nil, nil)
+	mockRepo.EXPECT().GetHeldAmount(t.Context(), email, currency).Return(decimal.Zero, nil)
+
+	mockRepo.EXPECT().Update(t.Context(), email, currency, initialBalance.Sub(amount)).Return(nil, nil)
+
+	mockLedger.EXPECT().CreateTransaction(t.Context(), models.TransactionWithdraw).Return(&db.AppTransaction{ID: 1}, nil)
+	mockLedger.EXPECT().CreateEntry(t.Context(), int64(1), email, currency, models.EntryDebit, amount).Return(nil)
+	mockLedger.EXPECT().CreateEntry(t.Context(), int64(1), models.SystemCashAccount, currency, models.EntryCredit, amount).Return(nil)
 
 	mockRepo.EXPECT().GetAllByEmail(gomock.Any(), email).Return([]db.AppWallet{
 		{
 			Email:    email,
 			Currency: currency,
-			Balance:  initialBalance - amount,
+			Balance:  initialBalance.Sub(amount),
 		},
 	}, nil)
 
-	result, err := srv.Withdraw(t.Context(), email, currency, amount)
+	result, err := srv.Withdraw(t.Context(), email, "", currency, amount)
 
 	assert.NoError(t, err)
-	assert.Equal(t, initialBalance-amount, result[currency])
+	assert.Equal(t, initialBalance.Sub(amount), result[currency])
 }
 
 func TestWithdraw_ZeroAmount_ReturnsError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockGrpcExchange := mocks.NewMockExchangeServiceClient(ctrl)
-	mockGrpcExchange.EXPECT().GetExchangeRates(t.Context(), nil).Return(&gw_grpc.ExchangeRatesResponse{Rates: pkg.ExchangeRates{"USD": 1}}, nil)
+	mockRepo := mock_repository.NewMockWallet(ctrl)
+	s := &Service{
+		Currency: newTestCurrencyService(ctrl),
+	}
+	srv := NewWalletService(mockRepo, s)
+
+	email := "user@example.com"
+	currency := "USD"
+	amount := decimal.NewFromInt(0)
+
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+	_, err := srv.Withdraw(t.Context(), email, "", currency, amount)
+
+	assert.ErrorIs(t, err, ErrZeroAmount)
+}
+
+func TestWithdraw_NegativeAmount_ReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockWallet(ctrl)
+	s := &Service{
+		Currency: newTestCurrencyService(ctrl),
+	}
+	srv := NewWalletService(mockRepo, s)
+
+	email := "user@example.com"
+	currency := "USD"
+	amount := decimal.NewFromInt(-1)
+
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+	_, err := srv.Withdraw(t.Context(), email, "", currency, amount)
+
+	assert.ErrorIs(t, err, ErrNegativeAmount)
+}
+
+func TestWithdraw_InsufficientBalance_ReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
 
 	mockRepo := mock_repository.NewMockWallet(ctrl)
 	s := &Service{
-		Exchange: NewExchangeService(t.Context(), mockGrpcExchange),
+		Currency: newTestCurrencyService(ctrl),
 	}
 	srv := NewWalletService(mockRepo, s)
 
 	email := "user@example.com"
 	currency := "USD"
-	amount := float32(0)
+	amount := decimal.NewFromInt(101)
+	initialBalance := decimal.NewFromInt(100)
 
 	mockTx := mock_repository.NewMockTx(ctrl)
 	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
@@ -205,81 +369,466 @@ func TestWithdraw_ZeroAmount_ReturnsError(t *testing.T) {
 	mockRepo.EXPECT().GetForUpdate(t.Context(), email, currency).Return(&db.AppWallet{
 		Email:    email,
 		Currency: currency,
-		Balance:  0,
+		Balance:  initialBalance,
 	}, nil)
 
-	_, err := srv.Withdraw(t.Context(), email, currency, amount)
+	mockRepo.EXPECT().GetHeldAmount(t.Context(), email, currency).Return(decimal.Zero, nil)
 
-	assert.ErrorIs(t, err, ErrZeroAmount)
+	_, err := srv.Withdraw(t.Context(), email, "", currency, amount)
+
+	assert.ErrorIs(t, err, ErrInsufficientBalance)
 }
 
-func TestWithdraw_NegativeAmount_ReturnsError(t *testing.T) {
+func TestWithdraw_HeldFunds_ReturnsError(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockGrpcExchange := mocks.NewMockExchangeServiceClient(ctrl)
-	mockGrpcExchange.EXPECT().GetExchangeRates(t.Context(), nil).Return(&gw_grpc.ExchangeRatesResponse{Rates: pkg.ExchangeRates{"USD": 1}}, nil)
-
 	mockRepo := mock_repository.NewMockWallet(ctrl)
 	s := &Service{
-		Exchange: NewExchangeService(t.Context(), mockGrpcExchange),
+		Currency: newTestCurrencyService(ctrl),
 	}
 	srv := NewWalletService(mockRepo, s)
 
 	email := "user@example.com"
 	currency := "USD"
-	amount := float32(-1)
 
 	mockTx := mock_repository.NewMockTx(ctrl)
 	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
 
 	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
-
 	mockRepo.EXPECT().IsExistCurrency(t.Context(), email, currency).Return(true, nil)
-
 	mockRepo.EXPECT().GetForUpdate(t.Context(), email, currency).Return(&db.AppWallet{
 		Email:    email,
 		Currency: currency,
-		Balance:  0,
+		Balance:  decimal.NewFromInt(100),
 	}, nil)
 
-	_, err := srv.Withdraw(t.Context(), email, currency, amount)
+	// Общий баланс 100, но 60 зарезервировано холдом
+	mockRepo.EXPECT().GetHeldAmount(t.Context(), email, currency).Return(decimal.NewFromInt(60), nil)
 
-	assert.ErrorIs(t, err, ErrNegativeAmount)
+	_, err := srv.Withdraw(t.Context(), email, "", currency, decimal.NewFromInt(50))
+
+	assert.ErrorIs(t, err, ErrInsufficientBalance)
 }
 
-func TestWithdraw_InsufficientBalance_ReturnsError(t *testing.T) {
+func TestDeposit_RepeatedIdempotencyKey_ReplaysResponse(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockGrpcExchange := mocks.NewMockExchangeServiceClient(ctrl)
-	mockGrpcExchange.EXPECT().GetExchangeRates(t.Context(), nil).Return(&gw_grpc.ExchangeRatesResponse{Rates: pkg.ExchangeRates{"USD": 1}}, nil)
-
 	mockRepo := mock_repository.NewMockWallet(ctrl)
+	mockIdempotency := mock_repository.NewMockIdempotency(ctrl)
 	s := &Service{
-		Exchange: NewExchangeService(t.Context(), mockGrpcExchange),
+		Currency:    newTestCurrencyService(ctrl),
+		Idempotency: NewIdempotencyService(mockIdempotency),
 	}
 	srv := NewWalletService(mockRepo, s)
 
 	email := "user@example.com"
 	currency := "USD"
-	amount := float32(101)
-	initialBalance := float32(100)
+	amount := decimal.NewFromInt(100)
+	key := "retry-key"
+
+	hash, err := hashRequest(models.TransactionDeposit, balanceRequest{Currency: currency, Amount: amount})
+	assert.NoError(t, err)
+
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Commit(gomock.Any()).Return(nil).Times(1)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+	// Баланс не меняется: ни блокировки кошелька, ни обновления нет
+	mockIdempotency.EXPECT().Claim(t.Context(), email, key, models.TransactionDeposit, hash).Return(false, nil)
+	mockIdempotency.EXPECT().Get(t.Context(), email, key).Return(&db.AppIdempotencyKey{
+		Email:       email,
+		Key:         key,
+		Operation:   models.TransactionDeposit,
+		RequestHash: hash,
+		Response:    []byte(`{"USD":"100"}`),
+	}, nil)
+
+	result, err := srv.Deposit(t.Context(), email, key, currency, amount)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "100", result[currency].String())
+}
+
+func TestTransfer_ToOtherUser_LocksWalletsInEmailOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockWallet(ctrl)
+	mockAccountRepo := mock_repository.NewMockAccount(ctrl)
+	mockLedger := mock_repository.NewMockLedger(ctrl)
+	s := &Service{
+		Currency: newTestCurrencyService(ctrl),
+		Ledger:   NewLedgerService(mockLedger),
+	}
+	s.Account = NewAccountService(mockAccountRepo, s)
+	srv := NewWalletService(mockRepo, s)
+
+	sender := "bob@example.com"
+	recipient := "alice@example.com"
+	currency := "USD"
+	amount := decimal.NewFromInt(30)
 
 	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Commit(gomock.Any()).Return(nil).Times(1)
 	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
 
 	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
 
-	mockRepo.EXPECT().IsExistCurrency(t.Context(), email, currency).Return(true, nil)
+	mockAccountRepo.EXPECT().GetByUsername(t.Context(), "alice").Return(&db.AppAccount{
+		Email:    recipient,
+		Username: "alice",
+	}, nil)
 
-	mockRepo.EXPECT().GetForUpdate(t.Context(), email, currency).Return(&db.AppWallet{
-		Email:    email,
+	// Кошелёк получателя создаётся при первом переводе
+	mockRepo.EXPECT().IsExistCurrency(t.Context(), recipient, currency).Return(false, nil).Times(1)
+	mockRepo.EXPECT().Create(t.Context(), recipient, currency).Return(nil)
+	mockRepo.EXPECT().IsExistCurrency(t.Context(), recipient, currency).Return(true, nil).AnyTimes()
+	mockRepo.EXPECT().IsExistCurrency(t.Context(), sender, currency).Return(true, nil).AnyTimes()
+
+	// alice@ < bob@, поэтому кошелёк получателя блокируется первым
+	lockRecipient := mockRepo.EXPECT().GetForUpdate(t.Context(), recipient, currency).Return(&db.AppWallet{
+		Email:    recipient,
 		Currency: currency,
-		Balance:  initialBalance,
+		Balance:  decimal.Zero,
+	}, nil).Times(1)
+	mockRepo.EXPECT().GetForUpdate(t.Context(), sender, currency).Return(&db.AppWallet{
+		Email:    sender,
+		Currency: currency,
+		Balance:  decimal.NewFromInt(100),
+	}, nil).After(lockRecipient).Times(2)
+	mockRepo.EXPECT().GetForUpdate(t.Context(), recipient, currency).Return(&db.AppWallet{
+		Email:    recipient,
+		Currency: currency,
+		Balance:  decimal.Zero,
+	}, nil).Times(1)
+
+	mockRepo.EXPECT().GetHeldAmount(t.Context(), sender, currency).Return(decimal.Zero, nil)
+
+	mockRepo.EXPECT().Update(t.Context(), sender, currency, decimal.NewFromInt(70)).Return(nil, nil)
+	mockRepo.EXPECT().Update(t.Context(), recipient, currency, decimal.NewFromInt(30)).Return(nil, nil)
+
+	mockLedger.EXPECT().CreateTransaction(t.Context(), models.TransactionTransfer).Return(&db.AppTransaction{ID: 1}, nil)
+	mockLedger.EXPECT().CreateEntry(t.Context(), int64(1), sender, currency, models.EntryDebit, amount).Return(nil)
+	mockLedger.EXPECT().CreateEntry(t.Context(), int64(1), recipient, currency, models.EntryCredit, amount).Return(nil)
+
+	mockRepo.EXPECT().GetAllByEmail(gomock.Any(), sender).Return([]db.AppWallet{
+		{
+			Email:    sender,
+			Currency: currency,
+			Balance:  decimal.NewFromInt(70),
+		},
 	}, nil)
 
-	_, err := srv.Withdraw(t.Context(), email, currency, amount)
+	result, err := srv.Transfer(t.Context(), sender, "", "alice", currency, amount)
 
-	assert.ErrorIs(t, err, ErrInsufficientBalance)
+	assert.NoError(t, err)
+	assert.Equal(t, "70", result[currency].String())
+}
+
+func TestTransfer_ToSelf_ReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockWallet(ctrl)
+	mockAccountRepo := mock_repository.NewMockAccount(ctrl)
+	s := &Service{}
+	s.Account = NewAccountService(mockAccountRepo, s)
+	srv := NewWalletService(mockRepo, s)
+
+	email := "user@example.com"
+
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+	mockAccountRepo.EXPECT().GetByEmail(t.Context(), email).Return(&db.AppAccount{
+		Email:    email,
+		Username: "user",
+	}, nil)
+
+	_, err := srv.Transfer(t.Context(), email, "", email, "USD", decimal.NewFromInt(10))
+
+	assert.ErrorIs(t, err, ErrSelfTransfer)
+}
+
+func TestTransfer_UnknownRecipient_ReturnsError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockWallet(ctrl)
+	mockAccountRepo := mock_repository.NewMockAccount(ctrl)
+	s := &Service{}
+	s.Account = NewAccountService(mockAccountRepo, s)
+	srv := NewWalletService(mockRepo, s)
+
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+	mockAccountRepo.EXPECT().GetByUsername(t.Context(), "ghost").Return(nil, nil)
+
+	_, err := srv.Transfer(t.Context(), "user@example.com", "", "ghost", "USD", decimal.NewFromInt(10))
+
+	assert.ErrorIs(t, err, ErrRecipientNotFound)
+}
+
+func TestCheckSlippage(t *testing.T) {
+	// не дороже 1.25 from за единицу to, то есть курс не ниже 0.8 to за единицу from
+	maxRate := decimal.RequireFromString("1.25")
+	minReceive := decimal.RequireFromString("9.1")
+
+	tests := []struct {
+		name     string
+		order    models.ExchangeOrder
+		rate     string
+		received string
+		wantErr  error
+	}{
+		{name: "no limits", order: models.ExchangeOrder{}, rate: "5", received: "1"},
+		{name: "rate at limit", order: models.ExchangeOrder{MaxRate: &maxRate}, rate: "0.8", received: "8"},
+		{name: "rate moved in user's favour", order: models.ExchangeOrder{MaxRate: &maxRate}, rate: "0.85", received: "8.5"},
+		{name: "rate moved against user", order: models.ExchangeOrder{MaxRate: &maxRate}, rate: "0.79", received: "7.9", wantErr: ErrSlippageExceeded},
+		{name: "receive within limit", order: models.ExchangeOrder{MinReceiveAmount: &minReceive}, rate: "0.91", received: "9.1"},
+		{name: "receive below limit", order: models.ExchangeOrder{MinReceiveAmount: &minReceive}, rate: "0.9", received: "9", wantErr: ErrSlippageExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkSlippage(tt.order, decimal.RequireFromString(tt.rate), decimal.RequireFromString(tt.received))
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+// testExchangeRate отдаёт фиксированный курс вместо сервиса курсов
+type testExchangeRate struct {
+	Exchange
+	rate pkg.Rate
+}
+
+func (e testExchangeRate) GetRate(context.Context, pkg.Currency, pkg.Currency) (pkg.Rate, error) {
+	return e.rate, nil
+}
+
+// newTestExchangeCurrencyService возвращает справочник валют с USD и EUR
+func newTestExchangeCurrencyService(ctrl *gomock.Controller) *CurrencyService {
+	mockCurrency := mock_repository.NewMockCurrency(ctrl)
+	mockCurrency.EXPECT().Get(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, code string) (*db.AppCurrency, error) {
+		switch code {
+		case "USD", "EUR":
+			return &db.AppCurrency{Code: code, MinorUnits: 2, Enabled: true}, nil
+		default:
+			return nil, nil
+		}
+	}).AnyTimes()
+
+	return NewCurrencyService(mockCurrency)
+}
+
+// expectExchangeWallets ожидает блокировку кошельков email с начальными балансами balances
+// и возвращает итоговые балансы, которые сервис записал в кошельки
+func expectExchangeWallets(mockRepo *mock_repository.MockWallet, email string, balances map[pkg.Currency]pkg.Amount) pkg.AccountWallets {
+	updated := make(pkg.AccountWallets)
+
+	mockRepo.EXPECT().IsExistCurrency(gomock.Any(), email, gomock.Any()).Return(true, nil).AnyTimes()
+	mockRepo.EXPECT().GetForUpdate(gomock.Any(), email, gomock.Any()).DoAndReturn(func(_ context.Context, email string, currency pkg.Currency) (*db.AppWallet, error) {
+		return &db.AppWallet{Email: email, Currency: currency, Balance: balances[currency]}, nil
+	}).AnyTimes()
+	mockRepo.EXPECT().GetHeldAmount(gomock.Any(), email, gomock.Any()).Return(decimal.Zero, nil).AnyTimes()
+	mockRepo.EXPECT().Update(gomock.Any(), email, gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _ string, currency pkg.Currency, balance pkg.Amount) (*db.AppWallet, error) {
+		updated[currency] = balance
+		return nil, nil
+	}).AnyTimes()
+	mockRepo.EXPECT().GetAllByEmail(gomock.Any(), email).DoAndReturn(func(_ context.Context, email string) ([]db.AppWallet, error) {
+		wallets := make([]db.AppWallet, 0, len(updated))
+		for currency, balance := range updated {
+			wallets = append(wallets, db.AppWallet{Email: email, Currency: currency, Balance: balance})
+		}
+		return wallets, nil
+	}).AnyTimes()
+
+	return updated
+}
+
+// expectLedgerEntries ожидает одну проводку и возвращает её записи
+func expectLedgerEntries(mockLedger *mock_repository.MockLedger, txType models.TransactionType) *[]models.LedgerEntry {
+	var entries []models.LedgerEntry
+
+	mockLedger.EXPECT().CreateTransaction(gomock.Any(), txType).Return(&db.AppTransaction{ID: 1}, nil)
+	mockLedger.EXPECT().CreateEntry(gomock.Any(), int64(1), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ int64, account string, currency pkg.Currency, direction string, amount pkg.Amount) error {
+			entries = append(entries, models.LedgerEntry{Account: account, Currency: currency, Direction: direction, Amount: amount})
+			return nil
+		}).AnyTimes()
+
+	return &entries
+}
+
+func TestExchange_WithFee_PostsRevenueEntry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockWallet(ctrl)
+	mockLedger := mock_repository.NewMockLedger(ctrl)
+	mockFee := mock_repository.NewMockFee(ctrl)
+	s := &Service{
+		Currency: newTestExchangeCurrencyService(ctrl),
+		Exchange: testExchangeRate{rate: decimal.RequireFromString("0.9")},
+		Fee:      NewFeeService(mockFee),
+		Ledger:   NewLedgerService(mockLedger),
+	}
+	srv := NewWalletService(mockRepo, s)
+
+	email := "user@example.com"
+
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Commit(gomock.Any()).Return(nil)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+	// 1% от 90 EUR
+	mockFee.EXPECT().Get(gomock.Any(), "USD", "EUR").Return(&db.AppExchangeFee{SpreadPercent: decimal.NewFromInt(1)}, nil)
+	updated := expectExchangeWallets(mockRepo, email, map[pkg.Currency]pkg.Amount{
+		"USD": decimal.NewFromInt(150),
+		"EUR": decimal.NewFromInt(10),
+	})
+	entries := expectLedgerEntries(mockLedger, models.TransactionExchange)
+
+	amounts, wallets, err := srv.Exchange(t.Context(), email, "", models.ExchangeOrder{
+		From:   "USD",
+		To:     "EUR",
+		Amount: decimal.NewFromInt(100),
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "90", amounts.Gross.String())
+	assert.Equal(t, "0.9", amounts.Fee.String())
+	assert.Equal(t, "89.1", amounts.Net.String())
+	assert.True(t, amounts.Net.Add(amounts.Fee).Equal(amounts.Gross))
+
+	assert.Equal(t, "50", updated["USD"].String())
+	assert.Equal(t, "99.1", updated["EUR"].String())
+	assert.Equal(t, "50", wallets["USD"].String())
+	assert.Equal(t, "99.1", wallets["EUR"].String())
+
+	// Валовая сумма списывается со счёта обмена и делится между клиентом и счётом доходов
+	assert.Len(t, *entries, 5)
+	assert.Contains(t, *entries, models.Debit(models.SystemExchangeAccount, "EUR", amounts.Gross))
+	assert.Contains(t, *entries, models.Credit(email, "EUR", amounts.Net))
+	assert.Contains(t, *entries, models.Credit(models.SystemRevenueAccount, "EUR", amounts.Fee))
+}
+
+func TestExchange_WithQuote_UsesQuotedRate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock_repository.NewMockWallet(ctrl)
+	mockLedger := mock_repository.NewMockLedger(ctrl)
+	mockQuote := mock_repository.NewMockQuote(ctrl)
+	s := &Service{
+		Currency: newTestExchangeCurrencyService(ctrl),
+		// Текущий курс ушёл от котировки, но обмен идёт по курсу котировки
+		Exchange: testExchangeRate{rate: decimal.RequireFromString("0.5")},
+		Fee:      NewFeeService(mock_repository.NewMockFee(ctrl)),
+		Ledger:   NewLedgerService(mockLedger),
+	}
+	s.Quote = NewQuoteService(mockQuote, s)
+	srv := NewWalletService(mockRepo, s)
+
+	email := "user@example.com"
+
+	mockTx := mock_repository.NewMockTx(ctrl)
+	mockTx.EXPECT().Commit(gomock.Any()).Return(nil)
+	mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+	mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+	row := testQuoteRow(t, time.Now().Add(time.Minute))
+	row.ReceiveAmount = decimal.RequireFromString("8.9")
+	row.Fee = decimal.RequireFromString("0.1")
+	mockQuote.EXPECT().GetForUpdate(gomock.Any(), testQuoteID, email).Return(row, nil)
+	mockQuote.EXPECT().MarkUsed(gomock.Any(), row.ID).Return(nil)
+
+	updated := expectExchangeWallets(mockRepo, email, map[pkg.Currency]pkg.Amount{"USD": decimal.NewFromInt(100)})
+	entries := expectLedgerEntries(mockLedger, models.TransactionExchange)
+
+	amounts, _, err := srv.Exchange(t.Context(), email, "", models.ExchangeOrder{QuoteID: testQuoteID})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "0.9", amounts.Rate.String())
+	assert.Equal(t, "9", amounts.Gross.String())
+	assert.Equal(t, "0.1", amounts.Fee.String())
+	assert.Equal(t, "8.9", amounts.Net.String())
+	assert.Equal(t, "90", updated["USD"].String())
+	assert.Equal(t, "8.9", updated["EUR"].String())
+	assert.Len(t, *entries, 5)
+}
+
+func TestExchange_WithUnusableQuote_ReturnsError(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		row     func(t *testing.T) *db.AppExchangeQuote
+		wantErr error
+	}{
+		{
+			name:    "expired",
+			email:   "user@example.com",
+			row:     func(t *testing.T) *db.AppExchangeQuote { return testQuoteRow(t, time.Now().Add(-time.Second)) },
+			wantErr: ErrQuoteExpired,
+		},
+		{
+			name:  "already used",
+			email: "user@example.com",
+			row: func(t *testing.T) *db.AppExchangeQuote {
+				row := testQuoteRow(t, time.Now().Add(time.Minute))
+				row.UsedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+				return row
+			},
+			wantErr: ErrQuoteAlreadyUsed,
+		},
+		{
+			// Котировка ищется вместе с email, поэтому чужая не находится
+			name:    "another account",
+			email:   "bob@example.com",
+			row:     func(*testing.T) *db.AppExchangeQuote { return nil },
+			wantErr: ErrQuoteNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// Кошельки и журнал не трогаются, транзакция не фиксируется
+			mockRepo := mock_repository.NewMockWallet(ctrl)
+			mockQuote := mock_repository.NewMockQuote(ctrl)
+			s := &Service{
+				Currency: newTestExchangeCurrencyService(ctrl),
+				Ledger:   NewLedgerService(mock_repository.NewMockLedger(ctrl)),
+			}
+			s.Quote = NewQuoteService(mockQuote, s)
+			srv := NewWalletService(mockRepo, s)
+
+			mockTx := mock_repository.NewMockTx(ctrl)
+			mockTx.EXPECT().Rollback(gomock.Any()).AnyTimes()
+			mockRepo.EXPECT().WithTx(gomock.Any()).Return(t.Context(), mockTx, nil)
+
+			mockQuote.EXPECT().GetForUpdate(gomock.Any(), testQuoteID, tt.email).Return(tt.row(t), nil)
+
+			_, _, err := srv.Exchange(t.Context(), tt.email, "", models.ExchangeOrder{QuoteID: testQuoteID})
+
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
 }
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- float4 -> text даёт кратчайшее десятичное представление, поэтому
+-- накопленный двоичный шум не переносится в numeric
+ALTER TABLE app.wallet
+    ALTER COLUMN balance DROP DEFAULT,
+    ALTER COLUMN balance TYPE NUMERIC(36, 8) USING ROUND(balance::text::numeric, 8),
+    ALTER COLUMN balance SET DEFAULT 0;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE app.wallet
+    ALTER COLUMN balance DROP DEFAULT,
+    ALTER COLUMN balance TYPE FLOAT4 USING balance::float4,
+    ALTER COLUMN balance SET DEFAULT 0;
+
+-- +goose StatementEnd
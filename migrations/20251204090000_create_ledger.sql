@@ -0,0 +1,56 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE app.transaction (
+    id BIGSERIAL PRIMARY KEY,
+    type VARCHAR(32) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE app.ledger_entry (
+    id BIGSERIAL PRIMARY KEY,
+    transaction_id BIGINT NOT NULL REFERENCES app.transaction(id) ON DELETE RESTRICT,
+    account VARCHAR(255) NOT NULL,
+    currency VARCHAR(16) NOT NULL,
+    direction VARCHAR(6) NOT NULL CHECK (direction IN ('debit', 'credit')),
+    amount NUMERIC(36, 8) NOT NULL CHECK (amount > 0)
+);
+
+CREATE INDEX ledger_entry_transaction_idx ON app.ledger_entry (transaction_id);
+CREATE INDEX ledger_entry_account_idx ON app.ledger_entry (account, currency);
+
+-- Остатки, накопленные до появления журнала, переносятся отдельной проводкой,
+-- чтобы сумма записей по кошельку совпадала с app.wallet.balance
+DO $$
+DECLARE
+    w RECORD;
+    tx_id BIGINT;
+BEGIN
+    FOR w IN SELECT email, currency, balance FROM app.wallet WHERE balance > 0 LOOP
+        INSERT INTO app.transaction (type) VALUES ('opening_balance') RETURNING id INTO tx_id;
+        INSERT INTO app.ledger_entry (transaction_id, account, currency, direction, amount)
+        VALUES (tx_id, 'system:cash', w.currency, 'debit', w.balance),
+               (tx_id, w.email, w.currency, 'credit', w.balance);
+    END LOOP;
+END $$;
+
+-- Сверка: баланс кошелька является проекцией журнала и должен совпадать с ним
+CREATE VIEW app.wallet_reconciliation AS
+SELECT w.email,
+       w.currency,
+       w.balance,
+       COALESCE(SUM(CASE WHEN e.direction = 'credit' THEN e.amount ELSE -e.amount END), 0)::NUMERIC(36, 8) AS ledger_balance
+FROM app.wallet w
+LEFT JOIN app.ledger_entry e ON e.account = w.email AND e.currency = w.currency
+GROUP BY w.email, w.currency, w.balance;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP VIEW IF EXISTS app.wallet_reconciliation;
+DROP TABLE IF EXISTS app.ledger_entry;
+DROP TABLE IF EXISTS app.transaction;
+
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE app.idempotency_key (
+    email VARCHAR(255) NOT NULL REFERENCES app.account(email) ON DELETE CASCADE,
+    key VARCHAR(255) NOT NULL,
+    operation VARCHAR(32) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    response JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (email, key)
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS app.idempotency_key;
+
+-- +goose StatementEnd
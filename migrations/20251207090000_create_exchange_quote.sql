@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+
+CREATE TABLE app.exchange_quote (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email VARCHAR(255) NOT NULL REFERENCES app.account(email) ON DELETE CASCADE,
+    from_currency VARCHAR(16) NOT NULL,
+    to_currency VARCHAR(16) NOT NULL,
+    amount NUMERIC(36, 8) NOT NULL,
+    rate NUMERIC(36, 18) NOT NULL,
+    receive_amount NUMERIC(36, 8) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS app.exchange_quote;
+
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Комиссии за обмен по валютным парам. '*' в from_currency или to_currency
+-- означает любую валюту; точное совпадение пары имеет приоритет.
+CREATE TABLE app.exchange_fee (
+    from_currency VARCHAR(16) NOT NULL,
+    to_currency VARCHAR(16) NOT NULL,
+    spread_percent NUMERIC(9, 6) NOT NULL DEFAULT 0 CHECK (spread_percent >= 0 AND spread_percent < 100),
+    fixed_fee NUMERIC(36, 8) NOT NULL DEFAULT 0 CHECK (fixed_fee >= 0),
+    min_fee NUMERIC(36, 8) NOT NULL DEFAULT 0 CHECK (min_fee >= 0),
+    PRIMARY KEY (from_currency, to_currency)
+);
+
+INSERT INTO app.exchange_fee (from_currency, to_currency)
+VALUES ('*', '*');
+
+ALTER TABLE app.exchange_quote
+    ADD COLUMN fee NUMERIC(36, 8) NOT NULL DEFAULT 0;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+ALTER TABLE app.exchange_quote
+    DROP COLUMN IF EXISTS fee;
+
+DROP TABLE IF EXISTS app.exchange_fee;
+
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Холды резервируют часть баланса кошелька. Активный холд уменьшает
+-- доступный баланс, но не общий; после expires_at холд перестаёт действовать.
+CREATE TABLE app.hold (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email VARCHAR(255) NOT NULL,
+    currency VARCHAR(16) NOT NULL,
+    amount NUMERIC(36, 8) NOT NULL CHECK (amount > 0),
+    captured_amount NUMERIC(36, 8) NOT NULL DEFAULT 0 CHECK (captured_amount >= 0 AND captured_amount <= amount),
+    status VARCHAR(16) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'captured', 'voided')),
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    FOREIGN KEY (email, currency) REFERENCES app.wallet (email, currency) ON DELETE CASCADE
+);
+
+CREATE INDEX hold_active_idx ON app.hold (email, currency, expires_at) WHERE status = 'active';
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS app.hold;
+
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Сессия объединяет цепочку refresh-токенов одного входа. Отзыв сессии
+-- делает недействительными и её access-токены.
+CREATE TABLE app.session (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email VARCHAR(255) NOT NULL REFERENCES app.account(email) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    revoked_at TIMESTAMPTZ
+);
+
+CREATE INDEX session_active_idx ON app.session (email) WHERE revoked_at IS NULL;
+
+-- Хранится только SHA-256 от refresh-токена. used_at заполняется при ротации;
+-- повторное предъявление использованного токена отзывает всю сессию.
+CREATE TABLE app.refresh_token (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    session_id UUID NOT NULL REFERENCES app.session(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX refresh_token_session_idx ON app.refresh_token (session_id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS app.refresh_token;
+DROP TABLE IF EXISTS app.session;
+
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Имя пользователя и email уникальны без учёта регистра. Email остаётся первичным ключом
+-- в том виде, в котором его ввели при регистрации. Если в таблице уже есть дубликаты,
+-- миграция завершится ошибкой: их нужно разрешить вручную.
+CREATE UNIQUE INDEX account_username_lower_key ON app.account (lower(username));
+CREATE UNIQUE INDEX account_email_lower_key ON app.account (lower(email));
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP INDEX IF EXISTS app.account_email_lower_key;
+DROP INDEX IF EXISTS app.account_username_lower_key;
+
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Счётчики неудачных попыток входа по аккаунту (scope = 'account') и по IP-адресу (scope = 'ip').
+-- locked_until — до какого момента вход запрещён: после задержки или блокировки.
+CREATE TABLE app.login_attempt (
+    scope VARCHAR(16) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    failures INT NOT NULL DEFAULT 0,
+    last_failed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    locked_until TIMESTAMPTZ,
+    PRIMARY KEY (scope, subject)
+);
+
+CREATE TABLE app.lockout_event (
+    id BIGSERIAL PRIMARY KEY,
+    scope VARCHAR(16) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    failures INT NOT NULL,
+    locked_until TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    unlocked_at TIMESTAMPTZ,
+    unlocked_by VARCHAR(255)
+);
+
+CREATE INDEX lockout_event_subject_idx ON app.lockout_event (scope, subject, id DESC);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS app.lockout_event;
+DROP TABLE IF EXISTS app.login_attempt;
+
+-- +goose StatementEnd
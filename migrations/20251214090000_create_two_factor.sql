@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Секрет TOTP аккаунта. Пока enabled_at не задан, включение не подтверждено первым кодом.
+-- last_used_step — номер интервала последнего принятого кода, защищает от повторного использования кода.
+CREATE TABLE app.account_totp (
+    email VARCHAR(255) PRIMARY KEY REFERENCES app.account(email) ON DELETE CASCADE,
+    secret VARCHAR(64) NOT NULL,
+    enabled_at TIMESTAMPTZ,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Одноразовые коды восстановления, хранятся только хеши
+CREATE TABLE app.recovery_code (
+    email VARCHAR(255) NOT NULL REFERENCES app.account(email) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (email, code_hash)
+);
+
+-- Токен второго шага входа, выдаётся после проверки пароля
+CREATE TABLE app.login_challenge (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    email VARCHAR(255) NOT NULL REFERENCES app.account(email) ON DELETE CASCADE,
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS app.login_challenge;
+DROP TABLE IF EXISTS app.recovery_code;
+DROP TABLE IF EXISTS app.account_totp;
+
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Токены сброса пароля. Хранятся только хеши; токен одноразовый и действует до expires_at.
+CREATE TABLE app.password_reset_token (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    email VARCHAR(255) NOT NULL REFERENCES app.account(email) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX password_reset_token_email_idx ON app.password_reset_token (email) WHERE used_at IS NULL;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS app.password_reset_token;
+
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Подтверждение email. Аккаунты, созданные до появления проверки, считаются подтверждёнными.
+ALTER TABLE app.account ADD COLUMN verified_at TIMESTAMPTZ;
+UPDATE app.account SET verified_at = now();
+
+-- Токены подтверждения email. Хранятся только хеши; created_at используется
+-- для ограничения частоты повторной отправки письма.
+CREATE TABLE app.email_verification_token (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    email VARCHAR(255) NOT NULL REFERENCES app.account(email) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX email_verification_token_email_idx ON app.email_verification_token (email, created_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS app.email_verification_token;
+ALTER TABLE app.account DROP COLUMN IF EXISTS verified_at;
+
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Роль определяет права в административном API. Замороженный аккаунт может входить
+-- и смотреть баланс, но не может совершать операции со средствами.
+ALTER TABLE app.account
+    ADD COLUMN role VARCHAR(16) NOT NULL DEFAULT 'user' CHECK (role IN ('user', 'support', 'admin')),
+    ADD COLUMN frozen_at TIMESTAMPTZ;
+
+-- Журнал действий администраторов. Причина обязательна для каждой записи.
+CREATE TABLE app.admin_audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor VARCHAR(255) NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    reason TEXT NOT NULL CHECK (reason <> ''),
+    details JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX admin_audit_log_subject_idx ON app.admin_audit_log (subject, id);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS app.admin_audit_log;
+ALTER TABLE app.account
+    DROP COLUMN IF EXISTS frozen_at,
+    DROP COLUMN IF EXISTS role;
+
+-- +goose StatementEnd
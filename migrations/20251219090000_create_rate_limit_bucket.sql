@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- Корзины ограничения частоты запросов (token bucket), общие для всех экземпляров.
+-- key — группа маршрутов и email или IP-адрес клиента, allowed — пропущен ли последний запрос.
+CREATE TABLE app.rate_limit_bucket (
+    key VARCHAR(320) PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    allowed BOOLEAN NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX rate_limit_bucket_updated_at_idx ON app.rate_limit_bucket (updated_at);
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+
+DROP TABLE IF EXISTS app.rate_limit_bucket;
+
+-- +goose StatementEnd
@@ -12,6 +12,27 @@ const (
 	DefaultCacherTTL = 5 * time.Second
 )
 
+// CacherObserver получает события кеша: обращение к свежим данным (Hit), к устаревшим (Miss)
+// и завершение обновления с его длительностью и ошибкой
+type CacherObserver interface {
+	Hit()
+	Miss()
+	Refreshed(duration time.Duration, err error)
+}
+
+type CacherOption func(*cacherOptions)
+
+type cacherOptions struct {
+	observer CacherObserver
+}
+
+// WithCacherObserver передаёт события кеша observer, например для метрик
+func WithCacherObserver(observer CacherObserver) CacherOption {
+	return func(o *cacherOptions) {
+		o.observer = observer
+	}
+}
+
 type Cacher[T any] struct {
 	data     T
 	updateFn UpdateFn[T]
@@ -19,19 +40,30 @@ type Cacher[T any] struct {
 	lastSync time.Time
 	mu       sync.Mutex
 	cond     *sync.Cond
+	observer CacherObserver
 }
 
-func NewCacher[T any](ctx context.Context, updateFn UpdateFn[T], ttl time.Duration) (*Cacher[T], error) {
-	data, err := updateFn(ctx)
-	if err != nil {
-		return nil, err
+func NewCacher[T any](ctx context.Context, updateFn UpdateFn[T], ttl time.Duration, opts ...CacherOption) (*Cacher[T], error) {
+	var options cacherOptions
+	for _, opt := range opts {
+		opt(&options)
 	}
-	return &Cacher[T]{
-		data:     data,
+
+	c := &Cacher[T]{
 		updateFn: updateFn,
 		ttl:      ttl,
-		lastSync: time.Now(),
-	}, nil
+		observer: options.observer,
+	}
+
+	data, err := c.update(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.data = data
+	c.lastSync = time.Now()
+
+	return c, nil
 }
 
 func (c *Cacher[T]) GetData(ctx context.Context) (T, error) {
@@ -39,9 +71,16 @@ func (c *Cacher[T]) GetData(ctx context.Context) (T, error) {
 	defer c.mu.Unlock()
 
 	if time.Since(c.lastSync) < c.ttl {
+		if c.observer != nil {
+			c.observer.Hit()
+		}
 		return c.data, nil
 	}
 
+	if c.observer != nil {
+		c.observer.Miss()
+	}
+
 	if c.cond != nil {
 		c.cond.Wait()
 		return c.data, nil
@@ -51,7 +90,7 @@ func (c *Cacher[T]) GetData(ctx context.Context) (T, error) {
 	cond := c.cond
 	c.mu.Unlock()
 
-	data, err := c.updateFn(ctx)
+	data, err := c.update(ctx)
 
 	c.mu.Lock()
 	c.cond = nil
@@ -79,7 +118,7 @@ func (c *Cacher[T]) ForceSync(ctx context.Context) (T, error) {
 	cond := c.cond
 	c.mu.Unlock()
 
-	data, err := c.updateFn(ctx)
+	data, err := c.update(ctx)
 
 	c.mu.Lock()
 	c.cond = nil
@@ -94,3 +133,15 @@ func (c *Cacher[T]) ForceSync(ctx context.Context) (T, error) {
 
 	return result, err
 }
+
+// update вызывает updateFn и сообщает наблюдателю длительность и результат обновления
+func (c *Cacher[T]) update(ctx context.Context) (T, error) {
+	start := time.Now()
+	data, err := c.updateFn(ctx)
+
+	if c.observer != nil {
+		c.observer.Refreshed(time.Since(start), err)
+	}
+
+	return data, err
+}
@@ -198,3 +198,43 @@ func TestCacher_ForceSync_Concurrent(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, 2, val)
 }
+
+type countingObserver struct {
+	hits, misses, refreshes, errors int
+}
+
+func (o *countingObserver) Hit()  { o.hits++ }
+func (o *countingObserver) Miss() { o.misses++ }
+func (o *countingObserver) Refreshed(_ time.Duration, err error) {
+	o.refreshes++
+	if err != nil {
+		o.errors++
+	}
+}
+
+func TestCacher_Observer(t *testing.T) {
+	fail := false
+	updateFn := func(ctx context.Context) (int, error) {
+		if fail {
+			return 0, errors.New("update failed")
+		}
+		return 1, nil
+	}
+
+	observer := &countingObserver{}
+	c, err := NewCacher(t.Context(), updateFn, 50*time.Millisecond, WithCacherObserver(observer))
+	assert.NoError(t, err)
+
+	_, _ = c.GetData(t.Context())
+
+	time.Sleep(60 * time.Millisecond)
+	fail = true
+	_, err = c.GetData(t.Context())
+	assert.Error(t, err)
+
+	// Начальная загрузка и неудачное обновление
+	assert.Equal(t, 1, observer.hits)
+	assert.Equal(t, 1, observer.misses)
+	assert.Equal(t, 2, observer.refreshes)
+	assert.Equal(t, 1, observer.errors)
+}
@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"gw-currency-wallet/pkg"
+	"time"
+)
+
+type cacherObserver struct {
+	m    *Metrics
+	name string
+}
+
+// CacherObserver наблюдатель для pkg.WithCacherObserver, который пишет события кеша name в метрики
+func (m *Metrics) CacherObserver(name string) pkg.CacherObserver {
+	return &cacherObserver{m: m, name: name}
+}
+
+func (o *cacherObserver) Hit() {
+	o.m.cacheHits.WithLabelValues(o.name).Inc()
+}
+
+func (o *cacherObserver) Miss() {
+	o.m.cacheMisses.WithLabelValues(o.name).Inc()
+}
+
+func (o *cacherObserver) Refreshed(duration time.Duration, err error) {
+	o.m.cacheRefreshDuration.WithLabelValues(o.name).Observe(duration.Seconds())
+	if err != nil {
+		o.m.cacheRefreshErrors.WithLabelValues(o.name).Inc()
+	}
+}
@@ -0,0 +1,25 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryClientInterceptor считает исходящие gRPC-вызовы, их ошибки и длительность по методу
+func (m *Metrics) UnaryClientInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+
+	code := status.Code(err)
+	m.grpcDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	m.grpcRequests.WithLabelValues(method, code.String()).Inc()
+	if code != codes.OK {
+		m.grpcErrors.WithLabelValues(method, code.String()).Inc()
+	}
+
+	return err
+}
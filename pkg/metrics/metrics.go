@@ -0,0 +1,131 @@
+// Package metrics метрики Prometheus для HTTP-сервера на gin, операций со средствами,
+// кеша pkg.Cacher, gRPC-клиента и пула pgxpool. Метрики регистрируются в собственном
+// реестре, поэтому пакет можно подключать в любом бинарнике.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+type Metrics struct {
+	namespace string
+	registry  *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+
+	operations      *prometheus.CounterVec
+	operationVolume *prometheus.CounterVec
+
+	cacheHits            *prometheus.CounterVec
+	cacheMisses          *prometheus.CounterVec
+	cacheRefreshErrors   *prometheus.CounterVec
+	cacheRefreshDuration *prometheus.HistogramVec
+
+	grpcRequests *prometheus.CounterVec
+	grpcErrors   *prometheus.CounterVec
+	grpcDuration *prometheus.HistogramVec
+}
+
+// New создаёт метрики с префиксом namespace и регистрирует их вместе с метриками
+// процесса и среды Go в новом реестре
+func New(namespace string) *Metrics {
+	m := &Metrics{
+		namespace: namespace,
+		registry:  prometheus.NewRegistry(),
+
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests by route, method and status.",
+		}, []string{"route", "method", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by route, method and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+
+		operations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "operations_total",
+			Help:      "Number of completed balance operations by operation and currency.",
+		}, []string{"operation", "currency"}),
+		operationVolume: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "operation_volume_total",
+			Help:      "Sum of amounts of completed balance operations by operation and currency.",
+		}, []string{"operation", "currency"}),
+
+		cacheHits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_hits_total",
+			Help:      "Number of cache reads served from fresh data.",
+		}, []string{"cache"}),
+		cacheMisses: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_misses_total",
+			Help:      "Number of cache reads that found stale data.",
+		}, []string{"cache"}),
+		cacheRefreshErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_refresh_errors_total",
+			Help:      "Number of failed cache refreshes.",
+		}, []string{"cache"}),
+		cacheRefreshDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "cache_refresh_duration_seconds",
+			Help:      "Cache refresh latency.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"cache"}),
+
+		grpcRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_client_requests_total",
+			Help:      "Number of outgoing gRPC calls by method and status code.",
+		}, []string{"method", "code"}),
+		grpcErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "grpc_client_errors_total",
+			Help:      "Number of failed outgoing gRPC calls by method and status code.",
+		}, []string{"method", "code"}),
+		grpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "grpc_client_request_duration_seconds",
+			Help:      "Outgoing gRPC call latency by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.operations,
+		m.operationVolume,
+		m.cacheHits,
+		m.cacheMisses,
+		m.cacheRefreshErrors,
+		m.cacheRefreshDuration,
+		m.grpcRequests,
+		m.grpcErrors,
+		m.grpcDuration,
+	)
+
+	return m
+}
+
+// Registry реестр метрик, в котором можно зарегистрировать собственные коллекторы
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// Handler отдаёт метрики в формате Prometheus
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGinMiddleware_LabelsByRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := New("test")
+
+	router := gin.New()
+	router.Use(m.GinMiddleware())
+	router.GET("/wallets/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, path := range []string{"/wallets/1", "/wallets/2", "/unknown"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.httpRequests.WithLabelValues("/wallets/:id", http.MethodGet, "200")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.httpRequests.WithLabelValues(unmatchedRoute, http.MethodGet, "404")))
+}
+
+func TestObserveOperation(t *testing.T) {
+	m := New("test")
+
+	m.ObserveOperation("deposit", "USD", decimal.RequireFromString("10.50"))
+	m.ObserveOperation("deposit", "USD", decimal.RequireFromString("4.50"))
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(m.operations.WithLabelValues("deposit", "USD")))
+	assert.Equal(t, float64(15), testutil.ToFloat64(m.operationVolume.WithLabelValues("deposit", "USD")))
+}
+
+func TestCacherObserver(t *testing.T) {
+	m := New("test")
+	o := m.CacherObserver("rates")
+
+	o.Hit()
+	o.Miss()
+	o.Refreshed(time.Millisecond, errors.New("unavailable"))
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.cacheHits.WithLabelValues("rates")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.cacheMisses.WithLabelValues("rates")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.cacheRefreshErrors.WithLabelValues("rates")))
+}
+
+func TestUnaryClientInterceptor_CountsErrors(t *testing.T) {
+	m := New("test")
+	method := "/exchange.ExchangeService/GetExchangeRates"
+
+	ok := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return nil
+	}
+	unavailable := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return status.Error(codes.Unavailable, "connection refused")
+	}
+
+	assert.NoError(t, m.UnaryClientInterceptor(t.Context(), method, nil, nil, nil, ok))
+	assert.Error(t, m.UnaryClientInterceptor(t.Context(), method, nil, nil, nil, unavailable))
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.grpcRequests.WithLabelValues(method, "OK")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(m.grpcErrors.WithLabelValues(method, "Unavailable")))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.grpcErrors))
+}
+
+func TestHandler_ExposesMetrics(t *testing.T) {
+	m := New("test")
+	m.ObserveOperation("withdraw", "EUR", decimal.NewFromInt(1))
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, strings.Contains(rec.Body.String(), `test_operations_total{currency="EUR",operation="withdraw"} 1`))
+	assert.True(t, strings.Contains(rec.Body.String(), "go_goroutines"))
+}
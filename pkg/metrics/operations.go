@@ -0,0 +1,9 @@
+package metrics
+
+import "gw-currency-wallet/pkg"
+
+// ObserveOperation учитывает проведённую операцию со средствами и её сумму
+func (m *Metrics) ObserveOperation(operation string, currency pkg.Currency, amount pkg.Amount) {
+	m.operations.WithLabelValues(operation, currency).Inc()
+	m.operationVolume.WithLabelValues(operation, currency).Add(amount.InexactFloat64())
+}
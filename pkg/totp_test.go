@@ -0,0 +1,62 @@
+package pkg
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Секрет и ожидаемые значения из приложения B RFC 6238 (SHA1), последние 6 цифр
+var rfc6238Secret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestTOTPCode_RFC6238Vectors(t *testing.T) {
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for unix, expected := range vectors {
+		code, err := TOTPCode(rfc6238Secret, TOTPStep(time.Unix(unix, 0)))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, code, "time %d", unix)
+	}
+}
+
+func TestValidateTOTP_AcceptsAdjacentStepOnce(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	previous, err := TOTPCode(rfc6238Secret, TOTPStep(now)-1)
+	assert.NoError(t, err)
+
+	step, ok := ValidateTOTP(rfc6238Secret, previous, now, 0)
+	assert.True(t, ok)
+	assert.Equal(t, TOTPStep(now)-1, step)
+
+	// Повторное использование того же кода отклоняется
+	_, ok = ValidateTOTP(rfc6238Secret, previous, now, step)
+	assert.False(t, ok)
+}
+
+func TestValidateTOTP_RejectsDistantCode(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	old, err := TOTPCode(rfc6238Secret, TOTPStep(now)-5)
+	assert.NoError(t, err)
+
+	_, ok := ValidateTOTP(rfc6238Secret, old, now, 0)
+	assert.False(t, ok)
+}
+
+func TestTOTPURI_ContainsParameters(t *testing.T) {
+	uri := TOTPURI("gw-wallet", "alice@example.com", "JBSWY3DPEHPK3PXP")
+
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/gw-wallet:alice@example.com?"))
+	assert.Contains(t, uri, "secret=JBSWY3DPEHPK3PXP")
+	assert.Contains(t, uri, "issuer=gw-wallet")
+	assert.Contains(t, uri, "digits=6")
+}
@@ -1,13 +1,21 @@
 package pkg
 
+import "github.com/shopspring/decimal"
+
+// AmountScale количество знаков после запятой, с которым хранятся денежные суммы
+const AmountScale int32 = 8
+
 // Currency код валюты, например "USD", "EUR", "JPY"
 type Currency = string
 
+// Amount денежная сумма с фиксированной точностью
+type Amount = decimal.Decimal
+
 // Rate курс валюты относительно базовой валюты
-type Rate = float32
+type Rate = decimal.Decimal
 
 // ExchangeRates — карта валютных курсов
 type ExchangeRates = map[Currency]Rate
 
 // AccountWallets - состояние баланса кошельков
-type AccountWallets = map[Currency]float32
+type AccountWallets = map[Currency]Amount
@@ -5,16 +5,22 @@ import (
 	"encoding/json"
 	"fmt"
 	"gw-currency-wallet/config"
+	"gw-currency-wallet/internal/dto"
 	"gw-currency-wallet/internal/handler"
+	"gw-currency-wallet/internal/mailer"
 	gw_grpc "gw-currency-wallet/internal/pb/exchange"
 	"gw-currency-wallet/internal/repository"
 	"gw-currency-wallet/internal/service"
 	"math/rand"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
 	"testing"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
@@ -70,15 +76,16 @@ func TestE2E(t *testing.T) {
 	exchangeClient := gw_grpc.NewExchangeServiceClient(grpcConn)
 
 	r := repository.NewRepository(pool)
-	s := service.NewService(ctx, r, &cfg.Auth, exchangeClient)
-	h := handler.NewHandler(s)
+	mailFile := filepath.Join(t.TempDir(), "mail.log")
+	s := service.NewService(ctx, r, &cfg.Auth, &cfg.RateLimit, exchangeClient, mailer.NewFileMailer(mailFile), nil)
+	h := handler.NewHandler(s, &cfg.Server, nil)
 
 	router := h.Router()
 
 	// 1. Регистрация пользователя
 	username := fmt.Sprintf("user%d", rand.Intn(1000000))
 	email := fmt.Sprintf("%s@example.com", username)
-	password := "password123"
+	password := "correct-horse-42"
 	regBody := map[string]string{
 		"username": username,
 		"password": password,
@@ -91,6 +98,14 @@ func TestE2E(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "User registered successfully", regResp["message"])
 
+	// Подтверждение email токеном из письма
+	mail, err := os.ReadFile(mailFile)
+	assert.NoError(t, err)
+	match := regexp.MustCompile(`confirm your email: (\S+)`).FindSubmatch(mail)
+	assert.Len(t, match, 2)
+	rr = doPost(t, router, "/api/v1/verify-email", map[string]string{"token": string(match[1])}, "")
+	assert.Equal(t, http.StatusOK, rr.Code)
+
 	// 2. Авторизация пользователя (login)
 	loginBody := map[string]string{
 		"username": username,
@@ -107,7 +122,7 @@ func TestE2E(t *testing.T) {
 
 	// 3. Пополнение счета (deposit)
 	depositBody := map[string]interface{}{
-		"amount":   100.0,
+		"amount":   "100",
 		"currency": "USD",
 	}
 	rr = doPost(t, router, "/api/v1/wallet/deposit", depositBody, token)
@@ -120,17 +135,16 @@ func TestE2E(t *testing.T) {
 	// 4. Получение баланса (balance)
 	rr = doGet(t, router, "/api/v1/balance", token)
 	assert.Equal(t, http.StatusOK, rr.Code)
-	var balanceResp map[string]map[string]float64
+	var balanceResp dto.GetWalletsResponse
 	err = json.Unmarshal(rr.Body.Bytes(), &balanceResp)
 	assert.NoError(t, err)
-	balance, ok := balanceResp["balance"]
-	assert.True(t, ok)
-	assert.NotEmpty(t, balance)
-	assert.GreaterOrEqual(t, balance["USD"], 100.0)
+	assert.NotEmpty(t, balanceResp.Balance)
+	assert.True(t, balanceResp.Balance["USD"].Available.GreaterThanOrEqual(decimal.NewFromInt(100)))
+	assert.True(t, balanceResp.Balance["USD"].Held.IsZero())
 
 	// 5. Вывод средств (withdraw)
 	withdrawBody := map[string]interface{}{
-		"amount":   50.0,
+		"amount":   "50",
 		"currency": "USD",
 	}
 	rr = doPost(t, router, "/api/v1/wallet/withdraw", withdrawBody, token)
@@ -143,7 +157,7 @@ func TestE2E(t *testing.T) {
 	// 6. Получение курса валют (exchange rates)
 	rr = doGet(t, router, "/api/v1/exchange/rates", token)
 	assert.Equal(t, http.StatusOK, rr.Code)
-	var ratesResp map[string]map[string]float64
+	var ratesResp map[string]map[string]decimal.Decimal
 	err = json.Unmarshal(rr.Body.Bytes(), &ratesResp)
 	assert.NoError(t, err)
 	rates, ok := ratesResp["rates"]
@@ -151,7 +165,7 @@ func TestE2E(t *testing.T) {
 	assert.NotEmpty(t, rates)
 
 	// 7. Обмен валют (exchange)
-	exchangeAmount := 10.0
+	exchangeAmount := decimal.NewFromInt(10)
 	exchangeBody := map[string]interface{}{
 		"from_currency": "USD",
 		"to_currency":   "EUR",
@@ -159,13 +173,30 @@ func TestE2E(t *testing.T) {
 	}
 	rr = doPost(t, router, "/api/v1/exchange", exchangeBody, token)
 	assert.Equal(t, http.StatusOK, rr.Code)
-	var exchangeResp map[string]interface{}
+	var exchangeResp dto.ExchangeResponse
 	err = json.Unmarshal(rr.Body.Bytes(), &exchangeResp)
 	assert.NoError(t, err)
-	assert.Equal(t, "Exchange successful", exchangeResp["message"])
-	assert.NotEmpty(t, exchangeResp["exchanged_amount"])
-	expectedExchanged := exchangeAmount * rates["EUR"] / rates["USD"]
-	assert.Equal(t, expectedExchanged, exchangeResp["exchanged_amount"])
+	assert.Equal(t, "Exchange successful", exchangeResp.Message)
+	// Сумма округляется до точности валюты получения: у EUR два знака после запятой
+	expectedExchanged := exchangeAmount.Mul(rates["EUR"].Div(rates["USD"])).RoundDown(2)
+	assert.Equal(t, expectedExchanged.String(), exchangeResp.ExchangedAmount.String())
+
+	// 8. История операций (transactions)
+	rr = doGet(t, router, "/api/v1/wallet/transactions?limit=2", token)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	var historyResp dto.GetTransactionsResponse
+	err = json.Unmarshal(rr.Body.Bytes(), &historyResp)
+	assert.NoError(t, err)
+	assert.Len(t, historyResp.Transactions, 2)
+	assert.Equal(t, "exchange", historyResp.Transactions[0].Type)
+	assert.NotEmpty(t, historyResp.NextCursor)
+
+	rr = doGet(t, router, "/api/v1/wallet/transactions?cursor="+historyResp.NextCursor, token)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	err = json.Unmarshal(rr.Body.Bytes(), &historyResp)
+	assert.NoError(t, err)
+	assert.Len(t, historyResp.Transactions, 1)
+	assert.Equal(t, "deposit", historyResp.Transactions[0].Type)
 }
 
 func doPost(t *testing.T, handler http.Handler, url string, body interface{}, token string) *httptest.ResponseRecorder {